@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/google/uuid"
+	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
+
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/db"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/usecases"
+)
+
+func apiKeysCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "apikeys",
+		Usage: "manage API keys for the admin API",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "create",
+				Usage: "create a key; it is printed once and only its hash is stored",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "name", Required: true, Usage: "`NAME` describing who uses the key"},
+				},
+				Action: withDatabase(func(c *cli.Context, gormDb *gorm.DB) error {
+					apiKey, key, err := usecases.NewCreateAPIKeyUseCase(db.NewGormAPIKeyRepository(gormDb)).Create(c.Context, c.String("name"))
+					if err != nil {
+						return err
+					}
+
+					fmt.Printf("Created API key %s (%s)\n", apiKey.ID, apiKey.Name)
+					fmt.Printf("Key: %s\n", key)
+					fmt.Println("Store it now, it cannot be shown again.")
+					return nil
+				}),
+			},
+			{
+				Name:  "list",
+				Usage: "list API keys",
+				Action: withDatabase(func(c *cli.Context, gormDb *gorm.DB) error {
+					apiKeys, err := usecases.NewListAPIKeysUseCase(db.NewGormAPIKeyRepository(gormDb)).List(c.Context)
+					if err != nil {
+						return err
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+					fmt.Fprintln(w, "ID\tNAME\tPREFIX\tCREATED AT\tLAST USED AT\tREVOKED AT")
+					for _, k := range apiKeys {
+						fmt.Fprintf(w, "%s\t%s\t%s…\t%s\t%s\t%s\n", k.ID, k.Name, k.Prefix, k.CreatedAt.Format(timeFormat), formatOptionalTime(k.LastUsedAt), formatOptionalTime(k.RevokedAt))
+					}
+					return w.Flush()
+				}),
+			},
+			{
+				Name:      "revoke",
+				Usage:     "revoke an API key",
+				ArgsUsage: "<id>",
+				Action: withDatabase(func(c *cli.Context, gormDb *gorm.DB) error {
+					if !c.Args().Present() {
+						return errors.New("api key id is required")
+					}
+
+					id, err := uuid.Parse(c.Args().First())
+					if err != nil {
+						return fmt.Errorf("invalid api key id: %w", err)
+					}
+
+					if err := usecases.NewRevokeAPIKeyUseCase(db.NewGormAPIKeyRepository(gormDb)).Revoke(c.Context, id); err != nil {
+						return err
+					}
+
+					fmt.Printf("Revoked API key %s\n", id)
+					return nil
+				}),
+			},
+		},
+	}
+}
@@ -40,7 +40,7 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Confirmation token",
+                        "description": "Confirmation token or signed link token",
                         "name": "token",
                         "in": "path",
                         "required": true
@@ -57,7 +57,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid token format or missing token",
+                        "description": "Missing token or invalid link",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -73,13 +73,213 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "410": {
+                        "description": "Confirmation link expired",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/forecast": {
+            "get": {
+                "description": "Get a multi-day weather forecast for a specific city",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "weather"
+                ],
+                "summary": "Get weather forecast by city",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "City name",
+                        "name": "city",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of days (1-7, default 3)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Forecast information",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Forecast"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request or missing city parameter",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "City not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/pause/{token}": {
+            "post": {
+                "description": "Pause a subscription until the given date, or indefinitely when until is omitted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Pause weather updates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pause link token or unsubscribe token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pause end",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/http.PauseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscription paused",
+                        "schema": {
+                            "$ref": "#/definitions/http.PauseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid link or pause end",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Pause link expired",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/resume/{token}": {
+            "post": {
+                "description": "Resume a paused subscription",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Resume weather updates",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pause link token or unsubscribe token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscription resumed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid link",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Subscription is not paused",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Pause link expired",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
         "/subscribe": {
             "post": {
-                "description": "Subscribe to weather updates for a specific city and frequency",
+                "description": "Subscribe to weather updates for a specific city and frequency.\nUse frequency \"alert\" together with an \"alert\" rule to be notified only when the rule starts to hold.\nDaily digests are sent at \"send_hour\" (default 12) in \"timezone\", which defaults to the city's timezone.\nSet \"webhook_url\" to receive digests as JSON POSTs signed with HMAC-SHA256 in the X-Webhook-Signature header instead of email.",
                 "consumes": [
                     "application/json"
                 ],
@@ -130,7 +330,62 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "Subscription already exists",
+                        "description": "Subscription already exists or is awaiting confirmation",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Email address bounced or reported spam and is suppressed",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/subscribe/resend": {
+            "post": {
+                "description": "Issue a new confirmation token for an unconfirmed subscription and email it again.\nThe previous confirmation link stops working. Unknown, confirmed, suppressed and\nrecently resent subscriptions get the same response as a sent email, so the\nendpoint cannot reveal who is subscribed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscription"
+                ],
+                "summary": "Resend confirmation email",
+                "parameters": [
+                    {
+                        "description": "Subscription to confirm",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.ResendConfirmationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Confirmation email sent if a pending subscription exists",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -157,7 +412,7 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Unsubscribe token",
+                        "description": "Unsubscribe token or signed link token",
                         "name": "token",
                         "in": "path",
                         "required": true
@@ -174,7 +429,7 @@ const docTemplate = `{
                         }
                     },
                     "400": {
-                        "description": "Invalid token format or missing token",
+                        "description": "Missing token or invalid link",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -246,6 +501,37 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "domain.Forecast": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ForecastDay"
+                    }
+                }
+            }
+        },
+        "domain.ForecastDay": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "max_temperature": {
+                    "type": "number"
+                },
+                "min_temperature": {
+                    "type": "number"
+                },
+                "precipitation_chance": {
+                    "type": "number"
+                }
+            }
+        },
         "domain.Weather": {
             "type": "object",
             "properties": {
@@ -260,6 +546,75 @@ const docTemplate = `{
                 }
             }
         },
+        "http.AlertRuleRequest": {
+            "type": "object",
+            "required": [
+                "comparator",
+                "metric",
+                "threshold"
+            ],
+            "properties": {
+                "comparator": {
+                    "type": "string",
+                    "enum": [
+                        "lt",
+                        "lte",
+                        "gt",
+                        "gte"
+                    ]
+                },
+                "cooldown_minutes": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "metric": {
+                    "type": "string",
+                    "enum": [
+                        "temperature",
+                        "humidity",
+                        "precipitation_chance"
+                    ]
+                },
+                "threshold": {
+                    "type": "number"
+                }
+            }
+        },
+        "http.PauseRequest": {
+            "type": "object",
+            "properties": {
+                "until": {
+                    "type": "string",
+                    "example": "2025-07-01"
+                }
+            }
+        },
+        "http.PauseResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "paused_until": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.ResendConfirmationRequest": {
+            "type": "object",
+            "required": [
+                "city",
+                "email"
+            ],
+            "properties": {
+                "city": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
         "http.SubscribeRequest": {
             "type": "object",
             "required": [
@@ -268,6 +623,9 @@ const docTemplate = `{
                 "frequency"
             ],
             "properties": {
+                "alert": {
+                    "$ref": "#/definitions/http.AlertRuleRequest"
+                },
                 "city": {
                     "type": "string"
                 },
@@ -278,8 +636,21 @@ const docTemplate = `{
                     "type": "string",
                     "enum": [
                         "hourly",
-                        "daily"
+                        "daily",
+                        "alert"
                     ]
+                },
+                "send_hour": {
+                    "type": "integer",
+                    "maximum": 23,
+                    "minimum": 0
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "webhook_url": {
+                    "description": "WebhookURL is an https URL that receives hourly and daily digests as\nsigned JSON instead of email.",
+                    "type": "string"
                 }
             }
         }
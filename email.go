@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	config "github.com/danik-tro/weather-subscriber/pkg"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/db"
+)
+
+func emailCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "email",
+		Usage: "check email delivery",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "test",
+				Usage: "send a test email through the configured email transport",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "to", Required: true, Usage: "recipient `ADDRESS`"},
+				},
+				Action: withConfig(runEmailTest),
+			},
+		},
+	}
+}
+
+func runEmailTest(c *cli.Context, config *config.Config) error {
+	to := c.String("to")
+
+	body := fmt.Sprintf(
+		"<p>This is a test email from Weather Subscriber sent at %s via the %s transport.</p>",
+		time.Now().UTC().Format(timeFormat), config.EmailTransport,
+	)
+
+	gormDb, err := db.NewGormConnection(config)
+	if err != nil {
+		return err
+	}
+
+	sqlDb, err := gormDb.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDb.Close()
+
+	// Built like serve's, so a suppressed address is refused here too.
+	emailService, _, closeEmailService, err := newFilteredEmailService(config, db.NewGormSuppressionRepository(gormDb))
+	if err != nil {
+		return err
+	}
+	defer closeEmailService()
+
+	if err := emailService.SendMessage(c.Context, to, "Weather Subscriber test email", body, nil); err != nil {
+		return err
+	}
+
+	fmt.Printf("Sent test email to %s\n", to)
+	return nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/urfave/cli/v2"
+
+	config "github.com/danik-tro/weather-subscriber/pkg"
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/db"
+	smtp "github.com/danik-tro/weather-subscriber/pkg/infrastructure/email_service"
+	events "github.com/danik-tro/weather-subscriber/pkg/infrastructure/events"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/tokens"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/usecases"
+	"github.com/danik-tro/weather-subscriber/templates"
+)
+
+const jobsRunUsage = "job must be one of: daily, hourly, alerts, resume, purge, bounces"
+
+func jobsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "jobs",
+		Usage: "run scheduled jobs on demand",
+		Subcommands: []*cli.Command{
+			{
+				Name: "run",
+				Usage: "run a job once; subscriptions already sent for the current period " +
+					"are skipped, so this is safe alongside the scheduler",
+				ArgsUsage: "daily | hourly | alerts | resume | purge | bounces",
+				Action:    withConfig(runJob),
+			},
+		},
+	}
+}
+
+func runJob(c *cli.Context, config *config.Config) error {
+	job := c.Args().First()
+	switch job {
+	case "daily", "hourly", "alerts", "resume", "purge", "bounces":
+	default:
+		return errors.New(jobsRunUsage)
+	}
+
+	gormDb, err := db.NewGormConnection(config)
+	if err != nil {
+		return err
+	}
+
+	sqlDb, err := gormDb.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDb.Close()
+
+	switch job {
+	case "resume":
+		// The resumed notifications go through the outbox, so they are sent
+		// by the server rather than by this command.
+		resumed, err := usecases.NewResumeExpiredPausesUseCase(db.NewGormRepository(gormDb)).ResumeExpired(c.Context)
+		if err != nil {
+			return fmt.Errorf("%s job failed: %w", job, err)
+		}
+
+		fmt.Printf("Resumed %d subscriptions\n", resumed)
+		return nil
+	case "purge":
+		purged, err := usecases.NewPurgeUnconfirmedSubscriptionsUseCase(db.NewGormRepository(gormDb), config.UnconfirmedRetention()).Purge(c.Context)
+		if err != nil {
+			return fmt.Errorf("%s job failed: %w", job, err)
+		}
+
+		fmt.Printf("Purged %d unconfirmed subscriptions\n", purged)
+		return nil
+	case "bounces":
+		if config.BounceMailboxDir == "" {
+			return errors.New("BOUNCE_MAILBOX_DIR is not set")
+		}
+
+		suppressed, err := usecases.NewProcessBounceMailboxUseCase(db.NewGormSuppressionRepository(gormDb), config.BounceMailboxDir).ProcessMailbox(c.Context)
+		if err != nil {
+			return fmt.Errorf("%s job failed: %w", job, err)
+		}
+
+		fmt.Printf("Suppressed %d email addresses\n", suppressed)
+		return nil
+	}
+
+	weatherService, err := newWeatherService(config)
+	if err != nil {
+		return err
+	}
+
+	repository := db.NewGormRepository(gormDb)
+	unsubscribeTokens := tokens.NewHMACUnsubscribeTokens(config.UnsubscribeTokenSecret)
+	if _, err := usecases.NewBackfillUnsubscribeTokensUseCase(repository, unsubscribeTokens).Backfill(c.Context); err != nil {
+		return err
+	}
+
+	actionLinks, err := tokens.NewHMACActionLinks(config.ActionLinkKeys)
+	if err != nil {
+		return fmt.Errorf("invalid ACTION_LINK_KEYS: %w", err)
+	}
+
+	emailTransport, err := newEmailService(config)
+	if err != nil {
+		return err
+	}
+	if closer, ok := emailTransport.(io.Closer); ok {
+		defer closer.Close()
+	}
+	emailService := smtp.NewSuppressionFilter(emailTransport, db.NewGormSuppressionRepository(gormDb))
+
+	templateSet, err := templates.Load(config.TemplatesDir)
+	if err != nil {
+		return err
+	}
+
+	handler := events.Handler{
+		EmailService:   emailService,
+		WeatherService: weatherService,
+		Repository:     repository,
+		Deliveries:     db.NewGormDeliveryRepository(gormDb),
+		Config:         *config,
+		Templates:      templateSet,
+		ActionLinks:    actionLinks,
+		DigestWorkers:  config.DigestWorkers,
+		Webhooks:       newWebhookChannel(config, db.NewGormWebhookEndpointRepository(gormDb)),
+	}
+
+	var run domain.EventHandler
+	switch job {
+	case "daily":
+		run = handler.FetchAndUpdateWeatherSubscribers(entity.FrequencyDaily)
+	case "hourly":
+		run = handler.FetchAndUpdateWeatherSubscribers(entity.FrequencyHourly)
+	case "alerts":
+		run = handler.EvaluateAlertSubscriptions()
+	}
+
+	if err := run(c.Context, domain.Event{Type: domain.WeatherEvent}); err != nil {
+		return fmt.Errorf("%s job failed: %w", job, err)
+	}
+
+	fmt.Printf("Finished %s job\n", job)
+	return nil
+}
@@ -1,20 +1,15 @@
 package main
 
 import (
-	"context"
-	"fmt"
 	"log"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
 
 	config "github.com/danik-tro/weather-subscriber/pkg"
-	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
-	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
-	"github.com/danik-tro/weather-subscriber/pkg/external/weather"
-	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/background_job"
 	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/db"
-	smtp "github.com/danik-tro/weather-subscriber/pkg/infrastructure/email_service"
-	events "github.com/danik-tro/weather-subscriber/pkg/infrastructure/events"
-	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/usecases"
-	"github.com/danik-tro/weather-subscriber/pkg/presenter/http"
 
 	_ "github.com/danik-tro/weather-subscriber/docs"
 )
@@ -23,6 +18,11 @@ const (
 	workers     = 10
 	bufferSize  = 100
 	redisPrefix = "weather_app"
+
+	outboxPollInterval = 2 * time.Second
+	outboxBatchSize    = 50
+
+	timeFormat = "2006-01-02 15:04:05 MST"
 )
 
 // @title			Weather Service
@@ -38,95 +38,58 @@ const (
 // @BasePath		/api
 // @schemes		http https
 func main() {
-	config, err := config.LoadConfig(".")
-	if err != nil {
+	if err := newApp().Run(os.Args); err != nil {
 		log.Fatal(err)
 	}
+}
 
-	publisher := events.NewPublisher(workers, bufferSize)
-
-	weatherClient := weather.NewWeatherAPIClient(config.WeatherAPIKey)
-	weatherCache, err := weather.NewWeatherCache(config.RedisAddress, config.RedisPassword, config.RedisDB, redisPrefix)
-
-	if err != nil {
-		log.Fatal(err)
+// newApp builds the command line interface. Running the binary without a
+// command starts the server, so existing deployments keep working.
+func newApp() *cli.App {
+	return &cli.App{
+		Name:  "weather-subscriber",
+		Usage: "weather subscription service and admin tools",
+		Commands: []*cli.Command{
+			serveCommand(),
+			migrateCommand(),
+			subscribersCommand(),
+			jobsCommand(),
+			emailCommand(),
+			weatherCommand(),
+			apiKeysCommand(),
+		},
+		Action: withConfig(func(c *cli.Context, config *config.Config) error {
+			return runServe(config)
+		}),
 	}
+}
 
-	gormDb, err := db.NewGormConnection(config)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	repository := db.NewGormRepository(gormDb)
-
-	if config.DBAutoMigrate {
-		err = repository.EnsureSchema()
+// withConfig loads the configuration before running action.
+func withConfig(action func(c *cli.Context, config *config.Config) error) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		config, err := config.LoadConfig(".")
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
-	}
-
-	weatherService := weather.NewWeatherService(weatherClient, weatherCache)
-
-	getWeatherUC := usecases.NewGetWeatherUseCase(*weatherService)
-	subscribeUC := usecases.NewSubscribeWeatherUseCase(repository, publisher, *weatherService)
-	confirmUC := usecases.NewConfirmSubscription(repository)
-	unsubscribeUC := usecases.NewUnsubscribeUseCase(repository)
-	checkTokensUC := usecases.NewCheckTokens(repository)
-
-	router := http.NewRouter(*config, subscribeUC, getWeatherUC, confirmUC, unsubscribeUC, checkTokensUC)
-
-	smtpConfig := smtp.SMTPConfig{
-		Host:     config.SMTPHost,
-		Port:     config.SMTPPort,
-		Username: config.SMTPUsername,
-		Password: config.SMTPPassword,
-		From:     config.SMTPFrom,
-	}
-	emailService := smtp.NewSmtpService(smtpConfig)
-
-	handler := events.Handler{
-		EmailService:   emailService,
-		WeatherService: weatherService,
-		Repository:     repository,
-		Config:         *config,
-	}
 
-	publisher.Register(domain.UserSubscribed, handler.UserSubscribed())
-	publisher.Register(domain.WeatherEvent, handler.WeatherEvent())
-
-	backgroundJobService := background_job.NewCronBackgroundJobService()
-	if err := backgroundJobService.Start(); err != nil {
-		log.Fatal(err)
+		return action(c, config)
 	}
-	defer backgroundJobService.Stop()
+}
 
-	if err := backgroundJobService.AddJob("0 0 12 * * *", func() {
-		event := domain.Event{
-			Type: domain.WeatherEvent,
-		}
-		if err := handler.FetchAndUpdateWeatherSubscribers(entity.FrequencyDaily)(context.Background(), event); err != nil {
-			log.Printf("Failed to process daily weather updates: %v", err)
+// withDatabase opens a database connection for the duration of action.
+func withDatabase(action func(c *cli.Context, gormDb *gorm.DB) error) cli.ActionFunc {
+	return withConfig(func(c *cli.Context, config *config.Config) error {
+		gormDb, err := db.NewGormConnection(config)
+		if err != nil {
+			return err
 		}
-	}); err != nil {
-		log.Fatal(err)
-	}
 
-	if err := backgroundJobService.AddJob("0 0 * * * *", func() {
-		event := domain.Event{
-			Type: domain.WeatherEvent,
-		}
-		if err := handler.FetchAndUpdateWeatherSubscribers(entity.FrequencyHourly)(context.Background(), event); err != nil {
-			log.Printf("Failed to process hourly weather updates: %v", err)
+		sqlDb, err := gormDb.DB()
+		if err != nil {
+			return err
 		}
-	}); err != nil {
-		log.Fatal(err)
-	}
-
-	publisher.Start()
-	defer publisher.Close()
+		defer sqlDb.Close()
 
-	if err := router.Run(fmt.Sprintf("%s:%d", config.AppHost, config.AppPort)); err != nil {
-		log.Fatal(err)
-	}
+		return action(c, gormDb)
+	})
 }
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
+
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/db"
+)
+
+func migrateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "migrate",
+		Usage: "apply, roll back or inspect database migrations",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "up",
+				Usage: "apply all pending migrations",
+				Action: withMigrator(func(c *cli.Context, migrator *db.Migrator) error {
+					applied, err := migrator.Up(c.Context)
+					if err != nil {
+						return err
+					}
+					fmt.Printf("Applied %d migrations\n", applied)
+					return nil
+				}),
+			},
+			{
+				Name:      "down",
+				Usage:     "roll back the last applied migrations",
+				ArgsUsage: "[steps]",
+				Action: withMigrator(func(c *cli.Context, migrator *db.Migrator) error {
+					steps := 1
+					if c.Args().Present() {
+						var err error
+						steps, err = strconv.Atoi(c.Args().First())
+						if err != nil || steps < 1 {
+							return errors.New("steps must be a positive number")
+						}
+					}
+
+					rolledBack, err := migrator.Down(c.Context, steps)
+					if err != nil {
+						return err
+					}
+					fmt.Printf("Rolled back %d migrations\n", rolledBack)
+					return nil
+				}),
+			},
+			{
+				Name:  "status",
+				Usage: "list migrations and when they were applied",
+				Action: withMigrator(func(c *cli.Context, migrator *db.Migrator) error {
+					statuses, err := migrator.Status(c.Context)
+					if err != nil {
+						return err
+					}
+
+					w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+					fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+					for _, status := range statuses {
+						appliedAt := "pending"
+						if status.AppliedAt != nil {
+							appliedAt = status.AppliedAt.Format(timeFormat)
+						}
+						fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+					}
+					return w.Flush()
+				}),
+			},
+		},
+	}
+}
+
+// withMigrator opens a database connection for the duration of action.
+func withMigrator(action func(c *cli.Context, migrator *db.Migrator) error) cli.ActionFunc {
+	return withDatabase(func(c *cli.Context, gormDb *gorm.DB) error {
+		sqlDb, err := gormDb.DB()
+		if err != nil {
+			return err
+		}
+
+		migrator, err := db.NewMigrator(sqlDb)
+		if err != nil {
+			return err
+		}
+
+		return action(c, migrator)
+	})
+}
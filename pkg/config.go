@@ -2,7 +2,9 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
@@ -20,7 +22,10 @@ type Config struct {
 	RedisPassword string `mapstructure:"REDIS_PASSWORD"`
 	RedisDB       int    `mapstructure:"REDIS_DB"`
 
-	WeatherAPIKey string `mapstructure:"WEATHER_API_KEY"`
+	WeatherAPIKey            string `mapstructure:"WEATHER_API_KEY"`
+	OpenWeatherMapAPIKey     string `mapstructure:"OPENWEATHERMAP_API_KEY"`
+	WeatherProvider          string `mapstructure:"WEATHER_PROVIDER"`
+	WeatherFallbackProviders string `mapstructure:"WEATHER_FALLBACK_PROVIDERS"`
 
 	SMTPHost     string `mapstructure:"SMTP_HOST"`
 	SMTPPort     int    `mapstructure:"SMTP_PORT"`
@@ -28,11 +33,71 @@ type Config struct {
 	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
 	SMTPFrom     string `mapstructure:"SMTP_FROM"`
 
+	// SMTPPoolSize bounds the SMTP connections kept open between messages.
+	SMTPPoolSize    int           `mapstructure:"SMTP_POOL_SIZE"`
+	SMTPIdleTimeout time.Duration `mapstructure:"SMTP_IDLE_TIMEOUT"`
+	// SMTPRateLimit and SMTPDomainRateLimit cap messages per second overall
+	// and per recipient domain; zero means unlimited.
+	SMTPRateLimit       float64 `mapstructure:"SMTP_RATE_LIMIT"`
+	SMTPDomainRateLimit float64 `mapstructure:"SMTP_DOMAIN_RATE_LIMIT"`
+
+	// EmailTransport is smtp, http (a SendGrid-style mail API), ses or file.
+	EmailTransport string `mapstructure:"EMAIL_TRANSPORT"`
+	// EmailFrom is the sender address; it defaults to SMTP_FROM.
+	EmailFrom          string `mapstructure:"EMAIL_FROM"`
+	EmailAPIURL        string `mapstructure:"EMAIL_API_URL"`
+	EmailAPIKey        string `mapstructure:"EMAIL_API_KEY"`
+	SESRegion          string `mapstructure:"SES_REGION"`
+	SESAccessKeyID     string `mapstructure:"SES_ACCESS_KEY_ID"`
+	SESSecretAccessKey string `mapstructure:"SES_SECRET_ACCESS_KEY"`
+	SESEndpoint        string `mapstructure:"SES_ENDPOINT"`
+	EmailFileDir       string `mapstructure:"EMAIL_FILE_DIR"`
+
+	// EmailWebhookSecret authenticates bounce and complaint webhooks; the
+	// webhook is disabled while it is unset.
+	EmailWebhookSecret string `mapstructure:"EMAIL_WEBHOOK_SECRET"`
+	// BounceMailboxDir is scanned for bounce messages when set.
+	BounceMailboxDir string `mapstructure:"BOUNCE_MAILBOX_DIR"`
+
+	// WebhookSigningSecret signs digests posted to subscriber webhooks;
+	// webhook delivery is disabled while it is unset.
+	WebhookSigningSecret string        `mapstructure:"WEBHOOK_SIGNING_SECRET"`
+	WebhookTimeout       time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+	WebhookMaxAttempts   int           `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	// WebhookDisableAfter is how many failed deliveries in a row disable a
+	// webhook endpoint.
+	WebhookDisableAfter int `mapstructure:"WEBHOOK_DISABLE_AFTER"`
+
+	// TemplatesDir holds .html files that replace the built-in page and
+	// email templates of the same name; it is unset by default.
+	TemplatesDir string `mapstructure:"TEMPLATES_DIR"`
+
 	BaseURL    string `mapstructure:"BASE_URL"`
 	SwaggerURL string `mapstructure:"SWAGGER_URL"`
 
 	AppHost string `mapstructure:"APP_HOST"`
 	AppPort int    `mapstructure:"APP_PORT"`
+
+	ManageTokenSecret string        `mapstructure:"MANAGE_TOKEN_SECRET"`
+	ManageLinkTTL     time.Duration `mapstructure:"MANAGE_LINK_TTL"`
+
+	UnsubscribeTokenSecret string `mapstructure:"UNSUBSCRIBE_TOKEN_SECRET"`
+
+	// ActionLinkKeys is a comma-separated list of id:secret pairs. The first
+	// key signs new links and all of them verify.
+	ActionLinkKeys string        `mapstructure:"ACTION_LINK_KEYS"`
+	ActionLinkTTL  time.Duration `mapstructure:"ACTION_LINK_TTL"`
+
+	ConfirmationTokenTTL     time.Duration `mapstructure:"CONFIRMATION_TOKEN_TTL"`
+	UnconfirmedRetentionDays int           `mapstructure:"UNCONFIRMED_RETENTION_DAYS"`
+
+	InstanceID    string `mapstructure:"INSTANCE_ID"`
+	JobLock       string `mapstructure:"JOB_LOCK"`
+	DigestWorkers int    `mapstructure:"DIGEST_WORKERS"`
+
+	EventBus            string `mapstructure:"EVENT_BUS"`
+	EventStreamGroup    string `mapstructure:"EVENT_STREAM_GROUP"`
+	EventStreamConsumer string `mapstructure:"EVENT_STREAM_CONSUMER"`
 }
 
 func (c *Config) GetDSN() string {
@@ -42,6 +107,40 @@ func (c *Config) GetDSN() string {
 	)
 }
 
+// WeatherProviders returns the primary provider followed by the fallbacks,
+// lowercased and without duplicates.
+func (c *Config) WeatherProviders() []string {
+	seen := map[string]bool{}
+	providers := []string{}
+
+	candidates := append([]string{c.WeatherProvider}, strings.Split(c.WeatherFallbackProviders, ",")...)
+	for _, name := range candidates {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		providers = append(providers, name)
+	}
+
+	return providers
+}
+
+// UnconfirmedRetention is how long an unconfirmed subscription is kept after
+// its last confirmation email.
+func (c *Config) UnconfirmedRetention() time.Duration {
+	return time.Duration(c.UnconfirmedRetentionDays) * 24 * time.Hour
+}
+
+func defaultInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
 func LoadConfig(configPath string) (*Config, error) {
 	v := viper.New()
 
@@ -56,12 +155,39 @@ func LoadConfig(configPath string) (*Config, error) {
 	v.SetDefault("REDIS_ADDRESS", "localhost:6379")
 	v.SetDefault("REDIS_DB", 0)
 
+	v.SetDefault("WEATHER_PROVIDER", "weatherapi")
+	v.SetDefault("WEATHER_FALLBACK_PROVIDERS", "openmeteo")
+
+	v.SetDefault("JOB_LOCK", "postgres")
+	v.SetDefault("DIGEST_WORKERS", 10)
+
+	v.SetDefault("EVENT_BUS", "memory")
+	v.SetDefault("EVENT_STREAM_GROUP", "weather_subscriber")
+
+	v.SetDefault("SMTP_POOL_SIZE", 4)
+	v.SetDefault("SMTP_IDLE_TIMEOUT", 30*time.Second)
+	v.SetDefault("SMTP_RATE_LIMIT", 0)
+	v.SetDefault("SMTP_DOMAIN_RATE_LIMIT", 0)
+
+	v.SetDefault("EMAIL_TRANSPORT", "smtp")
+	v.SetDefault("EMAIL_FILE_DIR", "mail")
+
+	v.SetDefault("WEBHOOK_TIMEOUT", 10*time.Second)
+	v.SetDefault("WEBHOOK_MAX_ATTEMPTS", 3)
+	v.SetDefault("WEBHOOK_DISABLE_AFTER", 5)
+
 	v.SetDefault("BASE_URL", "http://localhost:8080")
 	v.SetDefault("SWAGGER_URL", "http://localhost:8080/swagger/doc.json")
 
 	v.SetDefault("APP_HOST", "localhost")
 	v.SetDefault("APP_PORT", 8080)
 
+	v.SetDefault("MANAGE_LINK_TTL", 30*time.Minute)
+	v.SetDefault("ACTION_LINK_TTL", 90*24*time.Hour)
+
+	v.SetDefault("CONFIRMATION_TOKEN_TTL", 24*time.Hour)
+	v.SetDefault("UNCONFIRMED_RETENTION_DAYS", 7)
+
 	if configPath != "" {
 		v.AddConfigPath(configPath)
 		v.SetConfigName(".env")
@@ -80,6 +206,14 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("error unmarshaling config: %w", err)
 	}
 
+	if config.InstanceID == "" {
+		config.InstanceID = defaultInstanceID()
+	}
+
+	if config.EmailFrom == "" {
+		config.EmailFrom = config.SMTPFrom
+	}
+
 	if err := validateConfig(&config); err != nil {
 		return nil, err
 	}
@@ -90,8 +224,105 @@ func LoadConfig(configPath string) (*Config, error) {
 func validateConfig(config *Config) error {
 	missingFields := []string{}
 
-	if config.WeatherAPIKey == "" {
-		missingFields = append(missingFields, "WEATHER_API_KEY")
+	for _, provider := range config.WeatherProviders() {
+		switch provider {
+		case "weatherapi":
+			if config.WeatherAPIKey == "" {
+				missingFields = append(missingFields, "WEATHER_API_KEY")
+			}
+		case "openweathermap":
+			if config.OpenWeatherMapAPIKey == "" {
+				missingFields = append(missingFields, "OPENWEATHERMAP_API_KEY")
+			}
+		}
+	}
+
+	switch config.EmailTransport {
+	case "smtp", "file":
+	case "http":
+		if config.EmailAPIKey == "" {
+			missingFields = append(missingFields, "EMAIL_API_KEY")
+		}
+	case "ses":
+		if config.SESRegion == "" {
+			missingFields = append(missingFields, "SES_REGION")
+		}
+		if config.SESAccessKeyID == "" {
+			missingFields = append(missingFields, "SES_ACCESS_KEY_ID")
+		}
+		if config.SESSecretAccessKey == "" {
+			missingFields = append(missingFields, "SES_SECRET_ACCESS_KEY")
+		}
+	default:
+		return fmt.Errorf("unsupported EMAIL_TRANSPORT %q, expected smtp, http, ses or file", config.EmailTransport)
+	}
+
+	if config.SMTPPoolSize <= 0 {
+		return fmt.Errorf("SMTP_POOL_SIZE must be positive, got %d", config.SMTPPoolSize)
+	}
+
+	if config.SMTPIdleTimeout <= 0 {
+		return fmt.Errorf("SMTP_IDLE_TIMEOUT must be positive, got %s", config.SMTPIdleTimeout)
+	}
+
+	if config.SMTPRateLimit < 0 {
+		return fmt.Errorf("SMTP_RATE_LIMIT must not be negative, got %g", config.SMTPRateLimit)
+	}
+
+	if config.SMTPDomainRateLimit < 0 {
+		return fmt.Errorf("SMTP_DOMAIN_RATE_LIMIT must not be negative, got %g", config.SMTPDomainRateLimit)
+	}
+
+	if config.WebhookTimeout <= 0 {
+		return fmt.Errorf("WEBHOOK_TIMEOUT must be positive, got %s", config.WebhookTimeout)
+	}
+
+	if config.WebhookMaxAttempts <= 0 {
+		return fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must be positive, got %d", config.WebhookMaxAttempts)
+	}
+
+	if config.WebhookDisableAfter <= 0 {
+		return fmt.Errorf("WEBHOOK_DISABLE_AFTER must be positive, got %d", config.WebhookDisableAfter)
+	}
+
+	if config.ManageTokenSecret == "" {
+		missingFields = append(missingFields, "MANAGE_TOKEN_SECRET")
+	}
+
+	if config.UnsubscribeTokenSecret == "" {
+		missingFields = append(missingFields, "UNSUBSCRIBE_TOKEN_SECRET")
+	}
+
+	if config.ActionLinkKeys == "" {
+		missingFields = append(missingFields, "ACTION_LINK_KEYS")
+	}
+
+	if config.ManageLinkTTL <= 0 {
+		return fmt.Errorf("MANAGE_LINK_TTL must be positive, got %s", config.ManageLinkTTL)
+	}
+
+	if config.ActionLinkTTL <= 0 {
+		return fmt.Errorf("ACTION_LINK_TTL must be positive, got %s", config.ActionLinkTTL)
+	}
+
+	if config.ConfirmationTokenTTL < 0 {
+		return fmt.Errorf("CONFIRMATION_TOKEN_TTL must not be negative, got %s", config.ConfirmationTokenTTL)
+	}
+
+	if config.UnconfirmedRetentionDays <= 0 {
+		return fmt.Errorf("UNCONFIRMED_RETENTION_DAYS must be positive, got %d", config.UnconfirmedRetentionDays)
+	}
+
+	switch config.JobLock {
+	case "none", "postgres", "redis":
+	default:
+		return fmt.Errorf("unsupported JOB_LOCK %q, expected none, postgres or redis", config.JobLock)
+	}
+
+	switch config.EventBus {
+	case "memory", "redis":
+	default:
+		return fmt.Errorf("unsupported EVENT_BUS %q, expected memory or redis", config.EventBus)
 	}
 
 	if len(missingFields) > 0 {
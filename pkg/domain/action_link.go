@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type LinkAction string
+
+const (
+	ConfirmLinkAction     LinkAction = "confirm"
+	UnsubscribeLinkAction LinkAction = "unsubscribe"
+	// PauseLinkAction covers the pause page and its pause and resume forms.
+	PauseLinkAction LinkAction = "pause"
+)
+
+var (
+	ErrInvalidActionLink = errors.New("link is invalid")
+	ErrActionLinkExpired = errors.New("link has expired")
+)
+
+// ActionLink is what a signed email link grants: one action on one
+// subscription until ExpiresAt.
+type ActionLink struct {
+	SubscriptionID uuid.UUID
+	Action         LinkAction
+	IssuedAt       time.Time
+	ExpiresAt      time.Time
+}
+
+// ActionLinks signs and verifies the tokens carried by confirm, unsubscribe
+// and pause links. Verification needs no database lookup.
+type ActionLinks interface {
+	Sign(link ActionLink) string
+	// Verify returns the link the token was signed for. It fails with
+	// ErrInvalidActionLink when the token is malformed, forged, signed with
+	// an unknown key or for another action, and with ErrActionLinkExpired
+	// once it has expired. Unsubscribe links never expire.
+	Verify(token string, action LinkAction) (*ActionLink, error)
+}
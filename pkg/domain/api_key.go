@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	apiKeyPrefix      = "wsk_"
+	apiKeyBytes       = 32
+	apiKeyDisplaySize = len(apiKeyPrefix) + 8
+)
+
+// APIKeyUsageInterval is how stale LastUsedAt may get before a request
+// updates it, so authenticating does not write on every call.
+const APIKeyUsageInterval = time.Minute
+
+var ErrInvalidAPIKey = errors.New("invalid or missing API key")
+var ErrAPIKeyNotFound = errors.New("api key not found")
+
+// APIKey grants access to the admin API. Only the SHA-256 hash of the key is
+// stored; Prefix keeps its first characters so keys can be told apart.
+type APIKey struct {
+	ID         uuid.UUID
+	Name       string
+	Prefix     string
+	KeyHash    string
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// NewAPIKey generates a key and returns it together with the plain text
+// value, which is not stored and cannot be recovered later.
+func NewAPIKey(name string) (*APIKey, string, error) {
+	secret := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return &APIKey{
+		ID:        uuid.New(),
+		Name:      name,
+		Prefix:    key[:apiKeyDisplaySize],
+		KeyHash:   HashAPIKey(key),
+		CreatedAt: time.Now().UTC(),
+	}, key, nil
+}
+
+// HashAPIKey returns the hex encoded SHA-256 of key. Keys are random, so a
+// plain hash is enough to make a leaked table useless.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func (k *APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// UsageStale reports whether LastUsedAt should be updated for a use at now.
+func (k *APIKey) UsageStale(now time.Time) bool {
+	return k.LastUsedAt == nil || now.Sub(*k.LastUsedAt) >= APIKeyUsageInterval
+}
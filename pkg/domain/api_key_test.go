@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAPIKey(t *testing.T) {
+	apiKey, key, err := NewAPIKey("ops")
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(key, apiKeyPrefix))
+	assert.True(t, strings.HasPrefix(key, apiKey.Prefix))
+	assert.Equal(t, HashAPIKey(key), apiKey.KeyHash)
+	assert.NotContains(t, apiKey.KeyHash, key)
+	assert.False(t, apiKey.Revoked())
+
+	_, other, err := NewAPIKey("ops")
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestAPIKey_UsageStale(t *testing.T) {
+	now := time.Now().UTC()
+	apiKey := &APIKey{}
+	assert.True(t, apiKey.UsageStale(now))
+
+	lastUsedAt := now.Add(-APIKeyUsageInterval / 2)
+	apiKey.LastUsedAt = &lastUsedAt
+	assert.False(t, apiKey.UsageStale(now))
+
+	lastUsedAt = now.Add(-APIKeyUsageInterval)
+	assert.True(t, apiKey.UsageStale(now))
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrDeadLetterNotFound = errors.New("dead letter not found")
+var ErrDeadLetterAlreadyReplayed = errors.New("dead letter already replayed")
+
+// DeadLetter is an event whose handlers kept failing after every retry. It
+// keeps the encoded payload so the event can be replayed once the cause is
+// fixed.
+type DeadLetter struct {
+	ID         uuid.UUID
+	EventType  EventType
+	Payload    []byte
+	Error      string
+	Attempts   int
+	CreatedAt  time.Time
+	ReplayedAt *time.Time
+}
+
+func NewDeadLetter(eventType EventType, payload []byte, reason string, attempts int) *DeadLetter {
+	return &DeadLetter{
+		ID:        uuid.New(),
+		EventType: eventType,
+		Payload:   payload,
+		Error:     reason,
+		Attempts:  attempts,
+		CreatedAt: time.Now().UTC(),
+	}
+}
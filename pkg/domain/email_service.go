@@ -1,7 +1,62 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrEmailTransient wraps send failures that may succeed when retried,
+	// such as timeouts, throttling or a provider outage.
+	ErrEmailTransient = errors.New("transient email failure")
+	// ErrEmailPermanent wraps send failures that will not succeed unchanged,
+	// such as a rejected recipient or invalid credentials.
+	ErrEmailPermanent = errors.New("permanent email failure")
+)
+
+// EmailHeaders are extra message headers, keyed by header name.
+type EmailHeaders map[string]string
+
+const (
+	ListUnsubscribeHeader     = "List-Unsubscribe"
+	ListUnsubscribePostHeader = "List-Unsubscribe-Post"
+	// ListUnsubscribeOneClick is the List-Unsubscribe-Post value, and the
+	// form body mail providers POST to the unsubscribe URL (RFC 8058).
+	ListUnsubscribeOneClick = "List-Unsubscribe=One-Click"
+)
+
+// ListUnsubscribeHeaders lets mail clients offer a one-click unsubscribe
+// that POSTs to unsubscribeURL.
+func ListUnsubscribeHeaders(unsubscribeURL string) EmailHeaders {
+	return EmailHeaders{
+		ListUnsubscribeHeader:     "<" + unsubscribeURL + ">",
+		ListUnsubscribePostHeader: ListUnsubscribeOneClick,
+	}
+}
+
+var ErrEmailPoolStatsUnavailable = errors.New("the email transport does not keep a connection pool")
+
+// EmailPoolStats is a snapshot of a pooled email transport.
+type EmailPoolStats struct {
+	MaxConnections int
+	Open           int
+	Idle           int
+	Dials          int64
+	DialFailures   int64
+	Sent           int64
+	Failed         int64
+	// Throttled counts messages delayed by a rate limit.
+	Throttled int64
+}
+
+// EmailPool is implemented by email transports that keep connections open
+// between messages.
+type EmailPool interface {
+	PoolStats() EmailPoolStats
+}
 
 type EmailService interface {
-	SendMessage(ctx context.Context, recipient string, subject string, body string) error
+	// SendMessage sends an HTML message; headers may be nil. Failures wrap
+	// ErrEmailTransient or ErrEmailPermanent.
+	SendMessage(ctx context.Context, recipient string, subject string, body string, headers EmailHeaders) error
 }
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	value_object "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+)
+
+type AlertMetric string
+
+const (
+	AlertMetricTemperature         AlertMetric = "TEMPERATURE"
+	AlertMetricHumidity            AlertMetric = "HUMIDITY"
+	AlertMetricPrecipitationChance AlertMetric = "PRECIPITATION_CHANCE"
+)
+
+type AlertComparator string
+
+const (
+	ComparatorLessThan           AlertComparator = "LT"
+	ComparatorLessThanOrEqual    AlertComparator = "LTE"
+	ComparatorGreaterThan        AlertComparator = "GT"
+	ComparatorGreaterThanOrEqual AlertComparator = "GTE"
+)
+
+const (
+	DefaultAlertCooldown = 3 * time.Hour
+	MaxAlertCooldown     = 7 * 24 * time.Hour
+)
+
+var ErrInvalidAlertRule = errors.New("invalid alert rule")
+
+// AlertRule describes a threshold condition that an alert subscription
+// watches for, e.g. "temperature LT 0".
+type AlertRule struct {
+	Metric     AlertMetric
+	Comparator AlertComparator
+	Threshold  float64
+	Cooldown   time.Duration
+}
+
+func (r AlertRule) Validate() error {
+	switch r.Metric {
+	case AlertMetricTemperature:
+		if r.Threshold < -100 || r.Threshold > 100 {
+			return fmt.Errorf("%w: temperature threshold must be between -100 and 100", ErrInvalidAlertRule)
+		}
+	case AlertMetricHumidity, AlertMetricPrecipitationChance:
+		if r.Threshold < 0 || r.Threshold > 100 {
+			return fmt.Errorf("%w: %s threshold must be between 0 and 100", ErrInvalidAlertRule, r.Metric)
+		}
+	default:
+		return fmt.Errorf("%w: unknown metric %q", ErrInvalidAlertRule, r.Metric)
+	}
+
+	switch r.Comparator {
+	case ComparatorLessThan, ComparatorLessThanOrEqual, ComparatorGreaterThan, ComparatorGreaterThanOrEqual:
+	default:
+		return fmt.Errorf("%w: unknown comparator %q", ErrInvalidAlertRule, r.Comparator)
+	}
+
+	if r.Cooldown < 0 || r.Cooldown > MaxAlertCooldown {
+		return fmt.Errorf("%w: cooldown must be between 0 and %s", ErrInvalidAlertRule, MaxAlertCooldown)
+	}
+
+	return nil
+}
+
+// Matches reports whether value satisfies the rule's condition.
+func (r AlertRule) Matches(value float64) bool {
+	switch r.Comparator {
+	case ComparatorLessThan:
+		return value < r.Threshold
+	case ComparatorLessThanOrEqual:
+		return value <= r.Threshold
+	case ComparatorGreaterThan:
+		return value > r.Threshold
+	case ComparatorGreaterThanOrEqual:
+		return value >= r.Threshold
+	default:
+		return false
+	}
+}
+
+// Evaluate applies the rule to value. triggered is the new rule state;
+// notify is true only when the rule transitions from false to true and the
+// cooldown since lastSentAt has elapsed.
+func (r AlertRule) Evaluate(value float64, wasTriggered bool, lastSentAt *time.Time, now time.Time) (triggered bool, notify bool) {
+	triggered = r.Matches(value)
+
+	if !triggered || wasTriggered {
+		return triggered, false
+	}
+
+	if lastSentAt != nil && now.Sub(*lastSentAt) < r.Cooldown {
+		return triggered, false
+	}
+
+	return triggered, true
+}
+
+// Value extracts the rule's metric from the current conditions and today's
+// forecast. today may be nil when the metric does not need it.
+func (m AlertMetric) Value(weather value_object.Weather, today *value_object.ForecastDay) (float64, error) {
+	switch m {
+	case AlertMetricTemperature:
+		return weather.Temperature, nil
+	case AlertMetricHumidity:
+		return weather.Humidity, nil
+	case AlertMetricPrecipitationChance:
+		if today == nil {
+			return 0, fmt.Errorf("forecast is required for %s", m)
+		}
+		return today.PrecipitationChance, nil
+	default:
+		return 0, fmt.Errorf("%w: unknown metric %q", ErrInvalidAlertRule, m)
+	}
+}
+
+// NeedsForecast reports whether the metric is taken from the daily forecast
+// rather than current conditions.
+func (m AlertMetric) NeedsForecast() bool {
+	return m == AlertMetricPrecipitationChance
+}
+
+func (c AlertComparator) Symbol() string {
+	switch c {
+	case ComparatorLessThan:
+		return "<"
+	case ComparatorLessThanOrEqual:
+		return "≤"
+	case ComparatorGreaterThan:
+		return ">"
+	case ComparatorGreaterThanOrEqual:
+		return "≥"
+	default:
+		return string(c)
+	}
+}
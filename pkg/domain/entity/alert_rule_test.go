@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	value_object "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+)
+
+func TestAlertRule_Validate(t *testing.T) {
+	valid := AlertRule{Metric: AlertMetricTemperature, Comparator: ComparatorLessThan, Threshold: 0, Cooldown: time.Hour}
+	require.NoError(t, valid.Validate())
+
+	cases := map[string]AlertRule{
+		"unknown metric":        {Metric: "WIND", Comparator: ComparatorLessThan},
+		"unknown comparator":    {Metric: AlertMetricTemperature, Comparator: "EQ"},
+		"humidity out of range": {Metric: AlertMetricHumidity, Comparator: ComparatorGreaterThan, Threshold: 120},
+		"negative cooldown":     {Metric: AlertMetricTemperature, Comparator: ComparatorLessThan, Cooldown: -time.Minute},
+		"cooldown too long":     {Metric: AlertMetricTemperature, Comparator: ComparatorLessThan, Cooldown: MaxAlertCooldown + time.Hour},
+	}
+
+	for name, rule := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.ErrorIs(t, rule.Validate(), ErrInvalidAlertRule)
+		})
+	}
+}
+
+func TestAlertRule_Matches(t *testing.T) {
+	cases := []struct {
+		comparator AlertComparator
+		value      float64
+		want       bool
+	}{
+		{ComparatorLessThan, -1, true},
+		{ComparatorLessThan, 0, false},
+		{ComparatorLessThanOrEqual, 0, true},
+		{ComparatorGreaterThan, 0, false},
+		{ComparatorGreaterThan, 0.1, true},
+		{ComparatorGreaterThanOrEqual, 0, true},
+		{ComparatorGreaterThanOrEqual, -0.1, false},
+	}
+
+	for _, tc := range cases {
+		rule := AlertRule{Metric: AlertMetricTemperature, Comparator: tc.comparator, Threshold: 0}
+		require.Equal(t, tc.want, rule.Matches(tc.value), "%s %v", tc.comparator, tc.value)
+	}
+}
+
+func TestAlertRule_Evaluate(t *testing.T) {
+	now := time.Now().UTC()
+	rule := AlertRule{Metric: AlertMetricTemperature, Comparator: ComparatorLessThan, Threshold: 0, Cooldown: time.Hour}
+
+	// false -> true notifies
+	triggered, notify := rule.Evaluate(-5, false, nil, now)
+	require.True(t, triggered)
+	require.True(t, notify)
+
+	// true -> true does not notify again
+	triggered, notify = rule.Evaluate(-6, true, &now, now.Add(2*time.Hour))
+	require.True(t, triggered)
+	require.False(t, notify)
+
+	// true -> false resets the state silently
+	triggered, notify = rule.Evaluate(3, true, &now, now)
+	require.False(t, triggered)
+	require.False(t, notify)
+
+	// false -> true inside the cooldown is suppressed but still recorded
+	lastSent := now.Add(-30 * time.Minute)
+	triggered, notify = rule.Evaluate(-1, false, &lastSent, now)
+	require.True(t, triggered)
+	require.False(t, notify)
+
+	// false -> true after the cooldown notifies
+	lastSent = now.Add(-2 * time.Hour)
+	triggered, notify = rule.Evaluate(-1, false, &lastSent, now)
+	require.True(t, triggered)
+	require.True(t, notify)
+}
+
+func TestAlertMetric_Value(t *testing.T) {
+	weather := value_object.Weather{Temperature: -2, Humidity: 70}
+	today := &value_object.ForecastDay{PrecipitationChance: 85}
+
+	value, err := AlertMetricTemperature.Value(weather, nil)
+	require.NoError(t, err)
+	require.Equal(t, -2.0, value)
+
+	value, err = AlertMetricHumidity.Value(weather, nil)
+	require.NoError(t, err)
+	require.Equal(t, 70.0, value)
+
+	value, err = AlertMetricPrecipitationChance.Value(weather, today)
+	require.NoError(t, err)
+	require.Equal(t, 85.0, value)
+
+	_, err = AlertMetricPrecipitationChance.Value(weather, nil)
+	require.Error(t, err)
+}
+
+func TestNewAlertSubscription(t *testing.T) {
+	rule := AlertRule{Metric: AlertMetricHumidity, Comparator: ComparatorGreaterThan, Threshold: 70}
+
+	s, err := NewAlertSubscription("test@example.com", "Kyiv", rule)
+	require.NoError(t, err)
+	require.Equal(t, FrequencyAlert, s.Frequency)
+	require.Equal(t, &rule, s.AlertRule)
+	require.False(t, s.AlertTriggered)
+
+	_, err = NewAlertSubscription("test@example.com", "Kyiv", AlertRule{Metric: "WIND"})
+	require.ErrorIs(t, err, ErrInvalidAlertRule)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MinConfirmationResendInterval throttles how often a new confirmation email
+// can be requested for the same subscription.
+const MinConfirmationResendInterval = time.Minute
+
+var ErrConfirmationExpired = errors.New("confirmation token has expired")
+var ErrAlreadyConfirmed = errors.New("subscription is already confirmed")
+var ErrConfirmationResendTooSoon = errors.New("confirmation email was sent recently, try again later")
+
+// ConfirmationExpired reports whether the confirmation token was issued more
+// than ttl ago. A non-positive ttl means tokens never expire.
+func (s *Subscription) ConfirmationExpired(now time.Time, ttl time.Duration) bool {
+	if ttl <= 0 {
+		return false
+	}
+
+	return now.Sub(s.ConfirmationSentAt) > ttl
+}
+
+// RotateConfirmationToken replaces the confirmation token of an unconfirmed
+// subscription, invalidating the one sent before.
+func (s *Subscription) RotateConfirmationToken(now time.Time) error {
+	if s.Confirmed {
+		return ErrAlreadyConfirmed
+	}
+
+	if now.Sub(s.ConfirmationSentAt) < MinConfirmationResendInterval {
+		return ErrConfirmationResendTooSoon
+	}
+
+	token, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+
+	s.ConfirmationToken = token.String()
+	s.ConfirmationTokenHash = HashToken(s.ConfirmationToken)
+	s.ConfirmationSentAt = now.UTC()
+
+	return nil
+}
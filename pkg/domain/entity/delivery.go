@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	DefaultTimezone = "UTC"
+	DefaultSendHour = 12
+)
+
+var ErrInvalidDeliveryPreferences = errors.New("invalid delivery preferences")
+
+// DeliveryPreferences controls when and where scheduled digests reach a
+// subscriber. An empty Timezone means "derive it from the subscribed city"
+// and an empty WebhookURL means digests are emailed.
+type DeliveryPreferences struct {
+	Timezone   string
+	SendHour   int
+	WebhookURL string
+}
+
+func DefaultDeliveryPreferences() DeliveryPreferences {
+	return DeliveryPreferences{SendHour: DefaultSendHour}
+}
+
+func (p DeliveryPreferences) Validate() error {
+	if p.Timezone != "" {
+		if _, err := time.LoadLocation(p.Timezone); err != nil {
+			return fmt.Errorf("%w: unknown timezone %q", ErrInvalidDeliveryPreferences, p.Timezone)
+		}
+	}
+
+	if p.SendHour < 0 || p.SendHour > 23 {
+		return fmt.Errorf("%w: send hour must be between 0 and 23", ErrInvalidDeliveryPreferences)
+	}
+
+	if p.WebhookURL != "" {
+		target, err := url.Parse(p.WebhookURL)
+		if err != nil || target.Scheme != "https" || target.Host == "" {
+			return fmt.Errorf("%w: webhook URL must be an absolute https URL", ErrInvalidDeliveryPreferences)
+		}
+	}
+
+	return nil
+}
+
+// loadLocation returns the subscriber's timezone, falling back to UTC when
+// it is empty or unknown to the system tz database.
+func loadLocation(timezone string) *time.Location {
+	if timezone == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// LastScheduledAt returns the most recent send slot at or before now for
+// the given frequency: the start of the current hour for hourly digests and
+// sendHour:00 local time for daily ones.
+func LastScheduledAt(frequency Frequency, timezone string, sendHour int, now time.Time) time.Time {
+	if frequency == FrequencyHourly {
+		return now.Truncate(time.Hour)
+	}
+
+	local := now.In(loadLocation(timezone))
+
+	slot := time.Date(local.Year(), local.Month(), local.Day(), sendHour, 0, 0, 0, local.Location())
+	if local.Before(slot) {
+		slot = slot.AddDate(0, 0, -1)
+	}
+
+	return slot
+}
+
+// IsDue reports whether the subscriber's latest send slot has passed since
+// the last digest was sent (or since the subscription was confirmed).
+func (s Subscriber) IsDue(now time.Time) bool {
+	reference := s.LastSentAt
+	if reference == nil {
+		reference = s.ConfirmedAt
+	}
+
+	if reference == nil {
+		return true
+	}
+
+	return reference.Before(LastScheduledAt(s.Frequency, s.Timezone, s.SendHour, now))
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending DeliveryStatus = "PENDING"
+	DeliverySent    DeliveryStatus = "SENT"
+	DeliveryFailed  DeliveryStatus = "FAILED"
+)
+
+// Delivery is a ledger entry for one scheduled digest. A subscription has at
+// most one delivery per period, which is what keeps restarts and concurrent
+// job runs from sending the same digest twice.
+type Delivery struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	Period         time.Time
+	Status         DeliveryStatus
+	Attempts       int
+	Error          string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	SentAt         *time.Time
+}
+
+// CurrentPeriod identifies the send slot now falls into, in UTC so that
+// it compares equal across processes.
+func (s Subscriber) CurrentPeriod(now time.Time) time.Time {
+	return LastScheduledAt(s.Frequency, s.Timezone, s.SendHour, now).UTC()
+}
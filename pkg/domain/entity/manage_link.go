@@ -0,0 +1,12 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// MinManageLinkInterval throttles how often a manage link can be emailed to
+// the same address.
+const MinManageLinkInterval = time.Minute
+
+var ErrManageLinkRequestedTooSoon = errors.New("manage link was sent recently, try again later")
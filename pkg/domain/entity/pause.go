@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxPauseDuration caps how far ahead a pause can be scheduled to end.
+const MaxPauseDuration = 365 * 24 * time.Hour
+
+var ErrInvalidPause = errors.New("invalid pause")
+var ErrSubscriptionNotPaused = errors.New("subscription is not paused")
+
+// IsPaused reports whether deliveries to the subscription are suspended.
+func (s *Subscription) IsPaused() bool {
+	return s.PausedAt != nil
+}
+
+// Pause suspends deliveries until the given time, or until resumed by hand
+// when until is nil. Pausing again replaces the previous end time.
+func (s *Subscription) Pause(now time.Time, until *time.Time) error {
+	if until != nil {
+		if !until.After(now) {
+			return fmt.Errorf("%w: end time must be in the future", ErrInvalidPause)
+		}
+		if until.Sub(now) > MaxPauseDuration {
+			return fmt.Errorf("%w: subscriptions can be paused for at most a year", ErrInvalidPause)
+		}
+		utc := until.UTC()
+		until = &utc
+	}
+
+	if s.PausedAt == nil {
+		pausedAt := now.UTC()
+		s.PausedAt = &pausedAt
+	}
+	s.PausedUntil = until
+
+	return nil
+}
+
+func (s *Subscription) Resume() error {
+	if !s.IsPaused() {
+		return ErrSubscriptionNotPaused
+	}
+
+	s.PausedAt = nil
+	s.PausedUntil = nil
+
+	return nil
+}
@@ -11,28 +11,59 @@ type Frequency string
 const (
 	FrequencyHourly Frequency = "HOURLY"
 	FrequencyDaily  Frequency = "DAILY"
+	FrequencyAlert  Frequency = "ALERT"
 )
 
 type Subscription struct {
-	ID                uuid.UUID
-	Email             string
-	City              string
-	Frequency         Frequency
-	ConfirmationToken string
-	UnsubscribeToken  string
-	Confirmed         bool
-	CreatedAt         time.Time
-	ConfirmedAt       *time.Time
-	LastSentAt        *time.Time
+	ID        uuid.UUID
+	Email     string
+	City      string
+	Frequency Frequency
+	// ConfirmationToken is only known when the token is issued, so that it
+	// can be emailed; subscriptions loaded from storage carry just the digest.
+	// It is left out of event payloads, which are stored in the outbox and
+	// dead letters.
+	ConfirmationToken     string `json:"-"`
+	ConfirmationTokenHash string
+	// ConfirmationSentAt is when the current confirmation token was issued.
+	ConfirmationSentAt time.Time
+	// UnsubscribeTokenHash is the digest of the token derived from the ID.
+	UnsubscribeTokenHash string
+	Confirmed            bool
+	CreatedAt            time.Time
+	ConfirmedAt          *time.Time
+	LastSentAt           *time.Time
+	Timezone             string
+	SendHour             int
+	// WebhookURL, when set, receives digests as signed JSON instead of email.
+	WebhookURL     string
+	AlertRule      *AlertRule
+	AlertTriggered bool
+	PausedAt       *time.Time
+	PausedUntil    *time.Time
+	// ManageLinkSentAt is when a manage link was last emailed to the
+	// subscription's address.
+	ManageLinkSentAt *time.Time
+	// UnsubscribedAt is set on past subscriptions that were unsubscribed;
+	// they are kept as history and never receive deliveries.
+	UnsubscribedAt *time.Time
 }
 
 type Subscriber struct {
-	Email            string
-	City             string
-	Frequency        Frequency
-	UnsubscribeToken string
+	ID          uuid.UUID
+	Email       string
+	City        string
+	Frequency   Frequency
+	Timezone    string
+	SendHour    int
+	WebhookURL  string
+	ConfirmedAt *time.Time
+	LastSentAt  *time.Time
 }
 
+// NewSubscription creates an unconfirmed subscription with a fresh
+// confirmation token. The unsubscribe token is derived from the ID with a
+// server key and its digest set with AssignUnsubscribeToken.
 func NewSubscription(email, city string, freq Frequency) (*Subscription, error) {
 	now := time.Now().UTC()
 
@@ -41,21 +72,52 @@ func NewSubscription(email, city string, freq Frequency) (*Subscription, error)
 		return nil, err
 	}
 
-	unsubscribeToken, err := uuid.NewV7()
+	return &Subscription{
+		ID:                    uuid.New(),
+		Email:                 email,
+		City:                  city,
+		Frequency:             freq,
+		ConfirmationToken:     confirmationToken.String(),
+		ConfirmationTokenHash: HashToken(confirmationToken.String()),
+		ConfirmationSentAt:    now,
+		Confirmed:             false,
+		CreatedAt:             now,
+		ConfirmedAt:           nil,
+		LastSentAt:            nil,
+		Timezone:              DefaultTimezone,
+		SendHour:              DefaultSendHour,
+	}, nil
+}
+
+// ApplyDeliveryPreferences sets the send hour and webhook URL and, when
+// given, overrides the timezone derived from the city.
+func (s *Subscription) ApplyDeliveryPreferences(prefs DeliveryPreferences) error {
+	if err := prefs.Validate(); err != nil {
+		return err
+	}
+
+	if prefs.Timezone != "" {
+		s.Timezone = prefs.Timezone
+	}
+	s.SendHour = prefs.SendHour
+	s.WebhookURL = prefs.WebhookURL
+
+	return nil
+}
+
+// NewAlertSubscription creates a subscription that is only notified when
+// rule transitions from false to true.
+func NewAlertSubscription(email, city string, rule AlertRule) (*Subscription, error) {
+	if err := rule.Validate(); err != nil {
+		return nil, err
+	}
+
+	subscription, err := NewSubscription(email, city, FrequencyAlert)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Subscription{
-		ID:                uuid.New(),
-		Email:             email,
-		City:              city,
-		Frequency:         freq,
-		ConfirmationToken: confirmationToken.String(),
-		UnsubscribeToken:  unsubscribeToken.String(),
-		Confirmed:         false,
-		CreatedAt:         now,
-		ConfirmedAt:       nil,
-		LastSentAt:        nil,
-	}, nil
+	subscription.AlertRule = &rule
+
+	return subscription, nil
 }
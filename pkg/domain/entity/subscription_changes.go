@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidFrequencyChange = errors.New("frequency can only be switched between hourly and daily")
+
+// SubscriptionChanges are the edits a subscriber can make from the manage
+// page. Empty fields are left unchanged.
+type SubscriptionChanges struct {
+	City      string
+	Frequency Frequency
+}
+
+// CityChanged reports whether applying the changes moves s to another city.
+func (c SubscriptionChanges) CityChanged(s *Subscription) bool {
+	return c.City != "" && !strings.EqualFold(c.City, s.City)
+}
+
+// Apply updates s in place. Alert subscriptions keep their frequency, since
+// switching them to a digest would drop the alert rule.
+func (c SubscriptionChanges) Apply(s *Subscription) error {
+	if c.Frequency != "" && c.Frequency != s.Frequency {
+		if !isDigestFrequency(s.Frequency) || !isDigestFrequency(c.Frequency) {
+			return ErrInvalidFrequencyChange
+		}
+		s.Frequency = c.Frequency
+	}
+
+	if c.CityChanged(s) {
+		s.City = c.City
+		s.AlertTriggered = false
+	}
+
+	return nil
+}
+
+func isDigestFrequency(f Frequency) bool {
+	return f == FrequencyHourly || f == FrequencyDaily
+}
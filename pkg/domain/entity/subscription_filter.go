@@ -0,0 +1,11 @@
+package domain
+
+// SubscriptionFilter narrows subscription listings. Zero values match
+// everything; Limit 0 means no limit.
+type SubscriptionFilter struct {
+	City      string
+	Frequency Frequency
+	Confirmed *bool
+	Limit     int
+	Offset    int
+}
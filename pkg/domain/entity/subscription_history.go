@@ -0,0 +1,32 @@
+package domain
+
+// SubscriptionHistory lists every subscription an email address has had to a
+// city, oldest first, including those that were unsubscribed.
+type SubscriptionHistory struct {
+	Email         string
+	City          string
+	Subscriptions []*Subscription
+}
+
+// Current returns the subscription that is still active, if any.
+func (h *SubscriptionHistory) Current() *Subscription {
+	for _, s := range h.Subscriptions {
+		if s.UnsubscribedAt == nil {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// Unsubscribes counts how many times the address unsubscribed from the city.
+func (h *SubscriptionHistory) Unsubscribes() int {
+	count := 0
+	for _, s := range h.Subscriptions {
+		if s.UnsubscribedAt != nil {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,24 @@
+package domain
+
+// SubscriptionStats aggregates active (not unsubscribed) subscriptions.
+type SubscriptionStats struct {
+	Total       int64
+	Confirmed   int64
+	ByFrequency map[Frequency]int64
+	ByCity      []CityStats
+}
+
+type CityStats struct {
+	City      string
+	Total     int64
+	Confirmed int64
+}
+
+// ConfirmationRate is the share of subscriptions that were confirmed, from
+// 0 to 1.
+func (s SubscriptionStats) ConfirmationRate() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Confirmed) / float64(s.Total)
+}
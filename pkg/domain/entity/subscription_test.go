@@ -26,15 +26,17 @@ func TestNewSubscription(t *testing.T) {
 	_, err := uuid.Parse(s.ID.String())
 	require.NoError(t, err)
 
-	// Check tokens are valid UUIDs and not equal
+	// Check the confirmation token is a valid UUID stored by its digest
 	confUUID, err := uuid.Parse(s.ConfirmationToken)
 	require.NoError(t, err)
 	require.NotEqual(t, uuid.Nil, confUUID)
+	require.Equal(t, HashToken(s.ConfirmationToken), s.ConfirmationTokenHash)
 
-	unsubUUID, err := uuid.Parse(s.UnsubscribeToken)
-	require.NoError(t, err)
-	require.NotEqual(t, uuid.Nil, unsubUUID)
-	require.NotEqual(t, s.ConfirmationToken, s.UnsubscribeToken)
+	// The unsubscribe token is assigned by the caller
+	require.Empty(t, s.UnsubscribeTokenHash)
+	s.AssignUnsubscribeToken("unsubscribe-token")
+	require.Equal(t, HashToken("unsubscribe-token"), s.UnsubscribeTokenHash)
+	require.NotEqual(t, s.ConfirmationTokenHash, s.UnsubscribeTokenHash)
 
 	// Confirmed should be false by default
 	require.False(t, s.Confirmed)
@@ -47,3 +49,167 @@ func TestNewSubscription(t *testing.T) {
 	now := time.Now().UTC()
 	require.WithinDuration(t, now, s.CreatedAt, time.Second)
 }
+
+func TestSubscription_ApplyDeliveryPreferences(t *testing.T) {
+	s, err := NewSubscription("test@example.com", "Tokyo", FrequencyDaily)
+	require.NoError(t, err)
+	require.Equal(t, DefaultTimezone, s.Timezone)
+	require.Equal(t, DefaultSendHour, s.SendHour)
+
+	require.NoError(t, s.ApplyDeliveryPreferences(DeliveryPreferences{Timezone: "Asia/Tokyo", SendHour: 7}))
+	require.Equal(t, "Asia/Tokyo", s.Timezone)
+	require.Equal(t, 7, s.SendHour)
+
+	require.ErrorIs(t, s.ApplyDeliveryPreferences(DeliveryPreferences{Timezone: "Mars/Olympus", SendHour: 7}), ErrInvalidDeliveryPreferences)
+	require.ErrorIs(t, s.ApplyDeliveryPreferences(DeliveryPreferences{SendHour: 24}), ErrInvalidDeliveryPreferences)
+
+	require.NoError(t, s.ApplyDeliveryPreferences(DeliveryPreferences{SendHour: 7, WebhookURL: "https://hooks.example.com/weather"}))
+	require.Equal(t, "https://hooks.example.com/weather", s.WebhookURL)
+	require.ErrorIs(t, s.ApplyDeliveryPreferences(DeliveryPreferences{SendHour: 7, WebhookURL: "ftp://hooks.example.com"}), ErrInvalidDeliveryPreferences)
+	require.ErrorIs(t, s.ApplyDeliveryPreferences(DeliveryPreferences{SendHour: 7, WebhookURL: "http://hooks.example.com/weather"}), ErrInvalidDeliveryPreferences)
+	require.ErrorIs(t, s.ApplyDeliveryPreferences(DeliveryPreferences{SendHour: 7, WebhookURL: "/weather"}), ErrInvalidDeliveryPreferences)
+}
+
+func TestSubscriber_IsDue(t *testing.T) {
+	// 2025-06-01 03:30 UTC is 12:30 in Tokyo and 04:30 in Lisbon.
+	now := time.Date(2025, 6, 1, 3, 30, 0, 0, time.UTC)
+	confirmed := time.Date(2025, 5, 30, 0, 0, 0, 0, time.UTC)
+
+	tokyo := Subscriber{Frequency: FrequencyDaily, Timezone: "Asia/Tokyo", SendHour: 12, ConfirmedAt: &confirmed}
+	lisbon := Subscriber{Frequency: FrequencyDaily, Timezone: "Europe/Lisbon", SendHour: 12, ConfirmedAt: &confirmed}
+
+	// Lisbon's previous slot was 2025-05-31 12:00 local, already sent.
+	sentLisbon := time.Date(2025, 5, 31, 11, 0, 5, 0, time.UTC)
+	lisbon.LastSentAt = &sentLisbon
+
+	require.True(t, tokyo.IsDue(now))
+	require.False(t, lisbon.IsDue(now))
+
+	// Once sent, Tokyo is not due again until tomorrow's slot.
+	tokyo.LastSentAt = &now
+	require.False(t, tokyo.IsDue(now.Add(time.Hour)))
+	require.True(t, tokyo.IsDue(now.Add(24*time.Hour)))
+
+	// Lisbon becomes due at 12:00 local (11:00 UTC in summer).
+	require.True(t, lisbon.IsDue(time.Date(2025, 6, 1, 11, 0, 0, 0, time.UTC)))
+
+	hourly := Subscriber{Frequency: FrequencyHourly, Timezone: "Asia/Kolkata", LastSentAt: &now}
+	require.False(t, hourly.IsDue(now.Add(20*time.Minute)))
+	require.True(t, hourly.IsDue(now.Add(time.Hour)))
+}
+
+func TestSubscriber_CurrentPeriod(t *testing.T) {
+	tokyo := Subscriber{Frequency: FrequencyDaily, Timezone: "Asia/Tokyo", SendHour: 12}
+
+	// Every run between two slots maps to the same period.
+	first := tokyo.CurrentPeriod(time.Date(2025, 6, 1, 3, 5, 0, 0, time.UTC))
+	second := tokyo.CurrentPeriod(time.Date(2025, 6, 2, 2, 55, 0, 0, time.UTC))
+	require.Equal(t, time.Date(2025, 6, 1, 3, 0, 0, 0, time.UTC), first)
+	require.Equal(t, first, second)
+	require.Equal(t, time.UTC, first.Location())
+
+	next := tokyo.CurrentPeriod(time.Date(2025, 6, 2, 3, 0, 0, 0, time.UTC))
+	require.Equal(t, first.Add(24*time.Hour), next)
+}
+
+func TestSubscriptionStats_ConfirmationRate(t *testing.T) {
+	require.Equal(t, 0.0, SubscriptionStats{}.ConfirmationRate())
+	require.Equal(t, 0.25, SubscriptionStats{Total: 4, Confirmed: 1}.ConfirmationRate())
+}
+
+func TestSubscriptionChanges_Apply(t *testing.T) {
+	sub, err := NewSubscription("test@example.com", "Kyiv", FrequencyHourly)
+	require.NoError(t, err)
+
+	changes := SubscriptionChanges{City: "Lviv", Frequency: FrequencyDaily}
+	require.True(t, changes.CityChanged(sub))
+	require.NoError(t, changes.Apply(sub))
+	require.Equal(t, "Lviv", sub.City)
+	require.Equal(t, FrequencyDaily, sub.Frequency)
+
+	require.False(t, SubscriptionChanges{City: "lviv"}.CityChanged(sub))
+	require.NoError(t, SubscriptionChanges{}.Apply(sub))
+	require.Equal(t, "Lviv", sub.City)
+
+	alert, err := NewAlertSubscription("test@example.com", "Kyiv", AlertRule{
+		Metric:     AlertMetricTemperature,
+		Comparator: ComparatorLessThan,
+		Threshold:  0,
+	})
+	require.NoError(t, err)
+	alert.AlertTriggered = true
+
+	require.ErrorIs(t, SubscriptionChanges{Frequency: FrequencyDaily}.Apply(alert), ErrInvalidFrequencyChange)
+	require.ErrorIs(t, SubscriptionChanges{Frequency: FrequencyAlert}.Apply(sub), ErrInvalidFrequencyChange)
+
+	require.NoError(t, SubscriptionChanges{City: "Odesa", Frequency: FrequencyAlert}.Apply(alert))
+	require.Equal(t, "Odesa", alert.City)
+	require.False(t, alert.AlertTriggered)
+}
+
+func TestSubscription_PauseResume(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	sub, err := NewSubscription("test@example.com", "Kyiv", FrequencyDaily)
+	require.NoError(t, err)
+	require.False(t, sub.IsPaused())
+	require.ErrorIs(t, sub.Resume(), ErrSubscriptionNotPaused)
+
+	past := now.Add(-time.Hour)
+	require.ErrorIs(t, sub.Pause(now, &past), ErrInvalidPause)
+	tooFar := now.Add(MaxPauseDuration + time.Hour)
+	require.ErrorIs(t, sub.Pause(now, &tooFar), ErrInvalidPause)
+	require.False(t, sub.IsPaused())
+
+	until := now.Add(14 * 24 * time.Hour)
+	require.NoError(t, sub.Pause(now, &until))
+	require.True(t, sub.IsPaused())
+	require.Equal(t, now, *sub.PausedAt)
+	require.Equal(t, until, *sub.PausedUntil)
+
+	require.NoError(t, sub.Pause(now.Add(time.Hour), nil))
+	require.Equal(t, now, *sub.PausedAt, "pausing again keeps the original pause time")
+	require.Nil(t, sub.PausedUntil)
+
+	require.NoError(t, sub.Resume())
+	require.False(t, sub.IsPaused())
+	require.Nil(t, sub.PausedUntil)
+}
+
+func TestSubscription_ConfirmationToken(t *testing.T) {
+	sub, err := NewSubscription("test@example.com", "Kyiv", FrequencyDaily)
+	require.NoError(t, err)
+	sentAt := sub.ConfirmationSentAt
+
+	require.False(t, sub.ConfirmationExpired(sentAt.Add(23*time.Hour), 24*time.Hour))
+	require.True(t, sub.ConfirmationExpired(sentAt.Add(25*time.Hour), 24*time.Hour))
+	require.False(t, sub.ConfirmationExpired(sentAt.Add(1000*time.Hour), 0), "zero ttl never expires")
+
+	require.ErrorIs(t, sub.RotateConfirmationToken(sentAt.Add(time.Second)), ErrConfirmationResendTooSoon)
+
+	oldToken := sub.ConfirmationToken
+	later := sentAt.Add(2 * time.Hour)
+	require.NoError(t, sub.RotateConfirmationToken(later))
+	require.NotEqual(t, oldToken, sub.ConfirmationToken)
+	require.Equal(t, HashToken(sub.ConfirmationToken), sub.ConfirmationTokenHash)
+	require.Equal(t, later, sub.ConfirmationSentAt)
+	require.False(t, sub.ConfirmationExpired(later.Add(time.Hour), 24*time.Hour))
+
+	sub.Confirmed = true
+	require.ErrorIs(t, sub.RotateConfirmationToken(later.Add(time.Hour)), ErrAlreadyConfirmed)
+}
+
+func TestSubscriptionHistory(t *testing.T) {
+	unsubscribedAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	first := &Subscription{ID: uuid.New(), UnsubscribedAt: &unsubscribedAt}
+	second := &Subscription{ID: uuid.New(), UnsubscribedAt: &unsubscribedAt}
+	current := &Subscription{ID: uuid.New()}
+
+	history := &SubscriptionHistory{Email: "test@example.com", City: "Kyiv", Subscriptions: []*Subscription{first, second}}
+	require.Nil(t, history.Current())
+	require.Equal(t, 2, history.Unsubscribes())
+
+	history.Subscriptions = append(history.Subscriptions, current)
+	require.Equal(t, current, history.Current())
+	require.Equal(t, 2, history.Unsubscribes())
+}
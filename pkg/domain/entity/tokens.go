@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HashToken returns the SHA-256 digest, hex encoded, under which confirmation
+// and unsubscribe tokens are stored. The tokens themselves are never
+// persisted.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// AssignUnsubscribeToken records the digest of the token that unsubscribe and
+// pause links carry. The token itself is rebuilt whenever a link is sent.
+func (s *Subscription) AssignUnsubscribeToken(token string) {
+	s.UnsubscribeTokenHash = HashToken(token)
+}
@@ -7,8 +7,10 @@ import (
 type EventType string
 
 const (
-	UserSubscribed EventType = "user_subscribed"
-	WeatherEvent   EventType = "weather_event"
+	UserSubscribed      EventType = "user_subscribed"
+	WeatherEvent        EventType = "weather_event"
+	ManageLinkRequested EventType = "manage_link_requested"
+	SubscriptionResumed EventType = "subscription_resumed"
 )
 
 type Event struct {
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidManageToken = errors.New("manage link is invalid or has expired")
+
+// ManageTokens issues the short-lived tokens carried by "manage my
+// subscriptions" links. A token identifies the email address it was sent to.
+type ManageTokens interface {
+	Issue(email string) (token string, expiresAt time.Time, err error)
+	// Verify returns the email address the token was issued for, or
+	// ErrInvalidManageToken when it is forged, malformed or expired.
+	Verify(token string) (email string, err error)
+}
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+
+	value_objects "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+)
+
+// NotificationChannel delivers weather updates to subscribers that asked
+// for them somewhere other than their inbox. target is the channel's
+// address for the subscriber, such as a webhook URL.
+type NotificationChannel interface {
+	Deliver(ctx context.Context, target string, event value_objects.WeatherEvent) error
+}
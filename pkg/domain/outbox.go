@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxMessage is an event persisted in the same transaction as the state
+// change that produced it, so it can be published after a crash.
+type OutboxMessage struct {
+	ID          uuid.UUID
+	EventType   EventType
+	Payload     []byte
+	Attempts    int
+	LastError   string
+	CreatedAt   time.Time
+	AvailableAt time.Time
+	PublishedAt *time.Time
+}
+
+func NewOutboxMessage(eventType EventType, payload []byte) *OutboxMessage {
+	now := time.Now().UTC()
+
+	return &OutboxMessage{
+		ID:          uuid.New(),
+		EventType:   eventType,
+		Payload:     payload,
+		CreatedAt:   now,
+		AvailableAt: now,
+	}
+}
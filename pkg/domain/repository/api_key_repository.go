@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	"github.com/google/uuid"
+)
+
+type APIKeyRepository interface {
+	Save(ctx context.Context, apiKey *domain.APIKey) error
+	FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error)
+	List(ctx context.Context) ([]domain.APIKey, error)
+	MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error
+	Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	"github.com/google/uuid"
+)
+
+type DeadLetterRepository interface {
+	Save(ctx context.Context, deadLetter *domain.DeadLetter) error
+	// List returns dead letters newest first. Replayed ones are included
+	// only when includeReplayed is set.
+	List(ctx context.Context, limit, offset int, includeReplayed bool) ([]domain.DeadLetter, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*domain.DeadLetter, error)
+	// Replay marks the dead letter as replayed and enqueues message in the
+	// outbox in the same transaction.
+	Replay(ctx context.Context, id uuid.UUID, message *domain.OutboxMessage) error
+}
@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DeliveryRepository interface {
+	// Claim reserves the (subscription, period) slot for sending. It returns
+	// false when the digest was already sent, is being sent by another run
+	// that claimed it less than staleAfter ago, or has run out of attempts.
+	Claim(ctx context.Context, subscriptionID uuid.UUID, period time.Time, staleAfter time.Duration) (bool, error)
+	// MarkDelivered records a successful send and updates the subscription's
+	// LastSentAt.
+	MarkDelivered(ctx context.Context, subscriptionID uuid.UUID, period time.Time, sentAt time.Time) error
+	MarkFailed(ctx context.Context, subscriptionID uuid.UUID, period time.Time, reason string) error
+}
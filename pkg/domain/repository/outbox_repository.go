@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	"github.com/google/uuid"
+)
+
+type OutboxRepository interface {
+	// Enqueue stores a message on its own, for events that do not change a
+	// subscription.
+	Enqueue(ctx context.Context, message *domain.OutboxMessage) error
+	// ClaimPending returns up to limit unpublished messages and hides them
+	// from other relays for the lease duration.
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error)
+	MarkPublished(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string, retryAt time.Time) error
+}
@@ -2,7 +2,9 @@ package domain
 
 import (
 	"context"
+	"time"
 
+	domain_events "github.com/danik-tro/weather-subscriber/pkg/domain"
 	domain "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
 	"github.com/google/uuid"
 )
@@ -10,11 +12,43 @@ import (
 type SubscriptionRepository interface {
 	FindByConfirmationToken(ctx context.Context, token string) (*domain.Subscription, error)
 	FindByUnsubscribeToken(ctx context.Context, token string) (*domain.Subscription, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*domain.Subscription, error)
+	FindByEmail(ctx context.Context, email string) ([]*domain.Subscription, error)
+	FindByEmailAndCity(ctx context.Context, email, city string) (*domain.Subscription, error)
+	// History returns all subscriptions of email to city, oldest first,
+	// including unsubscribed ones.
+	History(ctx context.Context, email, city string) ([]*domain.Subscription, error)
+	List(ctx context.Context, filter domain.SubscriptionFilter) ([]*domain.Subscription, error)
+	// Count returns how many subscriptions match filter, ignoring Limit and
+	// Offset.
+	Count(ctx context.Context, filter domain.SubscriptionFilter) (int64, error)
+	ConfirmByID(ctx context.Context, id uuid.UUID) error
+	Stats(ctx context.Context) (*domain.SubscriptionStats, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	// DeleteUnconfirmedBefore hard-deletes unconfirmed subscriptions whose
+	// confirmation email was last sent before cutoff and returns how many
+	// were removed.
+	DeleteUnconfirmedBefore(ctx context.Context, cutoff time.Time) (int64, error)
 	Save(ctx context.Context, subscription *domain.Subscription) error
+	SaveWithOutbox(ctx context.Context, subscription *domain.Subscription, message *domain_events.OutboxMessage) error
 	IsSubscribed(ctx context.Context, email, city string) (bool, error)
 	IsComfirmationTokenExists(ctx context.Context, token string) (bool, error)
 	IsUnsubscribeTokenExists(ctx context.Context, token string) (bool, error)
 	GetConfirmedSubscriptions(ctx context.Context, frequency domain.Frequency) ([]domain.Subscriber, error)
 	Confirm(ctx context.Context, token string) error
+	GetConfirmedAlertSubscriptions(ctx context.Context) ([]*domain.Subscription, error)
+	FindExpiredPauses(ctx context.Context, now time.Time, limit int) ([]*domain.Subscription, error)
+	// ResumeWithOutbox resumes a subscription whose pause ended at or before
+	// now and enqueues message in the same transaction.
+	ResumeWithOutbox(ctx context.Context, id uuid.UUID, now time.Time, message *domain_events.OutboxMessage) error
+	// RequestManageLinkWithOutbox records that a manage link was sent to
+	// email at now and enqueues message in the same transaction. It returns
+	// ErrManageLinkRequestedTooSoon when one was sent less than
+	// MinManageLinkInterval before now.
+	RequestManageLinkWithOutbox(ctx context.Context, email string, now time.Time, message *domain_events.OutboxMessage) error
+	// FindWithoutUnsubscribeTokenHash returns up to limit IDs of
+	// subscriptions whose derived unsubscribe token digest is not stored yet.
+	FindWithoutUnsubscribeTokenHash(ctx context.Context, limit int) ([]uuid.UUID, error)
+	SetUnsubscribeTokenHash(ctx context.Context, id uuid.UUID, hash string) error
+	UpdateAlertState(ctx context.Context, id uuid.UUID, triggered bool, lastSentAt *time.Time) error
 }
@@ -0,0 +1,14 @@
+package domain
+
+import (
+	"context"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+type SuppressionRepository interface {
+	// Save adds the address to the suppression list. An address that is
+	// already suppressed keeps its original entry.
+	Save(ctx context.Context, suppression *domain.Suppression) error
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+}
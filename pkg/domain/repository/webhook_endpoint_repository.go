@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+type WebhookEndpointRepository interface {
+	Find(ctx context.Context, url string) (*domain.WebhookEndpoint, error)
+	List(ctx context.Context) ([]domain.WebhookEndpoint, error)
+	// RecordSuccess resets the endpoint's consecutive failures.
+	RecordSuccess(ctx context.Context, url string, at time.Time) error
+	// RecordFailure counts a failed delivery and disables the endpoint once
+	// it has failed disableAfter times in a row. It returns the updated
+	// endpoint.
+	RecordFailure(ctx context.Context, url, reason string, at time.Time, disableAfter int) (*domain.WebhookEndpoint, error)
+	// Enable clears the endpoint's consecutive failures and re-enables it.
+	Enable(ctx context.Context, url string) error
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy controls how often a failing event handler is retried before
+// the event is moved to the dead-letter store.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Jitter is the fraction (0-1) by which each delay is randomly
+	// shortened or lengthened, so retries from many events spread out.
+	Jitter float64
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: time.Second,
+	MaxBackoff:     time.Minute,
+	Multiplier:     2,
+	Jitter:         0.2,
+}
+
+// Backoff returns the delay before the next try after attempt failed
+// attempts (starting at 1).
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.delay(attempt)
+
+	if p.Jitter > 0 {
+		delay += delay * p.Jitter * (2*rand.Float64() - 1)
+	}
+
+	return time.Duration(delay)
+}
+
+// LongestBackoff is the upper bound of every delay Backoff can return for
+// this policy, jitter included.
+func (p RetryPolicy) LongestBackoff() time.Duration {
+	var longest float64
+	for attempt := 1; attempt < p.MaxAttempts; attempt++ {
+		longest = math.Max(longest, p.delay(attempt))
+	}
+
+	if p.Jitter > 0 {
+		longest += longest * p.Jitter
+	}
+
+	return time.Duration(longest)
+}
+
+// delay is the backoff after attempt failed attempts, before jitter.
+func (p RetryPolicy) delay(attempt int) float64 {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	delay := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+
+	return delay
+}
+
+// Exhausted reports whether no retries are left after attempt tries.
+func (p RetryPolicy) Exhausted(attempt int) bool {
+	return attempt >= p.MaxAttempts
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Second, MaxBackoff: 5 * time.Second, Multiplier: 2}
+
+	require.Equal(t, time.Second, policy.Backoff(1))
+	require.Equal(t, 2*time.Second, policy.Backoff(2))
+	require.Equal(t, 4*time.Second, policy.Backoff(3))
+	require.Equal(t, 5*time.Second, policy.Backoff(4))
+
+	policy.Jitter = 0.5
+	for range 100 {
+		delay := policy.Backoff(2)
+		require.GreaterOrEqual(t, delay, time.Second)
+		require.LessOrEqual(t, delay, 3*time.Second)
+	}
+}
+
+func TestRetryPolicy_Exhausted(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3}
+
+	require.False(t, policy.Exhausted(2))
+	require.True(t, policy.Exhausted(3))
+}
+
+func TestRetryPolicy_LongestBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: time.Minute, Multiplier: 2}
+	require.Equal(t, 2*time.Second, policy.LongestBackoff())
+
+	policy.MaxAttempts = 10
+	require.Equal(t, time.Minute, policy.LongestBackoff())
+
+	policy.Jitter = 0.5
+	require.Equal(t, 90*time.Second, policy.LongestBackoff())
+	for attempt := 1; attempt < policy.MaxAttempts; attempt++ {
+		require.LessOrEqual(t, policy.Backoff(attempt), policy.LongestBackoff())
+	}
+}
@@ -10,3 +10,4 @@ var ErrCityNotFound = errors.New("city not found")
 var ErrUnableToSubscribe = errors.New("failed to subscribe, try again later")
 var ErrBadRequest = errors.New("bad request")
 var ErrInternalServerError = errors.New("internal server error")
+var ErrSubscriptionPendingConfirmation = errors.New("subscription is awaiting confirmation")
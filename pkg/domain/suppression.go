@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrEmailSuppressed = errors.New("email address is on the suppression list")
+var ErrUnknownFeedbackProvider = errors.New("unknown email feedback provider")
+var ErrInvalidFeedbackPayload = errors.New("invalid email feedback payload")
+
+// EmailFeedbackType is what a mail provider or bounce message reports about
+// a recipient.
+type EmailFeedbackType string
+
+const (
+	HardBounceFeedback EmailFeedbackType = "hard_bounce"
+	SoftBounceFeedback EmailFeedbackType = "soft_bounce"
+	ComplaintFeedback  EmailFeedbackType = "complaint"
+)
+
+// EmailFeedback is a bounce or spam complaint for one recipient.
+type EmailFeedback struct {
+	Email  string
+	Type   EmailFeedbackType
+	Detail string
+}
+
+// Suppresses reports whether the feedback means the address must not be
+// mailed again. Soft bounces are temporary, so they do not.
+func (f EmailFeedback) Suppresses() bool {
+	return f.Type == HardBounceFeedback || f.Type == ComplaintFeedback
+}
+
+// Suppression is an address that hard-bounced or complained and is never
+// sent to again. Source is where the feedback came from, such as a webhook
+// provider or the bounce mailbox.
+type Suppression struct {
+	Email     string
+	Reason    EmailFeedbackType
+	Source    string
+	Detail    string
+	CreatedAt time.Time
+}
+
+func NewSuppression(feedback EmailFeedback, source string) *Suppression {
+	return &Suppression{
+		Email:     NormalizeEmail(feedback.Email),
+		Reason:    feedback.Type,
+		Source:    source,
+		Detail:    feedback.Detail,
+		CreatedAt: time.Now().UTC(),
+	}
+}
+
+// NormalizeEmail is the form addresses are suppressed and looked up in.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "github.com/google/uuid"
+
+// UnsubscribeTokens derives the token carried by a subscription's unsubscribe
+// and pause links. Only its digest is stored, so every email rebuilds the
+// token from the subscription ID.
+type UnsubscribeTokens interface {
+	Token(id uuid.UUID) string
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	"github.com/google/uuid"
+)
+
+type AuthenticateAPIKeyUseCase interface {
+	Authenticate(ctx context.Context, key string) (*domain.APIKey, error)
+}
+
+type CreateAPIKeyUseCase interface {
+	// Create returns the stored key and its plain text value.
+	Create(ctx context.Context, name string) (*domain.APIKey, string, error)
+}
+
+type ListAPIKeysUseCase interface {
+	List(ctx context.Context) ([]domain.APIKey, error)
+}
+
+type RevokeAPIKeyUseCase interface {
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
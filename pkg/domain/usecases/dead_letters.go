@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	"github.com/google/uuid"
+)
+
+type ListDeadLettersUseCase interface {
+	List(ctx context.Context, limit, offset int, includeReplayed bool) ([]domain.DeadLetter, error)
+}
+
+type ReplayDeadLetterUseCase interface {
+	Replay(ctx context.Context, id uuid.UUID) error
+}
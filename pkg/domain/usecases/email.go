@@ -0,0 +1,11 @@
+package domain
+
+import (
+	"context"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+type EmailPoolStatsUseCase interface {
+	Stats(ctx context.Context) (*domain.EmailPoolStats, error)
+}
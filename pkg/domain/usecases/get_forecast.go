@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"context"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+)
+
+const (
+	DefaultForecastDays = 3
+	MaxForecastDays     = 7
+)
+
+type GetForecastUseCase interface {
+	GetForecast(ctx context.Context, city string, days int) (domain.Forecast, error)
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"context"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	"github.com/google/uuid"
+)
+
+type RequestManageLinkUseCase interface {
+	// RequestLink emails a manage link to email if it has subscriptions. It
+	// succeeds either way, so the response does not reveal who subscribed.
+	RequestLink(ctx context.Context, email string) error
+}
+
+type ListManagedSubscriptionsUseCase interface {
+	// List returns the email the manage token was issued for and its
+	// subscriptions.
+	List(ctx context.Context, token string) (string, []*domain.Subscription, error)
+}
+
+type UpdateManagedSubscriptionUseCase interface {
+	Update(ctx context.Context, token string, id uuid.UUID, changes domain.SubscriptionChanges) error
+}
+
+type DeleteManagedSubscriptionUseCase interface {
+	Delete(ctx context.Context, token string, id uuid.UUID) error
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+)
+
+type GetPauseStatusUseCase interface {
+	Status(ctx context.Context, token string) (*domain.Subscription, error)
+}
+
+type PauseSubscriptionUseCase interface {
+	// Pause suspends deliveries until the given time, or indefinitely when
+	// until is nil.
+	Pause(ctx context.Context, token string, until *time.Time) (*domain.Subscription, error)
+}
+
+type ResumeSubscriptionUseCase interface {
+	Resume(ctx context.Context, token string) (*domain.Subscription, error)
+}
+
+type ResumeExpiredPausesUseCase interface {
+	// ResumeExpired resumes every subscription whose pause has ended and
+	// returns how many were resumed.
+	ResumeExpired(ctx context.Context) (int, error)
+}
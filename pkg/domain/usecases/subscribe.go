@@ -7,5 +7,18 @@ import (
 )
 
 type SubscribeWeatherUseCase interface {
-	Subscribe(ctx context.Context, email, city string, freq domain.Frequency) error
+	Subscribe(ctx context.Context, email, city string, freq domain.Frequency, prefs domain.DeliveryPreferences) error
+	SubscribeToAlert(ctx context.Context, email, city string, rule domain.AlertRule) error
+}
+
+type ResendConfirmationUseCase interface {
+	// Resend issues a new confirmation token for the unconfirmed
+	// subscription of email to city and emails it again.
+	Resend(ctx context.Context, email, city string) error
+}
+
+type PurgeUnconfirmedSubscriptionsUseCase interface {
+	// Purge deletes stale unconfirmed subscriptions and returns how many
+	// were removed.
+	Purge(ctx context.Context) (int64, error)
 }
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"context"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	"github.com/google/uuid"
+)
+
+type ListSubscriptionsUseCase interface {
+	// List returns a page of subscriptions and the total number matching
+	// the filter.
+	List(ctx context.Context, filter domain.SubscriptionFilter) ([]*domain.Subscription, int64, error)
+}
+
+type GetSubscriptionUseCase interface {
+	Get(ctx context.Context, id uuid.UUID) (*domain.Subscription, error)
+}
+
+type DeleteSubscriptionUseCase interface {
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type ForceConfirmSubscriptionUseCase interface {
+	ForceConfirm(ctx context.Context, id uuid.UUID) (*domain.Subscription, error)
+}
+
+type SubscriptionStatsUseCase interface {
+	Stats(ctx context.Context) (*domain.SubscriptionStats, error)
+}
+
+type SubscriptionHistoryUseCase interface {
+	History(ctx context.Context, email, city string) (*domain.SubscriptionHistory, error)
+}
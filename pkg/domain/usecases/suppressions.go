@@ -0,0 +1,15 @@
+package domain
+
+import "context"
+
+type ProcessEmailFeedbackUseCase interface {
+	// ProcessWebhook parses a bounce or complaint notification sent by
+	// provider and returns how many addresses it suppressed.
+	ProcessWebhook(ctx context.Context, provider string, payload []byte) (int, error)
+}
+
+type ProcessBounceMailboxUseCase interface {
+	// ProcessMailbox parses the bounce messages in the mailbox directory
+	// and returns how many addresses they suppressed.
+	ProcessMailbox(ctx context.Context) (int, error)
+}
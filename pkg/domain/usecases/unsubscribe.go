@@ -7,3 +7,9 @@ import (
 type UnsubscribeUseCase interface {
 	Unsubscribe(ctx context.Context, token string) error
 }
+
+type BackfillUnsubscribeTokensUseCase interface {
+	// Backfill stores the derived unsubscribe token digest of subscriptions
+	// created before tokens were derived, and returns how many it updated.
+	Backfill(ctx context.Context) (int, error)
+}
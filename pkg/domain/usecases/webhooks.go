@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+type ListWebhookEndpointsUseCase interface {
+	List(ctx context.Context) ([]domain.WebhookEndpoint, error)
+}
+
+type EnableWebhookEndpointUseCase interface {
+	Enable(ctx context.Context, url string) error
+}
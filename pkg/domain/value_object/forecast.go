@@ -0,0 +1,13 @@
+package domain
+
+type ForecastDay struct {
+	Date                string  `json:"date"`
+	MinTemperature      float64 `json:"min_temperature"`
+	MaxTemperature      float64 `json:"max_temperature"`
+	PrecipitationChance float64 `json:"precipitation_chance"`
+	Description         string  `json:"description"`
+}
+
+type Forecast struct {
+	Days []ForecastDay `json:"days"`
+}
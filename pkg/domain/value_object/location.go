@@ -0,0 +1,7 @@
+package domain
+
+type Location struct {
+	Name     string `json:"name"`
+	Country  string `json:"country"`
+	Timezone string `json:"timezone"`
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// ManageLink is the payload of a ManageLinkRequested event.
+type ManageLink struct {
+	Email     string
+	Token     string
+	ExpiresAt time.Time
+}
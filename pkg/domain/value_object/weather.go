@@ -12,6 +12,7 @@ type WeatherEvent struct {
 	Temperature      float64
 	Humidity         float64
 	Description      string
+	Forecast         []ForecastDay
 	Email            string
 	UnsubscribeToken string
 }
@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrWebhookDisabled = errors.New("webhook endpoint is disabled after repeated failures")
+var ErrWebhookEndpointNotFound = errors.New("webhook endpoint not found")
+
+// WebhookEndpoint tracks deliveries to one webhook URL. After too many
+// consecutive failed deliveries it is disabled until an admin enables it
+// again.
+type WebhookEndpoint struct {
+	URL                 string
+	ConsecutiveFailures int
+	TotalFailures       int64
+	LastError           string
+	LastFailureAt       *time.Time
+	LastSuccessAt       *time.Time
+	DisabledAt          *time.Time
+}
+
+func (e *WebhookEndpoint) Disabled() bool {
+	return e.DisabledAt != nil
+}
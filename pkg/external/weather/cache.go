@@ -74,6 +74,82 @@ func (r *RedisWeatherCache) SetWeather(ctx context.Context, city string, weather
 	return nil
 }
 
+func (r *RedisWeatherCache) generateForecastKey(city string, days int) string {
+	return fmt.Sprintf("%s:forecast:%s:%d", r.prefix, city, days)
+}
+
+func (r *RedisWeatherCache) GetForecast(ctx context.Context, city string, days int) (*domain.Forecast, error) {
+	key := r.generateForecastKey(city, days)
+
+	data, err := r.client.Get(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get forecast data from Redis: %w", err)
+	}
+
+	var forecast domain.Forecast
+	if err := json.Unmarshal([]byte(data), &forecast); err != nil {
+		return nil, fmt.Errorf("failed to deserialize forecast data: %w", err)
+	}
+
+	return &forecast, nil
+}
+
+func (r *RedisWeatherCache) SetForecast(ctx context.Context, city string, days int, forecast *domain.Forecast, ttl time.Duration) error {
+	key := r.generateForecastKey(city, days)
+
+	data, err := json.Marshal(forecast)
+	if err != nil {
+		return fmt.Errorf("failed to serialize forecast data: %w", err)
+	}
+
+	if err := r.client.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set forecast data in Redis: %w", err)
+	}
+
+	return nil
+}
+
+func (r *RedisWeatherCache) generateLocationKey(city string) string {
+	return fmt.Sprintf("%s:location:%s", r.prefix, city)
+}
+
+func (r *RedisWeatherCache) GetLocation(ctx context.Context, city string) (*domain.Location, error) {
+	key := r.generateLocationKey(city)
+
+	data, err := r.client.Get(ctx, key).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get location data from Redis: %w", err)
+	}
+
+	var location domain.Location
+	if err := json.Unmarshal([]byte(data), &location); err != nil {
+		return nil, fmt.Errorf("failed to deserialize location data: %w", err)
+	}
+
+	return &location, nil
+}
+
+func (r *RedisWeatherCache) SetLocation(ctx context.Context, city string, location *domain.Location, ttl time.Duration) error {
+	key := r.generateLocationKey(city)
+
+	data, err := json.Marshal(location)
+	if err != nil {
+		return fmt.Errorf("failed to serialize location data: %w", err)
+	}
+
+	if err := r.client.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set location data in Redis: %w", err)
+	}
+
+	return nil
+}
+
 func (r *RedisWeatherCache) Close() error {
 	return r.client.Close()
 }
@@ -7,76 +7,138 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"time"
+	"strconv"
 
 	"github.com/danik-tro/weather-subscriber/pkg/domain"
+	value_object "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
 )
 
+const weatherAPIBaseURL = "https://api.weatherapi.com/v1"
+
 type WeatherAPIClient struct {
 	baseURL    string
 	apiKey     string
 	httpClient *http.Client
 }
 
-func NewWeatherAPIClient(apiKey string) WeatherClient {
+func NewWeatherAPIClient(config ProviderConfig) WeatherClient {
 	return &WeatherAPIClient{
-		baseURL:    "https://api.weatherapi.com/v1",
-		apiKey:     apiKey,
-		httpClient: &http.Client{Timeout: 10 * time.Second},
+		baseURL:    config.baseURLOr(weatherAPIBaseURL),
+		apiKey:     config.APIKey,
+		httpClient: config.httpClient(),
+	}
+}
+
+func (c *WeatherAPIClient) GetCurrentWeather(ctx context.Context, city string) (*value_object.Weather, error) {
+	q := url.Values{}
+	q.Add("q", city)
+	q.Add("aqi", "no")
+
+	var weatherData WeatherData
+	if err := c.get(ctx, "current.json", q, &weatherData); err != nil {
+		return nil, err
+	}
+
+	return &value_object.Weather{
+		Temperature: weatherData.Current.TempC,
+		Humidity:    weatherData.Current.Humidity,
+		Description: weatherData.Current.Condition.Text,
+	}, nil
+}
+
+func (c *WeatherAPIClient) GetForecast(ctx context.Context, city string, days int) (*value_object.Forecast, error) {
+	q := url.Values{}
+	q.Add("q", city)
+	q.Add("days", strconv.Itoa(days))
+	q.Add("aqi", "no")
+	q.Add("alerts", "no")
+
+	var forecastData ForecastData
+	if err := c.get(ctx, "forecast.json", q, &forecastData); err != nil {
+		return nil, err
 	}
+
+	forecast := &value_object.Forecast{
+		Days: make([]value_object.ForecastDay, 0, len(forecastData.Forecast.ForecastDay)),
+	}
+
+	for _, day := range forecastData.Forecast.ForecastDay {
+		forecast.Days = append(forecast.Days, value_object.ForecastDay{
+			Date:                day.Date,
+			MinTemperature:      day.Day.MinTempC,
+			MaxTemperature:      day.Day.MaxTempC,
+			PrecipitationChance: max(day.Day.DailyChanceOfRain, day.Day.DailyChanceOfSnow),
+			Description:         day.Day.Condition.Text,
+		})
+	}
+
+	return forecast, nil
+}
+
+func (c *WeatherAPIClient) GetLocation(ctx context.Context, city string) (*value_object.Location, error) {
+	q := url.Values{}
+	q.Add("q", city)
+	q.Add("aqi", "no")
+
+	var weatherData WeatherData
+	if err := c.get(ctx, "current.json", q, &weatherData); err != nil {
+		return nil, err
+	}
+
+	return &value_object.Location{
+		Name:     weatherData.Location.Name,
+		Country:  weatherData.Location.Country,
+		Timezone: weatherData.Location.TzID,
+	}, nil
 }
 
-func (c *WeatherAPIClient) GetCurrentWeather(ctx context.Context, city string) (*WeatherData, error) {
-	endpoint := fmt.Sprintf("%s/current.json", c.baseURL)
+func (c *WeatherAPIClient) get(ctx context.Context, path string, q url.Values, out interface{}) error {
+	endpoint := fmt.Sprintf("%s/%s", c.baseURL, path)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	// TODO: add logging
 	if err != nil {
 		// TODO: add logging
-		return nil, domain.ErrInternalServerError
+		return domain.ErrInternalServerError
 	}
 
-	q := url.Values{}
-	q.Add("key", c.apiKey)
-	q.Add("q", city)
-	q.Add("aqi", "no")
+	q.Set("key", c.apiKey)
 	req.URL.RawQuery = q.Encode()
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		// TODO: add logging
-		return nil, domain.ErrInternalServerError
+		return domain.ErrInternalServerError
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		// TODO: add logging
-		return nil, domain.ErrInternalServerError
+		return domain.ErrInternalServerError
 	}
 
 	switch resp.StatusCode {
 	case http.StatusOK:
-		var weatherData WeatherData
-		if err := json.Unmarshal(body, &weatherData); err != nil {
+		if err := json.Unmarshal(body, out); err != nil {
 			// TODO: add logging
-			return nil, domain.ErrInternalServerError
+			return domain.ErrInternalServerError
 		}
-		return &weatherData, nil
+		return nil
 	case http.StatusBadRequest:
 		var errorResp ErrorResponse
 		if err := json.Unmarshal(body, &errorResp); err != nil {
 			// TODO: add logging
-			return nil, domain.ErrInternalServerError
+			return domain.ErrInternalServerError
 		}
 
 		if errorResp.Error.Code == 1006 {
-			return nil, domain.ErrCityNotFound
+			return domain.ErrCityNotFound
 		}
 
 		// TODO: add logging
-		return nil, domain.ErrBadRequest
+		return domain.ErrBadRequest
 	default:
-		return nil, domain.ErrBadRequest
+		return domain.ErrBadRequest
 	}
 }
@@ -0,0 +1,187 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/danik-tro/weather-subscriber/pkg/domain"
+	value_object "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+)
+
+const (
+	failureThreshold  = 3
+	unhealthyCooldown = 30 * time.Second
+)
+
+type Provider struct {
+	Name   string
+	Client WeatherClient
+}
+
+type ProviderHealth struct {
+	Name                string
+	Healthy             bool
+	ConsecutiveFailures int
+	LastError           string
+	LastFailureAt       *time.Time
+	UnhealthyUntil      *time.Time
+}
+
+type providerState struct {
+	consecutiveFailures int
+	lastError           error
+	lastFailureAt       time.Time
+	unhealthyUntil      time.Time
+}
+
+// FailoverClient tries providers in order and skips those that failed
+// failureThreshold times in a row until their cooldown expires. When every
+// provider is marked unhealthy, all of them are tried anyway.
+type FailoverClient struct {
+	providers []Provider
+	states    []*providerState
+	mu        sync.Mutex
+	now       func() time.Time
+}
+
+func NewFailoverClient(providers []Provider) *FailoverClient {
+	states := make([]*providerState, len(providers))
+	for i := range states {
+		states[i] = &providerState{}
+	}
+
+	return &FailoverClient{
+		providers: providers,
+		states:    states,
+		now:       time.Now,
+	}
+}
+
+func (c *FailoverClient) GetCurrentWeather(ctx context.Context, city string) (*value_object.Weather, error) {
+	return withFailover(ctx, c, city, func(client WeatherClient) (*value_object.Weather, error) {
+		return client.GetCurrentWeather(ctx, city)
+	})
+}
+
+func (c *FailoverClient) GetForecast(ctx context.Context, city string, days int) (*value_object.Forecast, error) {
+	return withFailover(ctx, c, city, func(client WeatherClient) (*value_object.Forecast, error) {
+		return client.GetForecast(ctx, city, days)
+	})
+}
+
+func (c *FailoverClient) GetLocation(ctx context.Context, city string) (*value_object.Location, error) {
+	return withFailover(ctx, c, city, func(client WeatherClient) (*value_object.Location, error) {
+		return client.GetLocation(ctx, city)
+	})
+}
+
+func withFailover[T any](ctx context.Context, c *FailoverClient, city string, call func(WeatherClient) (*T, error)) (*T, error) {
+	var lastErr error
+
+	for _, i := range c.order() {
+		provider := c.providers[i]
+
+		result, err := call(provider.Client)
+		if err == nil {
+			c.recordSuccess(i)
+			return result, nil
+		}
+
+		if errors.Is(err, domain.ErrCityNotFound) {
+			c.recordSuccess(i)
+			return nil, err
+		}
+
+		log.Printf("Weather provider %s failed for city %s: %v", provider.Name, city, err)
+		c.recordFailure(i, err)
+		lastErr = err
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+
+	if lastErr == nil {
+		return nil, fmt.Errorf("no weather providers configured")
+	}
+
+	return nil, lastErr
+}
+
+func (c *FailoverClient) Health() []ProviderHealth {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	health := make([]ProviderHealth, len(c.providers))
+
+	for i, provider := range c.providers {
+		state := c.states[i]
+		h := ProviderHealth{
+			Name:                provider.Name,
+			Healthy:             !now.Before(state.unhealthyUntil),
+			ConsecutiveFailures: state.consecutiveFailures,
+		}
+		if state.lastError != nil {
+			h.LastError = state.lastError.Error()
+			lastFailureAt := state.lastFailureAt
+			h.LastFailureAt = &lastFailureAt
+		}
+		if !h.Healthy {
+			unhealthyUntil := state.unhealthyUntil
+			h.UnhealthyUntil = &unhealthyUntil
+		}
+		health[i] = h
+	}
+
+	return health
+}
+
+// order returns healthy providers first, keeping the configured priority
+// within each group.
+func (c *FailoverClient) order() []int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	healthy := make([]int, 0, len(c.providers))
+	unhealthy := make([]int, 0)
+
+	for i := range c.providers {
+		if now.Before(c.states[i].unhealthyUntil) {
+			unhealthy = append(unhealthy, i)
+			continue
+		}
+		healthy = append(healthy, i)
+	}
+
+	return append(healthy, unhealthy...)
+}
+
+func (c *FailoverClient) recordSuccess(i int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	state := c.states[i]
+	state.consecutiveFailures = 0
+	state.unhealthyUntil = time.Time{}
+}
+
+func (c *FailoverClient) recordFailure(i int, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	state := c.states[i]
+	state.consecutiveFailures++
+	state.lastError = err
+	state.lastFailureAt = now
+
+	if state.consecutiveFailures >= failureThreshold {
+		state.unhealthyUntil = now.Add(unhealthyCooldown)
+	}
+}
@@ -0,0 +1,145 @@
+package weather
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danik-tro/weather-subscriber/pkg/domain"
+	value_object "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+)
+
+type stubClient struct {
+	weather  *value_object.Weather
+	forecast *value_object.Forecast
+	err      error
+	calls    int
+}
+
+func (s *stubClient) GetCurrentWeather(ctx context.Context, city string) (*value_object.Weather, error) {
+	s.calls++
+	return s.weather, s.err
+}
+
+func (s *stubClient) GetForecast(ctx context.Context, city string, days int) (*value_object.Forecast, error) {
+	s.calls++
+	return s.forecast, s.err
+}
+
+func (s *stubClient) GetLocation(ctx context.Context, city string) (*value_object.Location, error) {
+	s.calls++
+	return &value_object.Location{Name: city}, s.err
+}
+
+func TestFailoverClient_FallsBackOnProviderError(t *testing.T) {
+	primary := &stubClient{err: domain.ErrInternalServerError}
+	fallback := &stubClient{weather: &value_object.Weather{Temperature: 10}}
+
+	client := NewFailoverClient([]Provider{
+		{Name: "primary", Client: primary},
+		{Name: "fallback", Client: fallback},
+	})
+
+	weather, err := client.GetCurrentWeather(context.Background(), "Kyiv")
+	require.NoError(t, err)
+	require.Equal(t, 10.0, weather.Temperature)
+	require.Equal(t, 1, primary.calls)
+	require.Equal(t, 1, fallback.calls)
+
+	health := client.Health()
+	require.Equal(t, 1, health[0].ConsecutiveFailures)
+	require.True(t, health[0].Healthy)
+	require.Equal(t, 0, health[1].ConsecutiveFailures)
+}
+
+func TestFailoverClient_CityNotFoundDoesNotFailOver(t *testing.T) {
+	primary := &stubClient{err: domain.ErrCityNotFound}
+	fallback := &stubClient{weather: &value_object.Weather{}}
+
+	client := NewFailoverClient([]Provider{
+		{Name: "primary", Client: primary},
+		{Name: "fallback", Client: fallback},
+	})
+
+	_, err := client.GetCurrentWeather(context.Background(), "Nowhere")
+	require.ErrorIs(t, err, domain.ErrCityNotFound)
+	require.Equal(t, 0, fallback.calls)
+	require.Equal(t, 0, client.Health()[0].ConsecutiveFailures)
+}
+
+func TestFailoverClient_SkipsUnhealthyProviderUntilCooldown(t *testing.T) {
+	now := time.Now()
+	primary := &stubClient{err: domain.ErrBadRequest}
+	fallback := &stubClient{weather: &value_object.Weather{Temperature: 5}}
+
+	client := NewFailoverClient([]Provider{
+		{Name: "primary", Client: primary},
+		{Name: "fallback", Client: fallback},
+	})
+	client.now = func() time.Time { return now }
+
+	for range failureThreshold {
+		_, err := client.GetCurrentWeather(context.Background(), "Kyiv")
+		require.NoError(t, err)
+	}
+
+	require.False(t, client.Health()[0].Healthy)
+
+	_, err := client.GetCurrentWeather(context.Background(), "Kyiv")
+	require.NoError(t, err)
+	require.Equal(t, failureThreshold, primary.calls)
+
+	now = now.Add(unhealthyCooldown)
+	primary.err = nil
+	primary.weather = &value_object.Weather{Temperature: 7}
+
+	weather, err := client.GetCurrentWeather(context.Background(), "Kyiv")
+	require.NoError(t, err)
+	require.Equal(t, 7.0, weather.Temperature)
+	require.True(t, client.Health()[0].Healthy)
+}
+
+func TestFailoverClient_AllProvidersDown(t *testing.T) {
+	server := newTestServer(t, map[string]http.HandlerFunc{
+		"/current.json": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusServiceUnavailable, `{}`)
+		},
+		"/weather": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusInternalServerError, `{}`)
+		},
+	})
+
+	client, err := NewProviderRegistry().BuildChain(
+		[]string{ProviderWeatherAPI, ProviderOpenWeatherMap},
+		map[string]ProviderConfig{
+			ProviderWeatherAPI:     {BaseURL: server.URL},
+			ProviderOpenWeatherMap: {BaseURL: server.URL},
+		},
+	)
+	require.NoError(t, err)
+
+	_, err = client.GetCurrentWeather(context.Background(), "Kyiv")
+	require.ErrorIs(t, err, domain.ErrBadRequest)
+
+	for _, h := range client.Health() {
+		require.Equal(t, 1, h.ConsecutiveFailures)
+	}
+}
+
+func TestFailoverClient_GetForecast(t *testing.T) {
+	primary := &stubClient{err: domain.ErrInternalServerError}
+	fallback := &stubClient{forecast: &value_object.Forecast{Days: []value_object.ForecastDay{{Date: "2025-01-01"}}}}
+
+	client := NewFailoverClient([]Provider{
+		{Name: "primary", Client: primary},
+		{Name: "fallback", Client: fallback},
+	})
+
+	forecast, err := client.GetForecast(context.Background(), "Kyiv", 1)
+	require.NoError(t, err)
+	require.Len(t, forecast.Days, 1)
+	require.Equal(t, 1, client.Health()[0].ConsecutiveFailures)
+}
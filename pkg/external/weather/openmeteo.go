@@ -0,0 +1,229 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/danik-tro/weather-subscriber/pkg/domain"
+	value_object "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+)
+
+const (
+	openMeteoBaseURL      = "https://api.open-meteo.com/v1"
+	openMeteoGeocodingURL = "https://geocoding-api.open-meteo.com/v1"
+)
+
+type OpenMeteoClient struct {
+	baseURL      string
+	geocodingURL string
+	httpClient   *http.Client
+}
+
+type openMeteoGeocodingResponse struct {
+	Results []openMeteoLocation `json:"results"`
+}
+
+type openMeteoLocation struct {
+	Name      string  `json:"name"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timezone  string  `json:"timezone"`
+}
+
+type openMeteoDailyResponse struct {
+	Daily struct {
+		Time                        []string  `json:"time"`
+		TemperatureMax              []float64 `json:"temperature_2m_max"`
+		TemperatureMin              []float64 `json:"temperature_2m_min"`
+		PrecipitationProbabilityMax []float64 `json:"precipitation_probability_max"`
+		WeatherCode                 []int     `json:"weather_code"`
+	} `json:"daily"`
+}
+
+type openMeteoCurrentResponse struct {
+	Current struct {
+		Temperature      float64 `json:"temperature_2m"`
+		RelativeHumidity float64 `json:"relative_humidity_2m"`
+		WeatherCode      int     `json:"weather_code"`
+	} `json:"current"`
+}
+
+// NewOpenMeteoClient builds a client for open-meteo.com. The service is
+// keyless, so config.APIKey is ignored.
+func NewOpenMeteoClient(config ProviderConfig) WeatherClient {
+	return &OpenMeteoClient{
+		baseURL:      config.baseURLOr(openMeteoBaseURL),
+		geocodingURL: config.geocodingURLOr(openMeteoGeocodingURL),
+		httpClient:   config.httpClient(),
+	}
+}
+
+func (c *OpenMeteoClient) GetCurrentWeather(ctx context.Context, city string) (*value_object.Weather, error) {
+	location, err := c.geocode(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	q := location.query()
+	q.Add("current", "temperature_2m,relative_humidity_2m,weather_code")
+
+	var resp openMeteoCurrentResponse
+	if err := c.forecast(ctx, q, &resp); err != nil {
+		return nil, err
+	}
+
+	return &value_object.Weather{
+		Temperature: resp.Current.Temperature,
+		Humidity:    resp.Current.RelativeHumidity,
+		Description: describeWMOCode(resp.Current.WeatherCode),
+	}, nil
+}
+
+func (c *OpenMeteoClient) GetForecast(ctx context.Context, city string, days int) (*value_object.Forecast, error) {
+	location, err := c.geocode(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	q := location.query()
+	q.Add("daily", "temperature_2m_max,temperature_2m_min,precipitation_probability_max,weather_code")
+	q.Add("forecast_days", strconv.Itoa(days))
+	q.Add("timezone", "auto")
+
+	var resp openMeteoDailyResponse
+	if err := c.forecast(ctx, q, &resp); err != nil {
+		return nil, err
+	}
+
+	daily := resp.Daily
+	n := min(len(daily.Time), len(daily.TemperatureMax), len(daily.TemperatureMin), len(daily.PrecipitationProbabilityMax), len(daily.WeatherCode))
+
+	forecast := &value_object.Forecast{
+		Days: make([]value_object.ForecastDay, 0, n),
+	}
+
+	for i := range n {
+		forecast.Days = append(forecast.Days, value_object.ForecastDay{
+			Date:                daily.Time[i],
+			MinTemperature:      daily.TemperatureMin[i],
+			MaxTemperature:      daily.TemperatureMax[i],
+			PrecipitationChance: daily.PrecipitationProbabilityMax[i],
+			Description:         describeWMOCode(daily.WeatherCode[i]),
+		})
+	}
+
+	return forecast, nil
+}
+
+func (c *OpenMeteoClient) GetLocation(ctx context.Context, city string) (*value_object.Location, error) {
+	location, err := c.geocode(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	return &value_object.Location{
+		Name:     location.Name,
+		Country:  location.Country,
+		Timezone: location.Timezone,
+	}, nil
+}
+
+func (c *OpenMeteoClient) forecast(ctx context.Context, q url.Values, out interface{}) error {
+	status, body, err := fetch(ctx, c.httpClient, c.baseURL+"/forecast", q)
+	if err != nil {
+		log.Printf("Open-Meteo request failed: %v", err)
+		return domain.ErrInternalServerError
+	}
+
+	if status != http.StatusOK {
+		log.Printf("Open-Meteo returned status %d", status)
+		return domain.ErrBadRequest
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return domain.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (l *openMeteoLocation) query() url.Values {
+	q := url.Values{}
+	q.Add("latitude", strconv.FormatFloat(l.Latitude, 'f', -1, 64))
+	q.Add("longitude", strconv.FormatFloat(l.Longitude, 'f', -1, 64))
+	return q
+}
+
+func (c *OpenMeteoClient) geocode(ctx context.Context, city string) (*openMeteoLocation, error) {
+	q := url.Values{}
+	q.Add("name", city)
+	q.Add("count", "1")
+	q.Add("language", "en")
+	q.Add("format", "json")
+
+	status, body, err := fetch(ctx, c.httpClient, c.geocodingURL+"/search", q)
+	if err != nil {
+		log.Printf("Open-Meteo geocoding request failed: %v", err)
+		return nil, domain.ErrInternalServerError
+	}
+
+	if status != http.StatusOK {
+		log.Printf("Open-Meteo geocoding returned status %d", status)
+		return nil, domain.ErrBadRequest
+	}
+
+	var resp openMeteoGeocodingResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, domain.ErrInternalServerError
+	}
+
+	if len(resp.Results) == 0 {
+		return nil, domain.ErrCityNotFound
+	}
+
+	return &resp.Results[0], nil
+}
+
+// describeWMOCode maps WMO weather interpretation codes used by Open-Meteo
+// to the short descriptions weatherapi.com uses.
+func describeWMOCode(code int) string {
+	switch code {
+	case 0:
+		return "Clear sky"
+	case 1:
+		return "Mainly clear"
+	case 2:
+		return "Partly cloudy"
+	case 3:
+		return "Overcast"
+	case 45, 48:
+		return "Fog"
+	case 51, 53, 55:
+		return "Drizzle"
+	case 56, 57:
+		return "Freezing drizzle"
+	case 61, 63, 65:
+		return "Rain"
+	case 66, 67:
+		return "Freezing rain"
+	case 71, 73, 75:
+		return "Snow"
+	case 77:
+		return "Snow grains"
+	case 80, 81, 82:
+		return "Rain showers"
+	case 85, 86:
+		return "Snow showers"
+	case 95:
+		return "Thunderstorm"
+	case 96, 99:
+		return "Thunderstorm with hail"
+	default:
+		return "Unknown"
+	}
+}
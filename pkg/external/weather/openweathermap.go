@@ -0,0 +1,193 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/danik-tro/weather-subscriber/pkg/domain"
+	value_object "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+)
+
+const openWeatherMapBaseURL = "https://api.openweathermap.org/data/2.5"
+
+type OpenWeatherMapClient struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+type openWeatherMapCondition struct {
+	Main        string `json:"main"`
+	Description string `json:"description"`
+}
+
+type openWeatherMapCurrentResponse struct {
+	Name     string `json:"name"`
+	Timezone int    `json:"timezone"`
+	Sys      struct {
+		Country string `json:"country"`
+	} `json:"sys"`
+	Weather []openWeatherMapCondition `json:"weather"`
+	Main    struct {
+		Temp     float64 `json:"temp"`
+		Humidity float64 `json:"humidity"`
+	} `json:"main"`
+}
+
+type openWeatherMapForecastResponse struct {
+	City struct {
+		Timezone int `json:"timezone"`
+	} `json:"city"`
+	List []struct {
+		Dt   int64 `json:"dt"`
+		Main struct {
+			TempMin float64 `json:"temp_min"`
+			TempMax float64 `json:"temp_max"`
+		} `json:"main"`
+		Weather []openWeatherMapCondition `json:"weather"`
+		Pop     float64                   `json:"pop"`
+	} `json:"list"`
+}
+
+func NewOpenWeatherMapClient(config ProviderConfig) WeatherClient {
+	return &OpenWeatherMapClient{
+		baseURL:    config.baseURLOr(openWeatherMapBaseURL),
+		apiKey:     config.APIKey,
+		httpClient: config.httpClient(),
+	}
+}
+
+func (c *OpenWeatherMapClient) GetCurrentWeather(ctx context.Context, city string) (*value_object.Weather, error) {
+	var resp openWeatherMapCurrentResponse
+	if err := c.get(ctx, "weather", city, &resp); err != nil {
+		return nil, err
+	}
+
+	return &value_object.Weather{
+		Temperature: resp.Main.Temp,
+		Humidity:    resp.Main.Humidity,
+		Description: openWeatherMapDescription(resp.Weather),
+	}, nil
+}
+
+// GetForecast aggregates the free 5 day / 3 hour forecast into daily
+// entries in the city's local time. At most five days are available.
+func (c *OpenWeatherMapClient) GetForecast(ctx context.Context, city string, days int) (*value_object.Forecast, error) {
+	var resp openWeatherMapForecastResponse
+	if err := c.get(ctx, "forecast", city, &resp); err != nil {
+		return nil, err
+	}
+
+	offset := time.Duration(resp.City.Timezone) * time.Second
+	forecast := &value_object.Forecast{}
+	middayDistance := map[string]time.Duration{}
+
+	for _, entry := range resp.List {
+		local := time.Unix(entry.Dt, 0).UTC().Add(offset)
+		date := local.Format(time.DateOnly)
+
+		n := len(forecast.Days)
+		if n == 0 || forecast.Days[n-1].Date != date {
+			if n == days {
+				break
+			}
+			forecast.Days = append(forecast.Days, value_object.ForecastDay{
+				Date:           date,
+				MinTemperature: entry.Main.TempMin,
+				MaxTemperature: entry.Main.TempMax,
+			})
+			n++
+		}
+
+		day := &forecast.Days[n-1]
+		day.MinTemperature = min(day.MinTemperature, entry.Main.TempMin)
+		day.MaxTemperature = max(day.MaxTemperature, entry.Main.TempMax)
+		day.PrecipitationChance = max(day.PrecipitationChance, entry.Pop*100)
+
+		distance := (time.Duration(local.Hour())*time.Hour - 12*time.Hour).Abs()
+		if current, ok := middayDistance[date]; !ok || distance < current {
+			middayDistance[date] = distance
+			day.Description = openWeatherMapDescription(entry.Weather)
+		}
+	}
+
+	return forecast, nil
+}
+
+// GetLocation reports the city's timezone as a fixed Etc/GMT zone, since
+// OpenWeatherMap only exposes a UTC offset. Offsets that are not whole
+// hours are left empty.
+func (c *OpenWeatherMapClient) GetLocation(ctx context.Context, city string) (*value_object.Location, error) {
+	var resp openWeatherMapCurrentResponse
+	if err := c.get(ctx, "weather", city, &resp); err != nil {
+		return nil, err
+	}
+
+	return &value_object.Location{
+		Name:     resp.Name,
+		Country:  resp.Sys.Country,
+		Timezone: fixedZoneName(resp.Timezone),
+	}, nil
+}
+
+func fixedZoneName(offsetSeconds int) string {
+	if offsetSeconds%3600 != 0 {
+		return ""
+	}
+
+	hours := offsetSeconds / 3600
+	switch {
+	case hours == 0:
+		return "UTC"
+	case hours > 0:
+		// Etc/GMT zones use inverted signs: UTC+2 is Etc/GMT-2.
+		return fmt.Sprintf("Etc/GMT-%d", hours)
+	default:
+		return fmt.Sprintf("Etc/GMT+%d", -hours)
+	}
+}
+
+func (c *OpenWeatherMapClient) get(ctx context.Context, path, city string, out interface{}) error {
+	q := url.Values{}
+	q.Add("q", city)
+	q.Add("appid", c.apiKey)
+	q.Add("units", "metric")
+
+	status, body, err := fetch(ctx, c.httpClient, c.baseURL+"/"+path, q)
+	if err != nil {
+		log.Printf("OpenWeatherMap request failed: %v", err)
+		return domain.ErrInternalServerError
+	}
+
+	switch status {
+	case http.StatusOK:
+		if err := json.Unmarshal(body, out); err != nil {
+			return domain.ErrInternalServerError
+		}
+		return nil
+	case http.StatusNotFound:
+		return domain.ErrCityNotFound
+	default:
+		log.Printf("OpenWeatherMap returned status %d", status)
+		return domain.ErrBadRequest
+	}
+}
+
+func openWeatherMapDescription(conditions []openWeatherMapCondition) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	description := conditions[0].Description
+	if description == "" {
+		return conditions[0].Main
+	}
+
+	return strings.ToUpper(description[:1]) + description[1:]
+}
@@ -0,0 +1,146 @@
+package weather
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	ProviderWeatherAPI     = "weatherapi"
+	ProviderOpenMeteo      = "openmeteo"
+	ProviderOpenWeatherMap = "openweathermap"
+
+	defaultProviderTimeout = 10 * time.Second
+)
+
+// ProviderConfig holds the settings shared by every weather provider.
+// Empty BaseURL values fall back to the provider's public endpoint, which
+// lets tests point a provider at an httptest server.
+type ProviderConfig struct {
+	APIKey       string
+	BaseURL      string
+	GeocodingURL string
+	Timeout      time.Duration
+}
+
+func (c ProviderConfig) baseURLOr(fallback string) string {
+	if c.BaseURL == "" {
+		return fallback
+	}
+	return strings.TrimRight(c.BaseURL, "/")
+}
+
+func (c ProviderConfig) geocodingURLOr(fallback string) string {
+	if c.GeocodingURL == "" {
+		return fallback
+	}
+	return strings.TrimRight(c.GeocodingURL, "/")
+}
+
+func (c ProviderConfig) httpClient() *http.Client {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultProviderTimeout
+	}
+	return &http.Client{Timeout: timeout}
+}
+
+type ProviderFactory func(config ProviderConfig) WeatherClient
+
+type ProviderRegistry struct {
+	mu        sync.RWMutex
+	factories map[string]ProviderFactory
+}
+
+// NewProviderRegistry returns a registry with all built-in providers registered.
+func NewProviderRegistry() *ProviderRegistry {
+	r := &ProviderRegistry{
+		factories: make(map[string]ProviderFactory),
+	}
+
+	r.Register(ProviderWeatherAPI, NewWeatherAPIClient)
+	r.Register(ProviderOpenMeteo, NewOpenMeteoClient)
+	r.Register(ProviderOpenWeatherMap, NewOpenWeatherMapClient)
+
+	return r
+}
+
+func (r *ProviderRegistry) Register(name string, factory ProviderFactory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.factories[strings.ToLower(name)] = factory
+}
+
+func (r *ProviderRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func (r *ProviderRegistry) Build(name string, config ProviderConfig) (WeatherClient, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[strings.ToLower(name)]
+	r.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown weather provider %q (available: %s)", name, strings.Join(r.Names(), ", "))
+	}
+
+	return factory(config), nil
+}
+
+// BuildChain builds the named providers in order and wraps them in a
+// FailoverClient. The first name is the primary provider.
+func (r *ProviderRegistry) BuildChain(names []string, configs map[string]ProviderConfig) (*FailoverClient, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("at least one weather provider is required")
+	}
+
+	providers := make([]Provider, 0, len(names))
+	for _, name := range names {
+		client, err := r.Build(name, configs[strings.ToLower(name)])
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, Provider{Name: strings.ToLower(name), Client: client})
+	}
+
+	return NewFailoverClient(providers), nil
+}
+
+// fetch performs a GET request and returns the status code and raw body.
+func fetch(ctx context.Context, httpClient *http.Client, endpoint string, query url.Values) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.URL.RawQuery = query.Encode()
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return resp.StatusCode, body, nil
+}
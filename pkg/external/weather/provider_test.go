@@ -0,0 +1,242 @@
+package weather
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danik-tro/weather-subscriber/pkg/domain"
+	value_object "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+)
+
+func newTestServer(t *testing.T, routes map[string]http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	for pattern, handler := range routes {
+		mux.HandleFunc(pattern, handler)
+	}
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func writeJSON(w http.ResponseWriter, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write([]byte(body))
+}
+
+func TestWeatherAPIClient_GetCurrentWeather(t *testing.T) {
+	server := newTestServer(t, map[string]http.HandlerFunc{
+		"/current.json": func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "secret", r.URL.Query().Get("key"))
+
+			if r.URL.Query().Get("q") == "Nowhere" {
+				writeJSON(w, http.StatusBadRequest, `{"error":{"code":1006,"message":"No matching location found."}}`)
+				return
+			}
+
+			writeJSON(w, http.StatusOK, `{
+				"location": {"name": "Kyiv", "tz_id": "Europe/Kyiv"},
+				"current": {"temp_c": 21.5, "humidity": 40, "condition": {"text": "Sunny"}}
+			}`)
+		},
+	})
+
+	client := NewWeatherAPIClient(ProviderConfig{APIKey: "secret", BaseURL: server.URL})
+
+	weather, err := client.GetCurrentWeather(context.Background(), "Kyiv")
+	require.NoError(t, err)
+	require.Equal(t, &value_object.Weather{Temperature: 21.5, Humidity: 40, Description: "Sunny"}, weather)
+
+	_, err = client.GetCurrentWeather(context.Background(), "Nowhere")
+	require.ErrorIs(t, err, domain.ErrCityNotFound)
+}
+
+func TestOpenMeteoClient_GetCurrentWeather(t *testing.T) {
+	server := newTestServer(t, map[string]http.HandlerFunc{
+		"/search": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("name") == "Nowhere" {
+				writeJSON(w, http.StatusOK, `{"generationtime_ms": 0.5}`)
+				return
+			}
+
+			writeJSON(w, http.StatusOK, `{"results": [{"name": "Kyiv", "latitude": 50.45, "longitude": 30.52, "timezone": "Europe/Kyiv"}]}`)
+		},
+		"/forecast": func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "50.45", r.URL.Query().Get("latitude"))
+			require.Equal(t, "30.52", r.URL.Query().Get("longitude"))
+
+			writeJSON(w, http.StatusOK, `{"current": {"temperature_2m": 12.3, "relative_humidity_2m": 81, "weather_code": 61}}`)
+		},
+	})
+
+	client := NewOpenMeteoClient(ProviderConfig{BaseURL: server.URL, GeocodingURL: server.URL})
+
+	weather, err := client.GetCurrentWeather(context.Background(), "Kyiv")
+	require.NoError(t, err)
+	require.Equal(t, &value_object.Weather{Temperature: 12.3, Humidity: 81, Description: "Rain"}, weather)
+
+	_, err = client.GetCurrentWeather(context.Background(), "Nowhere")
+	require.ErrorIs(t, err, domain.ErrCityNotFound)
+}
+
+func TestOpenWeatherMapClient_GetCurrentWeather(t *testing.T) {
+	server := newTestServer(t, map[string]http.HandlerFunc{
+		"/weather": func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "secret", r.URL.Query().Get("appid"))
+			require.Equal(t, "metric", r.URL.Query().Get("units"))
+
+			if r.URL.Query().Get("q") == "Nowhere" {
+				writeJSON(w, http.StatusNotFound, `{"cod": "404", "message": "city not found"}`)
+				return
+			}
+
+			writeJSON(w, http.StatusOK, `{
+				"name": "Kyiv",
+				"weather": [{"main": "Clouds", "description": "broken clouds"}],
+				"main": {"temp": -3.2, "humidity": 90}
+			}`)
+		},
+	})
+
+	client := NewOpenWeatherMapClient(ProviderConfig{APIKey: "secret", BaseURL: server.URL})
+
+	weather, err := client.GetCurrentWeather(context.Background(), "Kyiv")
+	require.NoError(t, err)
+	require.Equal(t, &value_object.Weather{Temperature: -3.2, Humidity: 90, Description: "Broken clouds"}, weather)
+
+	_, err = client.GetCurrentWeather(context.Background(), "Nowhere")
+	require.ErrorIs(t, err, domain.ErrCityNotFound)
+}
+
+func TestProviderRegistry_Build(t *testing.T) {
+	registry := NewProviderRegistry()
+
+	require.Equal(t, []string{ProviderOpenMeteo, ProviderOpenWeatherMap, ProviderWeatherAPI}, registry.Names())
+
+	_, err := registry.Build("OpenMeteo", ProviderConfig{})
+	require.NoError(t, err)
+
+	_, err = registry.Build("unknown", ProviderConfig{})
+	require.Error(t, err)
+
+	_, err = registry.BuildChain(nil, nil)
+	require.Error(t, err)
+}
+
+func TestWeatherAPIClient_GetForecast(t *testing.T) {
+	server := newTestServer(t, map[string]http.HandlerFunc{
+		"/forecast.json": func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "2", r.URL.Query().Get("days"))
+
+			writeJSON(w, http.StatusOK, `{"forecast": {"forecastday": [
+				{"date": "2025-01-01", "day": {"mintemp_c": -2, "maxtemp_c": 3, "daily_chance_of_rain": 10, "daily_chance_of_snow": 60, "condition": {"text": "Light snow"}}},
+				{"date": "2025-01-02", "day": {"mintemp_c": 0, "maxtemp_c": 5, "daily_chance_of_rain": 80, "condition": {"text": "Rain"}}}
+			]}}`)
+		},
+	})
+
+	client := NewWeatherAPIClient(ProviderConfig{BaseURL: server.URL})
+
+	forecast, err := client.GetForecast(context.Background(), "Kyiv", 2)
+	require.NoError(t, err)
+	require.Equal(t, []value_object.ForecastDay{
+		{Date: "2025-01-01", MinTemperature: -2, MaxTemperature: 3, PrecipitationChance: 60, Description: "Light snow"},
+		{Date: "2025-01-02", MinTemperature: 0, MaxTemperature: 5, PrecipitationChance: 80, Description: "Rain"},
+	}, forecast.Days)
+}
+
+func TestOpenMeteoClient_GetForecast(t *testing.T) {
+	server := newTestServer(t, map[string]http.HandlerFunc{
+		"/search": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusOK, `{"results": [{"name": "Kyiv", "latitude": 50.45, "longitude": 30.52}]}`)
+		},
+		"/forecast": func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "2", r.URL.Query().Get("forecast_days"))
+
+			writeJSON(w, http.StatusOK, `{"daily": {
+				"time": ["2025-01-01", "2025-01-02"],
+				"temperature_2m_max": [3, 5],
+				"temperature_2m_min": [-2, 0],
+				"precipitation_probability_max": [60, 80],
+				"weather_code": [71, 63]
+			}}`)
+		},
+	})
+
+	client := NewOpenMeteoClient(ProviderConfig{BaseURL: server.URL, GeocodingURL: server.URL})
+
+	forecast, err := client.GetForecast(context.Background(), "Kyiv", 2)
+	require.NoError(t, err)
+	require.Equal(t, []value_object.ForecastDay{
+		{Date: "2025-01-01", MinTemperature: -2, MaxTemperature: 3, PrecipitationChance: 60, Description: "Snow"},
+		{Date: "2025-01-02", MinTemperature: 0, MaxTemperature: 5, PrecipitationChance: 80, Description: "Rain"},
+	}, forecast.Days)
+}
+
+func TestOpenWeatherMapClient_GetForecast(t *testing.T) {
+	server := newTestServer(t, map[string]http.HandlerFunc{
+		"/forecast": func(w http.ResponseWriter, r *http.Request) {
+			// Local times (UTC+2): 01-01 00:00, 01-01 10:00, 01-02 00:00, 01-03 00:00.
+			writeJSON(w, http.StatusOK, `{"city": {"timezone": 7200}, "list": [
+				{"dt": 1735682400, "main": {"temp_min": -1, "temp_max": 0}, "weather": [{"description": "clear sky"}], "pop": 0},
+				{"dt": 1735718400, "main": {"temp_min": 1, "temp_max": 4}, "weather": [{"description": "light snow"}], "pop": 0.4},
+				{"dt": 1735768800, "main": {"temp_min": -3, "temp_max": -1}, "weather": [{"description": "snow"}], "pop": 0.9},
+				{"dt": 1735855200, "main": {"temp_min": 0, "temp_max": 2}, "weather": [{"description": "fog"}], "pop": 0.1}
+			]}`)
+		},
+	})
+
+	client := NewOpenWeatherMapClient(ProviderConfig{BaseURL: server.URL})
+
+	forecast, err := client.GetForecast(context.Background(), "Kyiv", 2)
+	require.NoError(t, err)
+	require.Equal(t, []value_object.ForecastDay{
+		{Date: "2025-01-01", MinTemperature: -1, MaxTemperature: 4, PrecipitationChance: 40, Description: "Light snow"},
+		{Date: "2025-01-02", MinTemperature: -3, MaxTemperature: -1, PrecipitationChance: 90, Description: "Snow"},
+	}, forecast.Days)
+}
+
+func TestProviders_GetLocation(t *testing.T) {
+	server := newTestServer(t, map[string]http.HandlerFunc{
+		"/current.json": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusOK, `{"location": {"name": "Tokyo", "country": "Japan", "tz_id": "Asia/Tokyo"}}`)
+		},
+		"/search": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusOK, `{"results": [{"name": "Lisbon", "country": "Portugal", "timezone": "Europe/Lisbon"}]}`)
+		},
+		"/weather": func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusOK, `{"name": "Kyiv", "timezone": 7200, "sys": {"country": "UA"}}`)
+		},
+	})
+	config := ProviderConfig{BaseURL: server.URL, GeocodingURL: server.URL}
+
+	cases := []struct {
+		client WeatherClient
+		want   value_object.Location
+	}{
+		{NewWeatherAPIClient(config), value_object.Location{Name: "Tokyo", Country: "Japan", Timezone: "Asia/Tokyo"}},
+		{NewOpenMeteoClient(config), value_object.Location{Name: "Lisbon", Country: "Portugal", Timezone: "Europe/Lisbon"}},
+		{NewOpenWeatherMapClient(config), value_object.Location{Name: "Kyiv", Country: "UA", Timezone: "Etc/GMT-2"}},
+	}
+
+	for _, tc := range cases {
+		location, err := tc.client.GetLocation(context.Background(), "city")
+		require.NoError(t, err)
+		require.Equal(t, tc.want, *location)
+	}
+}
+
+func TestFixedZoneName(t *testing.T) {
+	require.Equal(t, "UTC", fixedZoneName(0))
+	require.Equal(t, "Etc/GMT-9", fixedZoneName(9*3600))
+	require.Equal(t, "Etc/GMT+5", fixedZoneName(-5*3600))
+	require.Equal(t, "", fixedZoneName(19800))
+}
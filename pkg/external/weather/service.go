@@ -9,17 +9,31 @@ import (
 )
 
 const (
-	cacheTTL = 20 * time.Minute
+	cacheTTL         = 20 * time.Minute
+	forecastCacheTTL = time.Hour
+	locationCacheTTL = 24 * time.Hour
 )
 
 type WeatherClient interface {
-	GetCurrentWeather(ctx context.Context, city string) (*WeatherData, error)
+	GetCurrentWeather(ctx context.Context, city string) (*domain.Weather, error)
+
+	GetForecast(ctx context.Context, city string, days int) (*domain.Forecast, error)
+
+	GetLocation(ctx context.Context, city string) (*domain.Location, error)
 }
 
 type WeatherCache interface {
 	GetWeather(ctx context.Context, city string) (*domain.Weather, error)
 
 	SetWeather(ctx context.Context, city string, weather *domain.Weather, ttl time.Duration) error
+
+	GetForecast(ctx context.Context, city string, days int) (*domain.Forecast, error)
+
+	SetForecast(ctx context.Context, city string, days int, forecast *domain.Forecast, ttl time.Duration) error
+
+	GetLocation(ctx context.Context, city string) (*domain.Location, error)
+
+	SetLocation(ctx context.Context, city string, location *domain.Location, ttl time.Duration) error
 }
 
 type WeatherService struct {
@@ -32,7 +46,7 @@ func NewWeatherService(weatherClient WeatherClient, cache WeatherCache) *Weather
 }
 
 func (s *WeatherService) GetWeather(ctx context.Context, city string) (*domain.Weather, error) {
-	city = normalizeCityName(city)
+	city = NormalizeCityName(city)
 
 	weather, err := s.cache.GetWeather(ctx, city)
 	if err != nil {
@@ -43,18 +57,12 @@ func (s *WeatherService) GetWeather(ctx context.Context, city string) (*domain.W
 		return weather, nil
 	}
 
-	weatherData, err := s.weatherClient.GetCurrentWeather(ctx, city)
+	weather, err = s.weatherClient.GetCurrentWeather(ctx, city)
 
 	if err != nil {
 		return nil, err
 	}
 
-	weather = &domain.Weather{
-		Temperature: weatherData.Current.TempC,
-		Humidity:    weatherData.Current.Humidity,
-		Description: weatherData.Current.Condition.Text,
-	}
-
 	if err := s.cache.SetWeather(ctx, city, weather, cacheTTL); err != nil {
 		return nil, err
 	}
@@ -62,6 +70,58 @@ func (s *WeatherService) GetWeather(ctx context.Context, city string) (*domain.W
 	return weather, nil
 }
 
-func normalizeCityName(city string) string {
-	return strings.ToTitle(city)
+func (s *WeatherService) GetForecast(ctx context.Context, city string, days int) (*domain.Forecast, error) {
+	city = NormalizeCityName(city)
+
+	forecast, err := s.cache.GetForecast(ctx, city, days)
+	if err != nil {
+		return nil, err
+	}
+
+	if forecast != nil {
+		return forecast, nil
+	}
+
+	forecast, err = s.weatherClient.GetForecast(ctx, city, days)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.SetForecast(ctx, city, days, forecast, forecastCacheTTL); err != nil {
+		return nil, err
+	}
+
+	return forecast, nil
+}
+
+func (s *WeatherService) GetLocation(ctx context.Context, city string) (*domain.Location, error) {
+	city = NormalizeCityName(city)
+
+	location, err := s.cache.GetLocation(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	if location != nil {
+		return location, nil
+	}
+
+	location, err = s.weatherClient.GetLocation(ctx, city)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.cache.SetLocation(ctx, city, location, locationCacheTTL); err != nil {
+		return nil, err
+	}
+
+	return location, nil
+}
+
+// NormalizeCityName returns the key under which a city's weather is cached,
+// so differently spelled requests for the same city share one entry.
+func NormalizeCityName(city string) string {
+	return strings.ToTitle(strings.TrimSpace(city))
 }
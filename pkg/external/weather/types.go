@@ -66,3 +66,29 @@ type ErrorResponse struct {
 func (e *WeatherError) Error() string {
 	return e.Message
 }
+
+type ForecastData struct {
+	Location Location     `json:"location"`
+	Current  Current      `json:"current"`
+	Forecast ForecastDays `json:"forecast"`
+}
+
+type ForecastDays struct {
+	ForecastDay []ForecastDay `json:"forecastday"`
+}
+
+type ForecastDay struct {
+	Date      string `json:"date"`
+	DateEpoch int64  `json:"date_epoch"`
+	Day       Day    `json:"day"`
+}
+
+type Day struct {
+	MaxTempC          float64   `json:"maxtemp_c"`
+	MinTempC          float64   `json:"mintemp_c"`
+	AvgTempC          float64   `json:"avgtemp_c"`
+	AvgHumidity       float64   `json:"avghumidity"`
+	DailyChanceOfRain float64   `json:"daily_chance_of_rain"`
+	DailyChanceOfSnow float64   `json:"daily_chance_of_snow"`
+	Condition         Condition `json:"condition"`
+}
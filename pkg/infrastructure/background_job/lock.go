@@ -0,0 +1,24 @@
+package background_job
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrLockHeld = errors.New("lock is held by another instance")
+
+// Locker hands out exclusive leases on named locks shared by all instances
+// of the service.
+type Locker interface {
+	// TryLock acquires name for ttl without waiting. It returns ErrLockHeld
+	// when another instance holds the lock.
+	TryLock(ctx context.Context, name string, ttl time.Duration) (Lease, error)
+}
+
+// Lease is a held lock. It must be refreshed before ttl runs out and
+// released with Unlock.
+type Lease interface {
+	Refresh(ctx context.Context, ttl time.Duration) error
+	Unlock(ctx context.Context) error
+}
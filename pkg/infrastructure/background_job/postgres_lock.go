@@ -0,0 +1,67 @@
+package background_job
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+// PostgresLocker uses session-level advisory locks. Each lease keeps its
+// own connection, so the lock is released by Postgres as soon as the
+// holder's session ends, even after a crash; ttl is therefore not needed.
+type PostgresLocker struct {
+	db *sql.DB
+}
+
+func NewPostgresLocker(db *sql.DB) *PostgresLocker {
+	return &PostgresLocker{
+		db: db,
+	}
+}
+
+func (l *PostgresLocker) TryLock(ctx context.Context, name string, ttl time.Duration) (Lease, error) {
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connection for lock %s: %w", name, err)
+	}
+
+	key := advisoryLockKey(name)
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+
+	if !acquired {
+		conn.Close()
+		return nil, ErrLockHeld
+	}
+
+	return &postgresLease{conn: conn, key: key}, nil
+}
+
+type postgresLease struct {
+	conn *sql.Conn
+	key  int64
+}
+
+// Refresh checks that the session holding the lock is still alive.
+func (l *postgresLease) Refresh(ctx context.Context, ttl time.Duration) error {
+	return l.conn.PingContext(ctx)
+}
+
+func (l *postgresLease) Unlock(ctx context.Context) error {
+	defer l.conn.Close()
+
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	return err
+}
+
+func advisoryLockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return int64(h.Sum64())
+}
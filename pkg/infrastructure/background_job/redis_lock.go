@@ -0,0 +1,99 @@
+package background_job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrLockLost = errors.New("lock was lost")
+
+var (
+	refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+	unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+)
+
+// RedisLocker implements Locker with SET NX PX. The value is a per-lease
+// token, so only the holder can refresh or release the lock.
+type RedisLocker struct {
+	client   *redis.Client
+	prefix   string
+	instance string
+}
+
+func NewRedisLocker(redisAddress, password string, db int, prefix, instance string) (*RedisLocker, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     redisAddress,
+		Password: password,
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	return &RedisLocker{
+		client:   client,
+		prefix:   prefix,
+		instance: instance,
+	}, nil
+}
+
+func (l *RedisLocker) TryLock(ctx context.Context, name string, ttl time.Duration) (Lease, error) {
+	key := fmt.Sprintf("%s:lock:%s", l.prefix, name)
+	token := fmt.Sprintf("%s:%s", l.instance, uuid.NewString())
+
+	acquired, err := l.client.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+
+	if !acquired {
+		return nil, ErrLockHeld
+	}
+
+	return &redisLease{client: l.client, key: key, token: token}, nil
+}
+
+func (l *RedisLocker) Close() error {
+	return l.client.Close()
+}
+
+type redisLease struct {
+	client *redis.Client
+	key    string
+	token  string
+}
+
+func (l *redisLease) Refresh(ctx context.Context, ttl time.Duration) error {
+	refreshed, err := refreshScript.Run(ctx, l.client, []string{l.key}, l.token, ttl.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+
+	if refreshed == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+func (l *redisLease) Unlock(ctx context.Context) error {
+	return unlockScript.Run(ctx, l.client, []string{l.key}, l.token).Err()
+}
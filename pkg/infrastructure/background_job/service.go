@@ -1,27 +1,47 @@
 package background_job
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
 	"sync"
+	"time"
 
 	"github.com/robfig/cron/v3"
 )
 
+const (
+	jobLockTTL = 30 * time.Second
+	// jobLockMinHold keeps the lock after a quick run, so instances whose
+	// clock lags a little behind do not run the same tick again.
+	jobLockMinHold = 30 * time.Second
+)
+
 type BackgroundJobService interface {
 	Start() error
 	Stop() error
 	AddJob(schedule string, job func()) error
+	// AddExclusiveJob adds a job that runs on only one instance per tick
+	// when a Locker is configured.
+	AddExclusiveJob(name, schedule string, job func(ctx context.Context)) error
 }
 
 type CronBackgroundJobService struct {
-	cron    *cron.Cron
-	mu      sync.RWMutex
-	running bool
+	cron        *cron.Cron
+	locker      Locker
+	instance    string
+	lockTTL     time.Duration
+	lockMinHold time.Duration
+	mu          sync.RWMutex
+	running     bool
 }
 
 func NewCronBackgroundJobService() *CronBackgroundJobService {
 	return &CronBackgroundJobService{
-		cron: cron.New(cron.WithSeconds()),
+		cron:        cron.New(cron.WithSeconds()),
+		lockTTL:     jobLockTTL,
+		lockMinHold: jobLockMinHold,
 	}
 }
 
@@ -67,3 +87,99 @@ func (s *CronBackgroundJobService) AddJob(schedule string, job func()) error {
 
 	return nil
 }
+
+// UseLock makes exclusive jobs take locker's lock before running. instance
+// identifies this process in the logs.
+func (s *CronBackgroundJobService) UseLock(locker Locker, instance string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.locker = locker
+	s.instance = instance
+}
+
+func (s *CronBackgroundJobService) AddExclusiveJob(name, schedule string, job func(ctx context.Context)) error {
+	return s.AddJob(schedule, func() {
+		s.runExclusive(name, job)
+	})
+}
+
+func (s *CronBackgroundJobService) runExclusive(name string, job func(ctx context.Context)) {
+	s.mu.RLock()
+	locker, instance := s.locker, s.instance
+	s.mu.RUnlock()
+
+	if locker == nil {
+		job(context.Background())
+		return
+	}
+
+	lease, err := locker.TryLock(context.Background(), "job:"+name, s.lockTTL)
+	if err != nil {
+		if errors.Is(err, ErrLockHeld) {
+			log.Printf("Job %s skipped on instance %s: running on another instance", name, instance)
+		} else {
+			log.Printf("Job %s skipped on instance %s: %v", name, instance, err)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		keepLease(ctx, cancel, lease, s.lockTTL, name, instance)
+	}()
+
+	started := time.Now()
+	log.Printf("Job %s started on instance %s", name, instance)
+
+	job(ctx)
+
+	if ctx.Err() != nil {
+		log.Printf("Job %s on instance %s stopped after losing its lock (%s)", name, instance, time.Since(started))
+	} else {
+		log.Printf("Job %s finished on instance %s in %s", name, instance, time.Since(started))
+	}
+
+	if remaining := s.lockMinHold - time.Since(started); remaining > 0 {
+		timer := time.NewTimer(remaining)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
+
+	cancel()
+	wg.Wait()
+
+	if err := lease.Unlock(context.Background()); err != nil {
+		log.Printf("Failed to release lock of job %s on instance %s: %v", name, instance, err)
+	}
+}
+
+// keepLease refreshes lease until ctx is done and cancels ctx when the
+// lock is lost, so the job stops instead of running twice.
+func keepLease(ctx context.Context, cancel context.CancelFunc, lease Lease, ttl time.Duration, name, instance string) {
+	ticker := time.NewTicker(ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := lease.Refresh(ctx, ttl); err != nil {
+				if ctx.Err() == nil {
+					log.Printf("Job %s lost its lock on instance %s: %v", name, instance, err)
+				}
+				cancel()
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
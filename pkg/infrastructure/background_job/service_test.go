@@ -0,0 +1,114 @@
+package background_job
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type memoryLocker struct {
+	mu         sync.Mutex
+	held       map[string]bool
+	refreshErr error
+}
+
+func (l *memoryLocker) TryLock(ctx context.Context, name string, ttl time.Duration) (Lease, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.held[name] {
+		return nil, ErrLockHeld
+	}
+	l.held[name] = true
+
+	return &memoryLease{locker: l, name: name}, nil
+}
+
+type memoryLease struct {
+	locker *memoryLocker
+	name   string
+}
+
+func (l *memoryLease) Refresh(ctx context.Context, ttl time.Duration) error {
+	return l.locker.refreshErr
+}
+
+func (l *memoryLease) Unlock(ctx context.Context) error {
+	l.locker.mu.Lock()
+	defer l.locker.mu.Unlock()
+
+	delete(l.locker.held, l.name)
+	return nil
+}
+
+func TestRunExclusive_RunsOncePerTick(t *testing.T) {
+	locker := &memoryLocker{held: map[string]bool{}}
+
+	var runs atomic.Int32
+	var wg sync.WaitGroup
+
+	for _, instance := range []string{"a", "b", "c"} {
+		service := NewCronBackgroundJobService()
+		service.UseLock(locker, instance)
+		service.lockMinHold = 20 * time.Millisecond
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			service.runExclusive("daily_digest", func(ctx context.Context) {
+				runs.Add(1)
+				time.Sleep(10 * time.Millisecond)
+			})
+		}()
+	}
+
+	// Give every instance a chance to try the lock while it is still held.
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, int32(1), runs.Load())
+
+	wg.Wait()
+	require.Empty(t, locker.held)
+}
+
+func TestRunExclusive_WithoutLocker(t *testing.T) {
+	service := NewCronBackgroundJobService()
+
+	ran := false
+	service.runExclusive("daily_digest", func(ctx context.Context) {
+		ran = true
+	})
+
+	require.True(t, ran)
+}
+
+func TestKeepLease_CancelsWhenLockIsLost(t *testing.T) {
+	locker := &memoryLocker{held: map[string]bool{}, refreshErr: errors.New("lock was lost")}
+	lease, err := locker.TryLock(context.Background(), "job", time.Second)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		keepLease(ctx, cancel, lease, 30*time.Millisecond, "job", "a")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("lease was not given up")
+	}
+	require.Error(t, ctx.Err())
+}
+
+func TestAdvisoryLockKey(t *testing.T) {
+	require.Equal(t, advisoryLockKey("job:daily_digest"), advisoryLockKey("job:daily_digest"))
+	require.NotEqual(t, advisoryLockKey("job:daily_digest"), advisoryLockKey("job:hourly_digest"))
+}
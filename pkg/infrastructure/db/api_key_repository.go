@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+type GormAPIKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAPIKeyRepository(db *gorm.DB) *GormAPIKeyRepository {
+	return &GormAPIKeyRepository{
+		db: db,
+	}
+}
+
+func (r *GormAPIKeyRepository) Save(ctx context.Context, k *domain.APIKey) error {
+	tx := r.db.WithContext(ctx)
+
+	return tx.Save(ToAPIKeyModel(k)).Error
+}
+
+func (r *GormAPIKeyRepository) FindByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var model APIKeyModel
+
+	tx := r.db.WithContext(ctx)
+
+	if err := tx.Where("key_hash = ?", keyHash).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	apiKey := ToAPIKeyDomain(&model)
+
+	return &apiKey, nil
+}
+
+func (r *GormAPIKeyRepository) List(ctx context.Context) ([]domain.APIKey, error) {
+	var models []APIKeyModel
+
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	apiKeys := make([]domain.APIKey, len(models))
+	for i := range models {
+		apiKeys[i] = ToAPIKeyDomain(&models[i])
+	}
+
+	return apiKeys, nil
+}
+
+func (r *GormAPIKeyRepository) MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) error {
+	tx := r.db.WithContext(ctx)
+
+	return tx.Model(&APIKeyModel{}).Where("id = ?", id).Update("last_used_at", usedAt).Error
+}
+
+func (r *GormAPIKeyRepository) Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	tx := r.db.WithContext(ctx)
+
+	result := tx.Model(&APIKeyModel{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrAPIKeyNotFound
+	}
+
+	return nil
+}
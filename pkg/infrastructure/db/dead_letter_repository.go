@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+type GormDeadLetterRepository struct {
+	db *gorm.DB
+}
+
+func NewGormDeadLetterRepository(db *gorm.DB) *GormDeadLetterRepository {
+	return &GormDeadLetterRepository{
+		db: db,
+	}
+}
+
+func (r *GormDeadLetterRepository) Save(ctx context.Context, d *domain.DeadLetter) error {
+	tx := r.db.WithContext(ctx)
+
+	return tx.Create(ToDeadLetterModel(d)).Error
+}
+
+func (r *GormDeadLetterRepository) List(ctx context.Context, limit, offset int, includeReplayed bool) ([]domain.DeadLetter, error) {
+	var models []DeadLetterModel
+
+	tx := r.db.WithContext(ctx).Order("created_at DESC").Limit(limit).Offset(offset)
+	if !includeReplayed {
+		tx = tx.Where("replayed_at IS NULL")
+	}
+
+	if err := tx.Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	deadLetters := make([]domain.DeadLetter, len(models))
+	for i := range models {
+		deadLetters[i] = ToDeadLetterDomain(&models[i])
+	}
+
+	return deadLetters, nil
+}
+
+func (r *GormDeadLetterRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.DeadLetter, error) {
+	var model DeadLetterModel
+
+	tx := r.db.WithContext(ctx)
+
+	if err := tx.Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrDeadLetterNotFound
+		}
+		return nil, err
+	}
+
+	deadLetter := ToDeadLetterDomain(&model)
+
+	return &deadLetter, nil
+}
+
+func (r *GormDeadLetterRepository) Replay(ctx context.Context, id uuid.UUID, message *domain.OutboxMessage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&DeadLetterModel{}).
+			Where("id = ? AND replayed_at IS NULL", id).
+			Update("replayed_at", time.Now().UTC())
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrDeadLetterAlreadyReplayed
+		}
+
+		return tx.Create(ToOutboxModel(message)).Error
+	})
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+)
+
+// maxDeliveryAttempts bounds how often a failed digest is retried within
+// the same period.
+const maxDeliveryAttempts = 3
+
+type GormDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewGormDeliveryRepository(db *gorm.DB) *GormDeliveryRepository {
+	return &GormDeliveryRepository{
+		db: db,
+	}
+}
+
+func (r *GormDeliveryRepository) Claim(ctx context.Context, subscriptionID uuid.UUID, period time.Time, staleAfter time.Duration) (bool, error) {
+	now := time.Now().UTC()
+
+	tx := r.db.WithContext(ctx)
+
+	result := tx.Exec(`
+		INSERT INTO deliveries (id, subscription_id, period, status, attempts, error, created_at, updated_at)
+		VALUES (?, ?, ?, ?, 1, '', ?, ?)
+		ON CONFLICT (subscription_id, period) DO UPDATE
+		SET status = EXCLUDED.status, attempts = deliveries.attempts + 1, updated_at = EXCLUDED.updated_at
+		WHERE (deliveries.status = ? AND deliveries.attempts < ?)
+			OR (deliveries.status = ? AND deliveries.updated_at < ?)`,
+		uuid.New(), subscriptionID, period, string(domain.DeliveryPending), now, now,
+		string(domain.DeliveryFailed), maxDeliveryAttempts,
+		string(domain.DeliveryPending), now.Add(-staleAfter),
+	)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r *GormDeliveryRepository) MarkDelivered(ctx context.Context, subscriptionID uuid.UUID, period time.Time, sentAt time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&DeliveryModel{}).
+			Where("subscription_id = ? AND period = ?", subscriptionID, period).
+			Updates(map[string]interface{}{
+				"status":     string(domain.DeliverySent),
+				"error":      "",
+				"sent_at":    sentAt,
+				"updated_at": sentAt,
+			}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&SubscriptionModel{}).
+			Where("id = ?", subscriptionID).
+			Update("last_sent_at", sentAt).Error
+	})
+}
+
+func (r *GormDeliveryRepository) MarkFailed(ctx context.Context, subscriptionID uuid.UUID, period time.Time, reason string) error {
+	tx := r.db.WithContext(ctx)
+
+	result := tx.Model(&DeliveryModel{}).
+		Where("subscription_id = ? AND period = ?", subscriptionID, period).
+		Updates(map[string]interface{}{
+			"status":     string(domain.DeliveryFailed),
+			"error":      reason,
+			"updated_at": time.Now().UTC(),
+		})
+
+	return result.Error
+}
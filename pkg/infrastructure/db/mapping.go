@@ -1,37 +1,108 @@
 package db
 
 import (
+	"time"
+
+	domain_events "github.com/danik-tro/weather-subscriber/pkg/domain"
 	domain "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	"gorm.io/gorm"
 )
 
 func ToModel(s *domain.Subscription) *SubscriptionModel {
-	return &SubscriptionModel{
-		ID:                s.ID,
-		Email:             s.Email,
-		City:              s.City,
-		Frequency:         Frequency(s.Frequency),
-		ConfirmationToken: s.ConfirmationToken,
-		UnsubscribeToken:  s.UnsubscribeToken,
-		Confirmed:         s.Confirmed,
-		CreatedAt:         s.CreatedAt,
-		ConfirmedAt:       s.ConfirmedAt,
-		LastSentAt:        s.LastSentAt,
+	model := &SubscriptionModel{
+		ID:                    s.ID,
+		Email:                 s.Email,
+		City:                  s.City,
+		Frequency:             Frequency(s.Frequency),
+		ConfirmationTokenHash: s.ConfirmationTokenHash,
+		ConfirmationSentAt:    s.ConfirmationSentAt,
+		Confirmed:             s.Confirmed,
+		CreatedAt:             s.CreatedAt,
+		ConfirmedAt:           s.ConfirmedAt,
+		LastSentAt:            s.LastSentAt,
+		Timezone:              s.Timezone,
+		SendHour:              s.SendHour,
+		AlertTriggered:        s.AlertTriggered,
+		PausedAt:              s.PausedAt,
+		PausedUntil:           s.PausedUntil,
+		ManageLinkSentAt:      s.ManageLinkSentAt,
+	}
+
+	if s.WebhookURL != "" {
+		webhookURL := s.WebhookURL
+		model.WebhookURL = &webhookURL
 	}
+
+	if s.UnsubscribeTokenHash != "" {
+		hash := s.UnsubscribeTokenHash
+		model.UnsubscribeTokenHash = &hash
+	}
+
+	if s.UnsubscribedAt != nil {
+		model.DeletedAt = gorm.DeletedAt{Time: *s.UnsubscribedAt, Valid: true}
+	}
+
+	if s.AlertRule != nil {
+		metric := string(s.AlertRule.Metric)
+		comparator := string(s.AlertRule.Comparator)
+		threshold := s.AlertRule.Threshold
+		cooldown := int64(s.AlertRule.Cooldown / time.Second)
+
+		model.AlertMetric = &metric
+		model.AlertComparator = &comparator
+		model.AlertThreshold = &threshold
+		model.AlertCooldown = &cooldown
+	}
+
+	return model
 }
 
 func ToDomain(m *SubscriptionModel) *domain.Subscription {
-	return &domain.Subscription{
-		ID:                m.ID,
-		Email:             m.Email,
-		City:              m.City,
-		Frequency:         domain.Frequency(m.Frequency),
-		ConfirmationToken: m.ConfirmationToken,
-		UnsubscribeToken:  m.UnsubscribeToken,
-		Confirmed:         m.Confirmed,
-		CreatedAt:         m.CreatedAt,
-		ConfirmedAt:       m.ConfirmedAt,
-		LastSentAt:        m.LastSentAt,
+	subscription := &domain.Subscription{
+		ID:                    m.ID,
+		Email:                 m.Email,
+		City:                  m.City,
+		Frequency:             domain.Frequency(m.Frequency),
+		ConfirmationTokenHash: m.ConfirmationTokenHash,
+		ConfirmationSentAt:    m.ConfirmationSentAt,
+		Confirmed:             m.Confirmed,
+		CreatedAt:             m.CreatedAt,
+		ConfirmedAt:           m.ConfirmedAt,
+		LastSentAt:            m.LastSentAt,
+		Timezone:              m.Timezone,
+		SendHour:              m.SendHour,
+		AlertTriggered:        m.AlertTriggered,
+		PausedAt:              m.PausedAt,
+		PausedUntil:           m.PausedUntil,
+		ManageLinkSentAt:      m.ManageLinkSentAt,
 	}
+
+	if m.WebhookURL != nil {
+		subscription.WebhookURL = *m.WebhookURL
+	}
+
+	if m.UnsubscribeTokenHash != nil {
+		subscription.UnsubscribeTokenHash = *m.UnsubscribeTokenHash
+	}
+
+	if m.DeletedAt.Valid {
+		unsubscribedAt := m.DeletedAt.Time
+		subscription.UnsubscribedAt = &unsubscribedAt
+	}
+
+	if m.AlertMetric != nil && m.AlertComparator != nil && m.AlertThreshold != nil {
+		rule := &domain.AlertRule{
+			Metric:     domain.AlertMetric(*m.AlertMetric),
+			Comparator: domain.AlertComparator(*m.AlertComparator),
+			Threshold:  *m.AlertThreshold,
+		}
+		if m.AlertCooldown != nil {
+			rule.Cooldown = time.Duration(*m.AlertCooldown) * time.Second
+		}
+		subscription.AlertRule = rule
+	}
+
+	return subscription
 }
 
 func ToDomainList(models []*SubscriptionModel) []*domain.Subscription {
@@ -41,3 +112,99 @@ func ToDomainList(models []*SubscriptionModel) []*domain.Subscription {
 	}
 	return subscriptions
 }
+
+func ToOutboxModel(m *domain_events.OutboxMessage) *OutboxMessageModel {
+	return &OutboxMessageModel{
+		ID:          m.ID,
+		EventType:   string(m.EventType),
+		Payload:     m.Payload,
+		Attempts:    m.Attempts,
+		LastError:   m.LastError,
+		CreatedAt:   m.CreatedAt,
+		AvailableAt: m.AvailableAt,
+		PublishedAt: m.PublishedAt,
+	}
+}
+
+func ToOutboxDomain(m *OutboxMessageModel) domain_events.OutboxMessage {
+	return domain_events.OutboxMessage{
+		ID:          m.ID,
+		EventType:   domain_events.EventType(m.EventType),
+		Payload:     m.Payload,
+		Attempts:    m.Attempts,
+		LastError:   m.LastError,
+		CreatedAt:   m.CreatedAt,
+		AvailableAt: m.AvailableAt,
+		PublishedAt: m.PublishedAt,
+	}
+}
+
+func ToDeadLetterModel(d *domain_events.DeadLetter) *DeadLetterModel {
+	return &DeadLetterModel{
+		ID:         d.ID,
+		EventType:  string(d.EventType),
+		Payload:    d.Payload,
+		Error:      d.Error,
+		Attempts:   d.Attempts,
+		CreatedAt:  d.CreatedAt,
+		ReplayedAt: d.ReplayedAt,
+	}
+}
+
+func ToDeadLetterDomain(m *DeadLetterModel) domain_events.DeadLetter {
+	return domain_events.DeadLetter{
+		ID:         m.ID,
+		EventType:  domain_events.EventType(m.EventType),
+		Payload:    m.Payload,
+		Error:      m.Error,
+		Attempts:   m.Attempts,
+		CreatedAt:  m.CreatedAt,
+		ReplayedAt: m.ReplayedAt,
+	}
+}
+
+func ToAPIKeyModel(k *domain_events.APIKey) *APIKeyModel {
+	return &APIKeyModel{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		KeyHash:    k.KeyHash,
+		CreatedAt:  k.CreatedAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+	}
+}
+
+func ToAPIKeyDomain(m *APIKeyModel) domain_events.APIKey {
+	return domain_events.APIKey{
+		ID:         m.ID,
+		Name:       m.Name,
+		Prefix:     m.Prefix,
+		KeyHash:    m.KeyHash,
+		CreatedAt:  m.CreatedAt,
+		LastUsedAt: m.LastUsedAt,
+		RevokedAt:  m.RevokedAt,
+	}
+}
+
+func ToSuppressionModel(s *domain_events.Suppression) *SuppressionModel {
+	return &SuppressionModel{
+		Email:     s.Email,
+		Reason:    string(s.Reason),
+		Source:    s.Source,
+		Detail:    s.Detail,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+func ToWebhookEndpoint(m *WebhookEndpointModel) *domain_events.WebhookEndpoint {
+	return &domain_events.WebhookEndpoint{
+		URL:                 m.URL,
+		ConsecutiveFailures: m.ConsecutiveFailures,
+		TotalFailures:       m.TotalFailures,
+		LastError:           m.LastError,
+		LastFailureAt:       m.LastFailureAt,
+		LastSuccessAt:       m.LastSuccessAt,
+		DisabledAt:          m.DisabledAt,
+	}
+}
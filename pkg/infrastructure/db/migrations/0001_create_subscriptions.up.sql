@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id                 uuid PRIMARY KEY,
+    email              text,
+    city               text,
+    frequency          varchar(10) DEFAULT 'DAILY',
+    confirmation_token varchar(100),
+    unsubscribe_token  varchar(100),
+    confirmed          boolean DEFAULT false,
+    created_at         timestamptz,
+    confirmed_at       timestamptz,
+    last_sent_at       timestamptz,
+    deleted_at         timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_city ON subscriptions (email, city);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_confirmation_token ON subscriptions (confirmation_token);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_unsubscribe_token ON subscriptions (unsubscribe_token);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_deleted_at ON subscriptions (deleted_at);
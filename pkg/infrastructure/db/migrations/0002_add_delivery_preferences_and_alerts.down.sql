@@ -0,0 +1,8 @@
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS send_hour,
+    DROP COLUMN IF EXISTS alert_metric,
+    DROP COLUMN IF EXISTS alert_comparator,
+    DROP COLUMN IF EXISTS alert_threshold,
+    DROP COLUMN IF EXISTS alert_cooldown,
+    DROP COLUMN IF EXISTS alert_triggered;
@@ -0,0 +1,10 @@
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS timezone         varchar(64) DEFAULT 'UTC',
+    ADD COLUMN IF NOT EXISTS send_hour        bigint DEFAULT 12,
+    ADD COLUMN IF NOT EXISTS alert_metric     varchar(32),
+    ADD COLUMN IF NOT EXISTS alert_comparator varchar(8),
+    ADD COLUMN IF NOT EXISTS alert_threshold  double precision,
+    ADD COLUMN IF NOT EXISTS alert_cooldown   bigint,
+    ADD COLUMN IF NOT EXISTS alert_triggered  boolean DEFAULT false;
+
+COMMENT ON COLUMN subscriptions.alert_cooldown IS 'cooldown in seconds';
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id           uuid PRIMARY KEY,
+    event_type   varchar(64) NOT NULL,
+    payload      jsonb NOT NULL,
+    attempts     bigint DEFAULT 0,
+    last_error   text,
+    created_at   timestamptz,
+    available_at timestamptz,
+    published_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox_messages (available_at) WHERE published_at IS NULL;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS deliveries (
+    id              uuid PRIMARY KEY,
+    subscription_id uuid NOT NULL,
+    period          timestamptz NOT NULL,
+    status          varchar(10) NOT NULL,
+    attempts        bigint DEFAULT 0,
+    error           text,
+    created_at      timestamptz,
+    updated_at      timestamptz,
+    sent_at         timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_delivery_period ON deliveries (subscription_id, period);
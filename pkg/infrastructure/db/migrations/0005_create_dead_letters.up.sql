@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS dead_letters (
+    id          uuid PRIMARY KEY,
+    event_type  varchar(64) NOT NULL,
+    payload     jsonb NOT NULL,
+    error       text,
+    attempts    bigint,
+    created_at  timestamptz,
+    replayed_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_dead_letters_created_at ON dead_letters (created_at);
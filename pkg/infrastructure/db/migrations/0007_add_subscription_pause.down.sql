@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_subscriptions_paused_until;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS paused_until,
+    DROP COLUMN IF EXISTS paused_at;
@@ -0,0 +1,5 @@
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS paused_at    timestamptz,
+    ADD COLUMN IF NOT EXISTS paused_until timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_paused_until ON subscriptions (paused_until);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_subscriptions_unconfirmed;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS confirmation_sent_at;
@@ -0,0 +1,10 @@
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS confirmation_sent_at timestamptz;
+
+UPDATE subscriptions SET confirmation_sent_at = created_at WHERE confirmation_sent_at IS NULL;
+
+ALTER TABLE subscriptions
+    ALTER COLUMN confirmation_sent_at SET DEFAULT now(),
+    ALTER COLUMN confirmation_sent_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_unconfirmed ON subscriptions (confirmation_sent_at) WHERE NOT confirmed;
@@ -0,0 +1,15 @@
+-- Keep only the live row, or the latest unsubscribed one, for each email and
+-- city so the full unique index can be restored.
+DELETE FROM subscriptions s
+WHERE s.deleted_at IS NOT NULL
+  AND EXISTS (
+    SELECT 1 FROM subscriptions o
+    WHERE o.email = s.email
+      AND o.city = s.city
+      AND o.id <> s.id
+      AND (o.deleted_at IS NULL OR (o.created_at, o.id) > (s.created_at, s.id))
+  );
+
+DROP INDEX IF EXISTS idx_email_city;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_city ON subscriptions (email, city);
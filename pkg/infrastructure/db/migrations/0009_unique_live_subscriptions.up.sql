@@ -0,0 +1,5 @@
+-- Unsubscribed rows are soft-deleted and kept as history, so only live
+-- subscriptions must be unique per email and city.
+DROP INDEX IF EXISTS idx_email_city;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_city ON subscriptions (email, city) WHERE deleted_at IS NULL;
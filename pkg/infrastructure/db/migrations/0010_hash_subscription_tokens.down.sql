@@ -0,0 +1,21 @@
+-- The plaintext tokens cannot be recovered from their digests: rolling back
+-- issues new random tokens, which invalidates every link sent so far.
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS confirmation_token varchar(100),
+    ADD COLUMN IF NOT EXISTS unsubscribe_token  varchar(100);
+
+UPDATE subscriptions
+SET confirmation_token = gen_random_uuid()::text,
+    unsubscribe_token  = gen_random_uuid()::text;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_confirmation_token ON subscriptions (confirmation_token);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_unsubscribe_token ON subscriptions (unsubscribe_token);
+
+DROP INDEX IF EXISTS idx_subscriptions_legacy_unsubscribe_token_hash;
+DROP INDEX IF EXISTS idx_subscriptions_unsubscribe_token_hash;
+DROP INDEX IF EXISTS idx_subscriptions_confirmation_token_hash;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS legacy_unsubscribe_token_hash,
+    DROP COLUMN IF EXISTS unsubscribe_token_hash,
+    DROP COLUMN IF EXISTS confirmation_token_hash;
@@ -0,0 +1,27 @@
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS confirmation_token_hash       varchar(64),
+    ADD COLUMN IF NOT EXISTS unsubscribe_token_hash        varchar(64),
+    ADD COLUMN IF NOT EXISTS legacy_unsubscribe_token_hash varchar(64);
+
+-- Links already emailed carry the plaintext tokens, so storing their digests
+-- keeps them working. Unsubscribe tokens are now derived from the
+-- subscription ID with a server key; the application stores the digest of
+-- the derived token for existing rows on startup.
+UPDATE subscriptions
+SET confirmation_token_hash       = encode(sha256(convert_to(confirmation_token, 'UTF8')), 'hex'),
+    legacy_unsubscribe_token_hash = encode(sha256(convert_to(unsubscribe_token, 'UTF8')), 'hex');
+
+ALTER TABLE subscriptions
+    ALTER COLUMN confirmation_token_hash SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_subscriptions_confirmation_token;
+DROP INDEX IF EXISTS idx_subscriptions_unsubscribe_token;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS confirmation_token,
+    DROP COLUMN IF EXISTS unsubscribe_token;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_confirmation_token_hash ON subscriptions (confirmation_token_hash);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_subscriptions_unsubscribe_token_hash ON subscriptions (unsubscribe_token_hash);
+CREATE INDEX IF NOT EXISTS idx_subscriptions_legacy_unsubscribe_token_hash ON subscriptions (legacy_unsubscribe_token_hash)
+    WHERE legacy_unsubscribe_token_hash IS NOT NULL;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS suppressions (
+    email      varchar(255) PRIMARY KEY,
+    reason     varchar(32) NOT NULL,
+    source     varchar(32) NOT NULL,
+    detail     text,
+    created_at timestamptz
+);
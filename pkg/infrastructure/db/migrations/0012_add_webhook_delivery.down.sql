@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS webhook_endpoints;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS webhook_url;
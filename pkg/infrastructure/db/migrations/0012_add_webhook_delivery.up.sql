@@ -0,0 +1,12 @@
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS webhook_url varchar(2048);
+
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    url                  varchar(2048) PRIMARY KEY,
+    consecutive_failures integer NOT NULL DEFAULT 0,
+    total_failures       bigint NOT NULL DEFAULT 0,
+    last_error           text,
+    last_failure_at      timestamptz,
+    last_success_at      timestamptz,
+    disabled_at          timestamptz
+);
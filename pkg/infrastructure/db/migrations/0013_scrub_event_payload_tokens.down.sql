@@ -0,0 +1,2 @@
+-- The removed tokens cannot be restored; rolling back leaves the payloads
+-- as they are.
@@ -0,0 +1,9 @@
+-- Event payloads serialized whole subscriptions and digests, tokens
+-- included. Drop them so the only plaintext copies are in the emails.
+UPDATE outbox_messages
+SET payload = payload - 'ConfirmationToken' - 'UnsubscribeToken'
+WHERE payload ?| ARRAY['ConfirmationToken', 'UnsubscribeToken'];
+
+UPDATE dead_letters
+SET payload = payload - 'ConfirmationToken' - 'UnsubscribeToken'
+WHERE payload ?| ARRAY['ConfirmationToken', 'UnsubscribeToken'];
@@ -0,0 +1,2 @@
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS manage_link_sent_at;
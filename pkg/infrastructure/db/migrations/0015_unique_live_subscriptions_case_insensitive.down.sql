@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_email_city;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_city ON subscriptions (email, city) WHERE deleted_at IS NULL;
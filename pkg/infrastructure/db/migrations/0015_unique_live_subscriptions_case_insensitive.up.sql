@@ -0,0 +1,18 @@
+-- Lookups match the email case-insensitively, so uniqueness must too. Where
+-- live subscriptions differ only in case, keep the confirmed one, or else
+-- the oldest, and unsubscribe the rest so the index can be built.
+UPDATE subscriptions s
+SET deleted_at = now()
+WHERE s.deleted_at IS NULL
+  AND EXISTS (
+    SELECT 1 FROM subscriptions o
+    WHERE LOWER(o.email) = LOWER(s.email)
+      AND o.city = s.city
+      AND o.id <> s.id
+      AND o.deleted_at IS NULL
+      AND (o.confirmed, s.created_at, s.id) > (s.confirmed, o.created_at, o.id)
+  );
+
+DROP INDEX IF EXISTS idx_email_city;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_city ON subscriptions (LOWER(email), city) WHERE deleted_at IS NULL;
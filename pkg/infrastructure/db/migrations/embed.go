@@ -0,0 +1,9 @@
+// Package migrations holds the versioned SQL migrations of the database
+// schema. Files are named <version>_<name>.up.sql and
+// <version>_<name>.down.sql and are applied in version order.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/db/migrations"
+)
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	loaded, err := LoadMigrations(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, loaded)
+
+	require.Equal(t, 1, loaded[0].Version)
+	require.Equal(t, "create_subscriptions", loaded[0].Name)
+
+	for i, migration := range loaded {
+		require.Equal(t, i+1, migration.Version, "migration versions must be contiguous")
+		require.NotEmpty(t, migration.Up)
+		require.NotEmpty(t, migration.Down)
+	}
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	_, err := LoadMigrations(fstest.MapFS{
+		"0001_init.up.sql": {Data: []byte("CREATE TABLE a ();")},
+	})
+	require.ErrorContains(t, err, "needs both up and down")
+
+	_, err = LoadMigrations(fstest.MapFS{
+		"0001_init.up.sql":    {Data: []byte("CREATE TABLE a ();")},
+		"0001_other.down.sql": {Data: []byte("DROP TABLE a;")},
+	})
+	require.ErrorContains(t, err, "conflicting names")
+
+	loaded, err := LoadMigrations(fstest.MapFS{
+		"0002_b.up.sql":   {Data: []byte("b")},
+		"0002_b.down.sql": {Data: []byte("b")},
+		"0001_a.up.sql":   {Data: []byte("a")},
+		"0001_a.down.sql": {Data: []byte("a")},
+		"README.md":       {Data: []byte("ignored")},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []Migration{
+		{Version: 1, Name: "a", Up: "a", Down: "a"},
+		{Version: 2, Name: "b", Up: "b", Down: "b"},
+	}, loaded)
+}
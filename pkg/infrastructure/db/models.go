@@ -12,22 +12,125 @@ type Frequency string
 const (
 	FrequencyHourly Frequency = "HOURLY"
 	FrequencyDaily  Frequency = "DAILY"
+	FrequencyAlert  Frequency = "ALERT"
 )
 
 type SubscriptionModel struct {
-	ID                uuid.UUID `gorm:"type:uuid;primary_key"`
-	Email             string    `gorm:"uniqueIndex:idx_email_city"`
-	City              string    `gorm:"uniqueIndex:idx_email_city"`
-	Frequency         Frequency `gorm:"type:varchar(10);default:'DAILY'"`
-	ConfirmationToken string    `gorm:"uniqueIndex;type:varchar(100)"`
-	UnsubscribeToken  string    `gorm:"uniqueIndex;type:varchar(100)"`
-	Confirmed         bool      `gorm:"default:false"`
-	CreatedAt         time.Time
-	ConfirmedAt       *time.Time
-	LastSentAt        *time.Time
-	DeletedAt         gorm.DeletedAt `gorm:"index"`
+	ID uuid.UUID `gorm:"type:uuid;primary_key"`
+	// Live subscriptions are unique per LOWER(email) and city; the index is
+	// an expression index, so it is only declared in the migrations.
+	Email     string
+	City      string
+	Frequency Frequency `gorm:"type:varchar(10);default:'DAILY'"`
+	// Tokens are stored as SHA-256 digests only. Subscriptions created before
+	// unsubscribe tokens were derived also match legacy_unsubscribe_token_hash,
+	// which is written by the migration and only ever queried.
+	ConfirmationTokenHash string    `gorm:"uniqueIndex;type:varchar(64);not null"`
+	ConfirmationSentAt    time.Time `gorm:"not null"`
+	UnsubscribeTokenHash  *string   `gorm:"uniqueIndex;type:varchar(64)"`
+	Confirmed             bool      `gorm:"default:false"`
+	CreatedAt             time.Time
+	ConfirmedAt           *time.Time
+	LastSentAt            *time.Time
+	Timezone              string  `gorm:"type:varchar(64);default:'UTC'"`
+	SendHour              int     `gorm:"default:12"`
+	WebhookURL            *string `gorm:"type:varchar(2048)"`
+	AlertMetric           *string `gorm:"type:varchar(32)"`
+	AlertComparator       *string `gorm:"type:varchar(8)"`
+	AlertThreshold        *float64
+	AlertCooldown         *int64 `gorm:"comment:cooldown in seconds"`
+	AlertTriggered        bool   `gorm:"default:false"`
+	PausedAt              *time.Time
+	PausedUntil           *time.Time `gorm:"index"`
+	ManageLinkSentAt      *time.Time
+	DeletedAt             gorm.DeletedAt `gorm:"index"`
 }
 
 func (SubscriptionModel) TableName() string {
 	return "subscriptions"
 }
+
+type OutboxMessageModel struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key"`
+	EventType   string    `gorm:"type:varchar(64);not null"`
+	Payload     []byte    `gorm:"type:jsonb;not null"`
+	Attempts    int       `gorm:"default:0"`
+	LastError   string    `gorm:"type:text"`
+	CreatedAt   time.Time
+	AvailableAt time.Time `gorm:"index:idx_outbox_pending,where:published_at IS NULL"`
+	PublishedAt *time.Time
+}
+
+func (OutboxMessageModel) TableName() string {
+	return "outbox_messages"
+}
+
+type DeliveryModel struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key"`
+	SubscriptionID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_delivery_period"`
+	Period         time.Time `gorm:"not null;uniqueIndex:idx_delivery_period"`
+	Status         string    `gorm:"type:varchar(10);not null"`
+	Attempts       int       `gorm:"default:0"`
+	Error          string    `gorm:"type:text"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	SentAt         *time.Time
+}
+
+func (DeliveryModel) TableName() string {
+	return "deliveries"
+}
+
+type DeadLetterModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key"`
+	EventType  string    `gorm:"type:varchar(64);not null"`
+	Payload    []byte    `gorm:"type:jsonb;not null"`
+	Error      string    `gorm:"type:text"`
+	Attempts   int
+	CreatedAt  time.Time `gorm:"index"`
+	ReplayedAt *time.Time
+}
+
+func (DeadLetterModel) TableName() string {
+	return "dead_letters"
+}
+
+type APIKeyModel struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key"`
+	Name       string    `gorm:"type:varchar(100);not null"`
+	Prefix     string    `gorm:"type:varchar(16);not null"`
+	KeyHash    string    `gorm:"type:char(64);uniqueIndex;not null"`
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
+
+func (APIKeyModel) TableName() string {
+	return "api_keys"
+}
+
+type SuppressionModel struct {
+	Email     string `gorm:"type:varchar(255);primary_key"`
+	Reason    string `gorm:"type:varchar(32);not null"`
+	Source    string `gorm:"type:varchar(32);not null"`
+	Detail    string `gorm:"type:text"`
+	CreatedAt time.Time
+}
+
+func (SuppressionModel) TableName() string {
+	return "suppressions"
+}
+
+type WebhookEndpointModel struct {
+	URL                 string `gorm:"type:varchar(2048);primary_key"`
+	ConsecutiveFailures int    `gorm:"not null;default:0"`
+	TotalFailures       int64  `gorm:"not null;default:0"`
+	LastError           string `gorm:"type:text"`
+	LastFailureAt       *time.Time
+	LastSuccessAt       *time.Time
+	DisabledAt          *time.Time
+}
+
+func (WebhookEndpointModel) TableName() string {
+	return "webhook_endpoints"
+}
@@ -0,0 +1,86 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+type GormOutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOutboxRepository(db *gorm.DB) *GormOutboxRepository {
+	return &GormOutboxRepository{
+		db: db,
+	}
+}
+
+func (r *GormOutboxRepository) Enqueue(ctx context.Context, message *domain.OutboxMessage) error {
+	tx := r.db.WithContext(ctx)
+
+	return tx.Create(ToOutboxModel(message)).Error
+}
+
+func (r *GormOutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	var models []OutboxMessageModel
+
+	now := time.Now().UTC()
+
+	tx := r.db.WithContext(ctx)
+
+	result := tx.Raw(`
+		UPDATE outbox_messages
+		SET available_at = ?, attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM outbox_messages
+			WHERE published_at IS NULL AND available_at <= ?
+			ORDER BY created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		now.Add(lease), now, limit,
+	).Scan(&models)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	messages := make([]domain.OutboxMessage, len(models))
+	for i := range models {
+		messages[i] = ToOutboxDomain(&models[i])
+	}
+
+	return messages, nil
+}
+
+func (r *GormOutboxRepository) MarkPublished(ctx context.Context, id uuid.UUID) error {
+	tx := r.db.WithContext(ctx)
+
+	result := tx.Model(&OutboxMessageModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"published_at": time.Now().UTC(),
+			"last_error":   "",
+		})
+
+	return result.Error
+}
+
+func (r *GormOutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string, retryAt time.Time) error {
+	tx := r.db.WithContext(ctx)
+
+	result := tx.Model(&OutboxMessageModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"available_at": retryAt,
+			"last_error":   reason,
+		})
+
+	return result.Error
+}
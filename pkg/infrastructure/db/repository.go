@@ -22,10 +22,6 @@ func NewGormRepository(db *gorm.DB) *GormRepository {
 	}
 }
 
-func (r *GormRepository) EnsureSchema() error {
-	return r.db.AutoMigrate(&SubscriptionModel{})
-}
-
 func (r *GormRepository) Save(ctx context.Context, s *domain.Subscription) error {
 	model := ToModel(s)
 
@@ -39,13 +35,24 @@ func (r *GormRepository) Save(ctx context.Context, s *domain.Subscription) error
 	return nil
 }
 
+// SaveWithOutbox stores the subscription and the outbox message atomically.
+func (r *GormRepository) SaveWithOutbox(ctx context.Context, s *domain.Subscription, message *domain_errors.OutboxMessage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(ToModel(s)).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(ToOutboxModel(message)).Error
+	})
+}
+
 func (r *GormRepository) IsSubscribed(ctx context.Context, email, city string) (bool, error) {
 	var count int64
 
 	tx := r.db.WithContext(ctx)
 
 	result := tx.Model(&SubscriptionModel{}).
-		Where("email = ? AND city = ? AND confirmed = ?", email, city, true).
+		Where("LOWER(email) = LOWER(?) AND city = ? AND confirmed = ?", email, city, true).
 		Count(&count)
 
 	if result.Error != nil {
@@ -61,7 +68,7 @@ func (r *GormRepository) IsComfirmationTokenExists(ctx context.Context, token st
 	tx := r.db.WithContext(ctx)
 
 	result := tx.Model(&SubscriptionModel{}).
-		Where("confirmation_token = ?", token).
+		Where("confirmation_token_hash = ?", domain.HashToken(token)).
 		Count(&count)
 
 	if result.Error != nil {
@@ -80,7 +87,7 @@ func (r *GormRepository) IsUnsubscribeTokenExists(ctx context.Context, token str
 	tx := r.db.WithContext(ctx)
 
 	result := tx.Model(&SubscriptionModel{}).
-		Where("unsubscribe_token = ?", token).
+		Scopes(unsubscribeTokenIs(token)).
 		Count(&count)
 
 	if result.Error != nil {
@@ -109,6 +116,23 @@ func (r *GormRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// DeleteUnconfirmedBefore permanently removes unconfirmed subscriptions whose
+// latest confirmation email was sent before cutoff, including unsubscribed
+// ones.
+func (r *GormRepository) DeleteUnconfirmedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	tx := r.db.WithContext(ctx)
+
+	result := tx.Unscoped().
+		Where("confirmed = ? AND confirmation_sent_at < ?", false, cutoff).
+		Delete(&SubscriptionModel{})
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
 func (r *GormRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Subscription, error) {
 	var model SubscriptionModel
 
@@ -126,13 +150,176 @@ func (r *GormRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.Su
 	return ToDomain(&model), nil
 }
 
+// FindByEmail returns every subscription of email, ignoring case, oldest
+// first.
+func (r *GormRepository) FindByEmail(ctx context.Context, email string) ([]*domain.Subscription, error) {
+	var models []*SubscriptionModel
+
+	tx := r.db.WithContext(ctx)
+
+	if err := tx.Where("LOWER(email) = LOWER(?)", email).Order("created_at").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return ToDomainList(models), nil
+}
+
+// FindByEmailAndCity matches the email case-insensitively, like History.
+func (r *GormRepository) FindByEmailAndCity(ctx context.Context, email, city string) (*domain.Subscription, error) {
+	var model SubscriptionModel
+
+	tx := r.db.WithContext(ctx)
+
+	result := tx.Where("LOWER(email) = LOWER(?) AND city = ?", email, city).First(&model)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, domain_errors.ErrSubscriptionNotFound
+		}
+		return nil, result.Error
+	}
+
+	return ToDomain(&model), nil
+}
+
+// History returns every subscription of email to city, oldest first,
+// including unsubscribed ones. The email is matched case-insensitively.
+func (r *GormRepository) History(ctx context.Context, email, city string) ([]*domain.Subscription, error) {
+	var models []*SubscriptionModel
+
+	tx := r.db.WithContext(ctx)
+
+	if err := tx.Unscoped().
+		Where("LOWER(email) = LOWER(?) AND city = ?", email, city).
+		Order("created_at").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return ToDomainList(models), nil
+}
+
+// List returns subscriptions matching filter, newest first.
+func (r *GormRepository) List(ctx context.Context, filter domain.SubscriptionFilter) ([]*domain.Subscription, error) {
+	var models []*SubscriptionModel
+
+	tx := applySubscriptionFilter(r.db.WithContext(ctx).Model(&SubscriptionModel{}), filter)
+
+	if filter.Limit > 0 {
+		tx = tx.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		tx = tx.Offset(filter.Offset)
+	}
+
+	if err := tx.Order("created_at DESC").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return ToDomainList(models), nil
+}
+
+func (r *GormRepository) Count(ctx context.Context, filter domain.SubscriptionFilter) (int64, error) {
+	var count int64
+
+	tx := applySubscriptionFilter(r.db.WithContext(ctx).Model(&SubscriptionModel{}), filter)
+
+	if err := tx.Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func applySubscriptionFilter(tx *gorm.DB, filter domain.SubscriptionFilter) *gorm.DB {
+	if filter.City != "" {
+		tx = tx.Where("LOWER(city) = LOWER(?)", filter.City)
+	}
+	if filter.Frequency != "" {
+		tx = tx.Where("frequency = ?", filter.Frequency)
+	}
+	if filter.Confirmed != nil {
+		tx = tx.Where("confirmed = ?", *filter.Confirmed)
+	}
+	return tx
+}
+
+// ConfirmByID confirms a subscription without its confirmation token.
+func (r *GormRepository) ConfirmByID(ctx context.Context, id uuid.UUID) error {
+	tx := r.db.WithContext(ctx)
+
+	result := tx.Model(&SubscriptionModel{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"confirmed":    true,
+			"confirmed_at": time.Now(),
+		})
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain_errors.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+func (r *GormRepository) Stats(ctx context.Context) (*domain.SubscriptionStats, error) {
+	stats := &domain.SubscriptionStats{
+		ByFrequency: map[domain.Frequency]int64{},
+	}
+
+	tx := r.db.WithContext(ctx)
+
+	var byCity []struct {
+		City      string
+		Total     int64
+		Confirmed int64
+	}
+	if err := tx.Model(&SubscriptionModel{}).
+		Select("city, COUNT(*) AS total, COUNT(*) FILTER (WHERE confirmed) AS confirmed").
+		Group("city").
+		Order("total DESC, city").
+		Scan(&byCity).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range byCity {
+		stats.Total += row.Total
+		stats.Confirmed += row.Confirmed
+		stats.ByCity = append(stats.ByCity, domain.CityStats{
+			City:      row.City,
+			Total:     row.Total,
+			Confirmed: row.Confirmed,
+		})
+	}
+
+	var byFrequency []struct {
+		Frequency string
+		Total     int64
+	}
+	if err := tx.Model(&SubscriptionModel{}).
+		Select("frequency, COUNT(*) AS total").
+		Group("frequency").
+		Scan(&byFrequency).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range byFrequency {
+		stats.ByFrequency[domain.Frequency(row.Frequency)] = row.Total
+	}
+
+	return stats, nil
+}
+
 func (r *GormRepository) Confirm(ctx context.Context, confirmationToken string) error {
 	now := time.Now()
 
 	tx := r.db.WithContext(ctx)
 
 	result := tx.Model(&SubscriptionModel{}).
-		Where("confirmation_token = ?", confirmationToken).
+		Where("confirmation_token_hash = ?", domain.HashToken(confirmationToken)).
 		Updates(map[string]interface{}{
 			"confirmed":    true,
 			"confirmed_at": now,
@@ -154,7 +341,7 @@ func (r *GormRepository) GetConfirmedSubscriptions(ctx context.Context, frequenc
 
 	tx := r.db.WithContext(ctx)
 
-	result := tx.Where("confirmed = ? AND frequency = ?", true, frequency).Find(&models)
+	result := tx.Where("confirmed = ? AND frequency = ? AND paused_at IS NULL", true, frequency).Find(&models)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -162,10 +349,18 @@ func (r *GormRepository) GetConfirmedSubscriptions(ctx context.Context, frequenc
 	subscriptions := make([]domain.Subscriber, len(models))
 	for i, model := range models {
 		subscriptions[i] = domain.Subscriber{
-			Email:            model.Email,
-			City:             model.City,
-			Frequency:        domain.Frequency(model.Frequency),
-			UnsubscribeToken: model.UnsubscribeToken,
+			ID:          model.ID,
+			Email:       model.Email,
+			City:        model.City,
+			Frequency:   domain.Frequency(model.Frequency),
+			Timezone:    model.Timezone,
+			SendHour:    model.SendHour,
+			ConfirmedAt: model.ConfirmedAt,
+			LastSentAt:  model.LastSentAt,
+		}
+
+		if model.WebhookURL != nil {
+			subscriptions[i].WebhookURL = *model.WebhookURL
 		}
 	}
 
@@ -178,7 +373,7 @@ func (r *GormRepository) FindByConfirmationToken(ctx context.Context, token stri
 	tx := r.db.WithContext(ctx)
 
 	result := tx.Model(&SubscriptionModel{}).
-		Where("confirmation_token = ?", token).
+		Where("confirmation_token_hash = ?", domain.HashToken(token)).
 		First(&model)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -196,7 +391,7 @@ func (r *GormRepository) FindByUnsubscribeToken(ctx context.Context, token strin
 	tx := r.db.WithContext(ctx)
 
 	result := tx.Model(&SubscriptionModel{}).
-		Where("unsubscribe_token = ?", token).
+		Scopes(unsubscribeTokenIs(token)).
 		First(&model)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -207,3 +402,152 @@ func (r *GormRepository) FindByUnsubscribeToken(ctx context.Context, token strin
 
 	return ToDomain(&model), nil
 }
+
+// unsubscribeTokenIs matches the subscription an unsubscribe token belongs
+// to, including tokens issued before they were derived from the ID.
+func unsubscribeTokenIs(token string) func(tx *gorm.DB) *gorm.DB {
+	digest := domain.HashToken(token)
+
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("unsubscribe_token_hash = ? OR legacy_unsubscribe_token_hash = ?", digest, digest)
+	}
+}
+
+// FindWithoutUnsubscribeTokenHash returns the IDs of subscriptions created
+// before unsubscribe tokens were derived, which still need a digest.
+func (r *GormRepository) FindWithoutUnsubscribeTokenHash(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+
+	tx := r.db.WithContext(ctx)
+
+	if err := tx.Unscoped().Model(&SubscriptionModel{}).
+		Where("unsubscribe_token_hash IS NULL").
+		Limit(limit).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+func (r *GormRepository) SetUnsubscribeTokenHash(ctx context.Context, id uuid.UUID, hash string) error {
+	tx := r.db.WithContext(ctx)
+
+	result := tx.Unscoped().Model(&SubscriptionModel{}).
+		Where("id = ?", id).
+		Update("unsubscribe_token_hash", hash)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain_errors.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+func (r *GormRepository) GetConfirmedAlertSubscriptions(ctx context.Context) ([]*domain.Subscription, error) {
+	var models []*SubscriptionModel
+
+	tx := r.db.WithContext(ctx)
+
+	result := tx.Where("confirmed = ? AND frequency = ? AND paused_at IS NULL", true, FrequencyAlert).Find(&models)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return ToDomainList(models), nil
+}
+
+func (r *GormRepository) UpdateAlertState(ctx context.Context, id uuid.UUID, triggered bool, lastSentAt *time.Time) error {
+	tx := r.db.WithContext(ctx)
+
+	updates := map[string]interface{}{
+		"alert_triggered": triggered,
+	}
+	if lastSentAt != nil {
+		updates["last_sent_at"] = *lastSentAt
+	}
+
+	result := tx.Model(&SubscriptionModel{}).
+		Where("id = ?", id).
+		Updates(updates)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain_errors.ErrSubscriptionNotFound
+	}
+
+	return nil
+}
+
+// FindExpiredPauses returns paused subscriptions whose pause ended at or
+// before now.
+func (r *GormRepository) FindExpiredPauses(ctx context.Context, now time.Time, limit int) ([]*domain.Subscription, error) {
+	var models []*SubscriptionModel
+
+	tx := r.db.WithContext(ctx)
+
+	if err := tx.Where("paused_at IS NOT NULL AND paused_until <= ?", now).
+		Order("paused_until").
+		Limit(limit).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return ToDomainList(models), nil
+}
+
+// RequestManageLinkWithOutbox stamps every subscription of the address and
+// stores the outbox message in one transaction. The NOT EXISTS covers
+// subscriptions created after the last link was sent; the per-row check
+// makes a concurrent request wait on the row locks and then match nothing.
+func (r *GormRepository) RequestManageLinkWithOutbox(ctx context.Context, email string, now time.Time, message *domain_errors.OutboxMessage) error {
+	cutoff := now.Add(-domain.MinManageLinkInterval)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&SubscriptionModel{}).
+			Where("LOWER(email) = LOWER(?) AND (manage_link_sent_at IS NULL OR manage_link_sent_at <= ?)", email, cutoff).
+			Where("NOT EXISTS (SELECT 1 FROM subscriptions recent WHERE LOWER(recent.email) = LOWER(?) AND recent.deleted_at IS NULL AND recent.manage_link_sent_at > ?)", email, cutoff).
+			Update("manage_link_sent_at", now)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrManageLinkRequestedTooSoon
+		}
+
+		return tx.Create(ToOutboxModel(message)).Error
+	})
+}
+
+// ResumeWithOutbox clears the pause and stores the outbox message in one
+// transaction. It returns ErrSubscriptionNotPaused when the subscription
+// was resumed, or paused again until a later time, in the meantime.
+func (r *GormRepository) ResumeWithOutbox(ctx context.Context, id uuid.UUID, now time.Time, message *domain_errors.OutboxMessage) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&SubscriptionModel{}).
+			Where("id = ? AND paused_at IS NOT NULL AND paused_until <= ?", id, now).
+			Updates(map[string]interface{}{
+				"paused_at":    nil,
+				"paused_until": nil,
+			})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrSubscriptionNotPaused
+		}
+
+		return tx.Create(ToOutboxModel(message)).Error
+	})
+}
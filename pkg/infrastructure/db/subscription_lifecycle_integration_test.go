@@ -0,0 +1,440 @@
+//go:build integration
+
+package db_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	"github.com/danik-tro/weather-subscriber/pkg/external/weather"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/db"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/tokens"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/usecases"
+)
+
+// Run with a disposable database:
+//
+//	TEST_DATABASE_DSN="host=localhost user=postgres password=postgres dbname=weather_test sslmode=disable" \
+//	    go test -tags integration ./pkg/infrastructure/db/...
+func openTestDatabase(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	gormDb, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+
+	sqlDb, err := gormDb.DB()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDb.Close() })
+
+	migrator, err := db.NewMigrator(sqlDb)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	require.NoError(t, gormDb.Exec("TRUNCATE subscriptions, outbox_messages, suppressions, webhook_endpoints CASCADE").Error)
+
+	return gormDb
+}
+
+var unsubscribeTokens = tokens.NewHMACUnsubscribeTokens("integration-test-secret")
+
+func newActionLinks(t *testing.T) *tokens.HMACActionLinks {
+	t.Helper()
+
+	links, err := tokens.NewHMACActionLinks("test:integration-test-secret")
+	require.NoError(t, err)
+
+	return links
+}
+
+func subscribe(t *testing.T, repository *db.GormRepository, email, city string) *entity.Subscription {
+	t.Helper()
+
+	subscription, err := entity.NewSubscription(email, city, entity.FrequencyDaily)
+	require.NoError(t, err)
+	subscription.AssignUnsubscribeToken(unsubscribeTokens.Token(subscription.ID))
+	require.NoError(t, repository.SaveWithOutbox(context.Background(), subscription, domain.NewOutboxMessage(domain.UserSubscribed, []byte(`{}`))))
+
+	return subscription
+}
+
+func TestSubscriptionLifecycle_Resubscribe(t *testing.T) {
+	ctx := context.Background()
+	repository := db.NewGormRepository(openTestDatabase(t))
+	links := newActionLinks(t)
+	confirm := usecases.NewConfirmSubscription(repository, time.Hour, links)
+	unsubscribe := usecases.NewUnsubscribeUseCase(repository, links)
+	history := usecases.NewSubscriptionHistoryUseCase(repository)
+
+	first := subscribe(t, repository, "user@example.com", "Kyiv")
+	require.NoError(t, confirm.Confirm(ctx, first.ConfirmationToken))
+	require.NoError(t, unsubscribe.Unsubscribe(ctx, unsubscribeTokens.Token(first.ID)))
+
+	_, err := repository.FindByEmailAndCity(ctx, "user@example.com", "Kyiv")
+	require.ErrorIs(t, err, domain.ErrSubscriptionNotFound)
+
+	second := subscribe(t, repository, "user@example.com", "Kyiv")
+	require.NotEqual(t, first.ConfirmationToken, second.ConfirmationToken)
+	require.NotEqual(t, unsubscribeTokens.Token(first.ID), unsubscribeTokens.Token(second.ID))
+
+	_, err = repository.FindByUnsubscribeToken(ctx, unsubscribeTokens.Token(first.ID))
+	require.ErrorIs(t, err, domain.ErrSubscriptionNotFound, "the old unsubscribe link must not reach the new subscription")
+
+	require.NoError(t, confirm.Confirm(ctx, second.ConfirmationToken))
+
+	current, err := repository.FindByEmailAndCity(ctx, "user@example.com", "Kyiv")
+	require.NoError(t, err)
+	require.Equal(t, second.ID, current.ID)
+	require.True(t, current.Confirmed)
+
+	subscribers, err := repository.GetConfirmedSubscriptions(ctx, entity.FrequencyDaily)
+	require.NoError(t, err)
+	require.Len(t, subscribers, 1)
+	require.Equal(t, second.ID, subscribers[0].ID)
+
+	got, err := history.History(ctx, "USER@example.com", "Kyiv")
+	require.NoError(t, err)
+	require.Len(t, got.Subscriptions, 2)
+	require.Equal(t, first.ID, got.Subscriptions[0].ID)
+	require.NotNil(t, got.Subscriptions[0].UnsubscribedAt)
+	require.Equal(t, second.ID, got.Current().ID)
+	require.Equal(t, 1, got.Unsubscribes())
+}
+
+func TestSubscriptionLifecycle_LiveSubscriptionsStayUnique(t *testing.T) {
+	repository := db.NewGormRepository(openTestDatabase(t))
+
+	subscribe(t, repository, "user@example.com", "Kyiv")
+
+	duplicate, err := entity.NewSubscription("user@example.com", "Kyiv", entity.FrequencyHourly)
+	require.NoError(t, err)
+	require.Error(t, repository.Save(context.Background(), duplicate))
+
+	differentCase, err := entity.NewSubscription("User@Example.com", "Kyiv", entity.FrequencyHourly)
+	require.NoError(t, err)
+	require.Error(t, repository.Save(context.Background(), differentCase))
+}
+
+func TestSubscriptionLifecycle_PurgeRemovesUnsubscribedSignups(t *testing.T) {
+	ctx := context.Background()
+	gormDb := openTestDatabase(t)
+	repository := db.NewGormRepository(gormDb)
+
+	stale := subscribe(t, repository, "stale@example.com", "Kyiv")
+	require.NoError(t, repository.Delete(ctx, stale.ID))
+	require.NoError(t, gormDb.Exec("UPDATE subscriptions SET confirmation_sent_at = ? WHERE id = ?", time.Now().Add(-48*time.Hour), stale.ID).Error)
+	fresh := subscribe(t, repository, "fresh@example.com", "Kyiv")
+
+	purged, err := usecases.NewPurgeUnconfirmedSubscriptionsUseCase(repository, 24*time.Hour).Purge(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+
+	history, err := repository.History(ctx, "stale@example.com", "Kyiv")
+	require.NoError(t, err)
+	require.Empty(t, history)
+
+	_, err = repository.FindByID(ctx, fresh.ID)
+	require.NoError(t, err)
+}
+
+func TestSubscriptionTokens_StoredAsDigests(t *testing.T) {
+	ctx := context.Background()
+	gormDb := openTestDatabase(t)
+	repository := db.NewGormRepository(gormDb)
+
+	subscription := subscribe(t, repository, "user@example.com", "Kyiv")
+
+	var stored int64
+	require.NoError(t, gormDb.Raw(
+		"SELECT COUNT(*) FROM subscriptions WHERE confirmation_token_hash = ? AND unsubscribe_token_hash = ?",
+		entity.HashToken(subscription.ConfirmationToken), entity.HashToken(unsubscribeTokens.Token(subscription.ID)),
+	).Scan(&stored).Error)
+	require.Equal(t, int64(1), stored)
+
+	found, err := repository.FindByConfirmationToken(ctx, subscription.ConfirmationToken)
+	require.NoError(t, err)
+	require.Equal(t, subscription.ID, found.ID)
+
+	exists, err := repository.IsComfirmationTokenExists(ctx, subscription.ConfirmationToken)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	exists, err = repository.IsUnsubscribeTokenExists(ctx, unsubscribeTokens.Token(subscription.ID))
+	require.NoError(t, err)
+	require.True(t, exists)
+}
+
+func TestSubscriptionTokens_LegacyLinksAndBackfill(t *testing.T) {
+	ctx := context.Background()
+	gormDb := openTestDatabase(t)
+	repository := db.NewGormRepository(gormDb)
+
+	// A row as left by the migration: only the digest of the old plaintext
+	// token, no derived token yet.
+	subscription := subscribe(t, repository, "user@example.com", "Kyiv")
+	legacyToken := "0190c1f2-7a5e-7c3d-9b1a-3f2e4d5c6b7a"
+	require.NoError(t, gormDb.Exec(
+		"UPDATE subscriptions SET unsubscribe_token_hash = NULL, legacy_unsubscribe_token_hash = ? WHERE id = ?",
+		entity.HashToken(legacyToken), subscription.ID,
+	).Error)
+
+	found, err := repository.FindByUnsubscribeToken(ctx, legacyToken)
+	require.NoError(t, err)
+	require.Equal(t, subscription.ID, found.ID)
+
+	_, err = repository.FindByUnsubscribeToken(ctx, unsubscribeTokens.Token(subscription.ID))
+	require.ErrorIs(t, err, domain.ErrSubscriptionNotFound)
+
+	backfilled, err := usecases.NewBackfillUnsubscribeTokensUseCase(repository, unsubscribeTokens).Backfill(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, backfilled)
+
+	for _, token := range []string{legacyToken, unsubscribeTokens.Token(subscription.ID)} {
+		found, err = repository.FindByUnsubscribeToken(ctx, token)
+		require.NoError(t, err)
+		require.Equal(t, subscription.ID, found.ID)
+	}
+
+	// Saving the loaded subscription keeps both digests.
+	found.Frequency = entity.FrequencyHourly
+	require.NoError(t, repository.Save(ctx, found))
+	for _, token := range []string{legacyToken, unsubscribeTokens.Token(subscription.ID)} {
+		_, err = repository.FindByUnsubscribeToken(ctx, token)
+		require.NoError(t, err)
+	}
+}
+
+func TestSubscriptionLifecycle_SignedLinks(t *testing.T) {
+	ctx := context.Background()
+	repository := db.NewGormRepository(openTestDatabase(t))
+	links := newActionLinks(t)
+	confirm := usecases.NewConfirmSubscription(repository, time.Hour, links)
+	unsubscribe := usecases.NewUnsubscribeUseCase(repository, links)
+
+	subscription := subscribe(t, repository, "user@example.com", "Kyiv")
+	link := func(action domain.LinkAction, issuedAt time.Time) string {
+		return links.Sign(domain.ActionLink{
+			SubscriptionID: subscription.ID,
+			Action:         action,
+			IssuedAt:       issuedAt,
+			ExpiresAt:      issuedAt.Add(time.Hour),
+		})
+	}
+
+	require.ErrorIs(t, confirm.Confirm(ctx, link(domain.ConfirmLinkAction, subscription.ConfirmationSentAt.Add(-time.Minute))), entity.ErrConfirmationExpired,
+		"a link issued before the latest confirmation email is superseded")
+	require.ErrorIs(t, confirm.Confirm(ctx, link(domain.UnsubscribeLinkAction, subscription.ConfirmationSentAt)), domain.ErrInvalidActionLink)
+	require.NoError(t, confirm.Confirm(ctx, link(domain.ConfirmLinkAction, subscription.ConfirmationSentAt)))
+
+	confirmed, err := repository.FindByID(ctx, subscription.ID)
+	require.NoError(t, err)
+	require.True(t, confirmed.Confirmed)
+
+	require.ErrorIs(t, unsubscribe.Unsubscribe(ctx, link(domain.UnsubscribeLinkAction, time.Now().Add(-2*time.Hour))), domain.ErrActionLinkExpired)
+	require.NoError(t, unsubscribe.Unsubscribe(ctx, link(domain.UnsubscribeLinkAction, time.Now())))
+
+	_, err = repository.FindByID(ctx, subscription.ID)
+	require.ErrorIs(t, err, domain.ErrSubscriptionNotFound)
+}
+
+func TestSuppressions_KeepFirstEntry(t *testing.T) {
+	gormDb := openTestDatabase(t)
+	repo := db.NewGormSuppressionRepository(gormDb)
+	ctx := context.Background()
+
+	suppressed, err := repo.IsSuppressed(ctx, "user@example.com")
+	require.NoError(t, err)
+	require.False(t, suppressed)
+
+	bounce := domain.EmailFeedback{Email: " User@Example.com", Type: domain.HardBounceFeedback, Detail: "550 5.1.1 no such user"}
+	require.NoError(t, repo.Save(ctx, domain.NewSuppression(bounce, "mailbox")))
+	complaint := domain.EmailFeedback{Email: "user@example.com", Type: domain.ComplaintFeedback}
+	require.NoError(t, repo.Save(ctx, domain.NewSuppression(complaint, "ses")))
+
+	suppressed, err = repo.IsSuppressed(ctx, "USER@example.com")
+	require.NoError(t, err)
+	require.True(t, suppressed)
+
+	var model db.SuppressionModel
+	require.NoError(t, gormDb.First(&model, "email = ?", "user@example.com").Error)
+	require.Equal(t, string(domain.HardBounceFeedback), model.Reason)
+	require.Equal(t, "mailbox", model.Source)
+}
+
+func TestSuppressions_BounceMailbox(t *testing.T) {
+	gormDb := openTestDatabase(t)
+	repo := db.NewGormSuppressionRepository(gormDb)
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	bounce := "Content-Type: multipart/report; report-type=delivery-status; boundary=B\r\n\r\n" +
+		"--B\r\nContent-Type: message/delivery-status\r\n\r\n" +
+		"Reporting-MTA: dns; mail.example.com\r\n\r\n" +
+		"Final-Recipient: rfc822; gone@example.com\r\nAction: failed\r\nStatus: 5.1.1\r\n\r\n" +
+		"--B--\r\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bounce.eml"), []byte(bounce), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "newsletter.eml"), []byte("Subject: hi\r\n\r\nhello\r\n"), 0o644))
+
+	suppressed, err := usecases.NewProcessBounceMailboxUseCase(repo, dir).ProcessMailbox(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, suppressed)
+
+	require.FileExists(t, filepath.Join(dir, "processed", "bounce.eml"))
+	require.FileExists(t, filepath.Join(dir, "failed", "newsletter.eml"))
+
+	isSuppressed, err := repo.IsSuppressed(ctx, "gone@example.com")
+	require.NoError(t, err)
+	require.True(t, isSuppressed)
+
+	subscribeUC := usecases.NewSubscribeWeatherUseCase(db.NewGormRepository(gormDb), weather.WeatherService{}, unsubscribeTokens, repo)
+	err = subscribeUC.Subscribe(ctx, "gone@example.com", "Kyiv", entity.FrequencyDaily, entity.DefaultDeliveryPreferences())
+	require.ErrorIs(t, err, domain.ErrEmailSuppressed)
+}
+
+func TestWebhookEndpoints_DisableAfterRepeatedFailures(t *testing.T) {
+	repo := db.NewGormWebhookEndpointRepository(openTestDatabase(t))
+	ctx := context.Background()
+	url := "https://hooks.example.com/weather"
+	now := time.Now().UTC().Truncate(time.Second)
+
+	_, err := repo.Find(ctx, url)
+	require.ErrorIs(t, err, domain.ErrWebhookEndpointNotFound)
+	require.ErrorIs(t, repo.Enable(ctx, url), domain.ErrWebhookEndpointNotFound)
+
+	endpoint, err := repo.RecordFailure(ctx, url, "HTTP 500", now, 3)
+	require.NoError(t, err)
+	require.Equal(t, 1, endpoint.ConsecutiveFailures)
+	require.False(t, endpoint.Disabled())
+
+	require.NoError(t, repo.RecordSuccess(ctx, url, now))
+	_, err = repo.RecordFailure(ctx, url, "HTTP 500", now, 3)
+	require.NoError(t, err)
+	_, err = repo.RecordFailure(ctx, url, "HTTP 502", now, 3)
+	require.NoError(t, err)
+	endpoint, err = repo.RecordFailure(ctx, url, "timeout", now, 3)
+	require.NoError(t, err)
+	require.Equal(t, 3, endpoint.ConsecutiveFailures)
+	require.Equal(t, int64(4), endpoint.TotalFailures)
+	require.Equal(t, "timeout", endpoint.LastError)
+	require.True(t, endpoint.Disabled())
+
+	require.NoError(t, repo.Enable(ctx, url))
+	endpoint, err = repo.Find(ctx, url)
+	require.NoError(t, err)
+	require.False(t, endpoint.Disabled())
+	require.Equal(t, 0, endpoint.ConsecutiveFailures)
+	require.Equal(t, int64(4), endpoint.TotalFailures)
+
+	endpoints, err := repo.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, endpoints, 1)
+}
+
+func TestConfirmedSubscriptions_IncludeWebhookURL(t *testing.T) {
+	ctx := context.Background()
+	repository := db.NewGormRepository(openTestDatabase(t))
+
+	subscription, err := entity.NewSubscription("hooks@example.com", "Kyiv", entity.FrequencyDaily)
+	require.NoError(t, err)
+	prefs := entity.DefaultDeliveryPreferences()
+	prefs.WebhookURL = "https://hooks.example.com/weather"
+	require.NoError(t, subscription.ApplyDeliveryPreferences(prefs))
+	subscription.AssignUnsubscribeToken(unsubscribeTokens.Token(subscription.ID))
+	require.NoError(t, repository.SaveWithOutbox(ctx, subscription, domain.NewOutboxMessage(domain.UserSubscribed, []byte(`{}`))))
+	require.NoError(t, repository.ConfirmByID(ctx, subscription.ID))
+
+	subscribers, err := repository.GetConfirmedSubscriptions(ctx, entity.FrequencyDaily)
+	require.NoError(t, err)
+	require.Len(t, subscribers, 1)
+	require.Equal(t, prefs.WebhookURL, subscribers[0].WebhookURL)
+}
+
+func TestRequestManageLink_EnqueuesOutboxMessage(t *testing.T) {
+	ctx := context.Background()
+	gormDb := openTestDatabase(t)
+	repository := db.NewGormRepository(gormDb)
+	subscribe(t, repository, "manage@example.com", "Kyiv")
+
+	manageTokens := tokens.NewHMACManageTokens("integration-test-secret", time.Hour)
+	requestLink := usecases.NewRequestManageLinkUseCase(repository, manageTokens)
+	require.NoError(t, requestLink.RequestLink(ctx, "manage@example.com"))
+
+	var count int64
+	require.NoError(t, gormDb.Model(&db.OutboxMessageModel{}).Where("event_type = ?", domain.ManageLinkRequested).Count(&count).Error)
+	require.Equal(t, int64(1), count)
+}
+
+func TestRequestManageLinkWithOutbox_ThrottlesPerEmail(t *testing.T) {
+	ctx := context.Background()
+	gormDb := openTestDatabase(t)
+	repository := db.NewGormRepository(gormDb)
+	subscribe(t, repository, "throttle@example.com", "Kyiv")
+
+	message := func() *domain.OutboxMessage {
+		return domain.NewOutboxMessage(domain.ManageLinkRequested, []byte(`{}`))
+	}
+	sentAt := time.Now().UTC().Truncate(time.Microsecond)
+	require.NoError(t, repository.RequestManageLinkWithOutbox(ctx, "throttle@example.com", sentAt, message()))
+
+	// A subscription created after the link was sent does not reopen the window.
+	subscribe(t, repository, "throttle@example.com", "Lviv")
+	soon := sentAt.Add(entity.MinManageLinkInterval / 2)
+	require.ErrorIs(t, repository.RequestManageLinkWithOutbox(ctx, "Throttle@Example.com", soon, message()), entity.ErrManageLinkRequestedTooSoon)
+
+	later := sentAt.Add(entity.MinManageLinkInterval)
+	require.NoError(t, repository.RequestManageLinkWithOutbox(ctx, "throttle@example.com", later, message()))
+
+	var count int64
+	require.NoError(t, gormDb.Model(&db.OutboxMessageModel{}).Where("event_type = ?", domain.ManageLinkRequested).Count(&count).Error)
+	require.Equal(t, int64(2), count)
+}
+
+func TestResumeWithOutbox_SkipsExtendedPause(t *testing.T) {
+	ctx := context.Background()
+	repository := db.NewGormRepository(openTestDatabase(t))
+	subscription := subscribe(t, repository, "paused@example.com", "Kyiv")
+
+	now := time.Now()
+	until := now.Add(time.Hour)
+	require.NoError(t, subscription.Pause(now, &until))
+	require.NoError(t, repository.Save(ctx, subscription))
+
+	// The subscriber extends the pause after the resume job found it expired.
+	expiredAt := until.Add(time.Minute)
+	extended := now.Add(24 * time.Hour)
+	require.NoError(t, subscription.Pause(now, &extended))
+	require.NoError(t, repository.Save(ctx, subscription))
+
+	message := domain.NewOutboxMessage(domain.SubscriptionResumed, []byte(`{}`))
+	require.ErrorIs(t, repository.ResumeWithOutbox(ctx, subscription.ID, expiredAt, message), entity.ErrSubscriptionNotPaused)
+
+	stored, err := repository.FindByID(ctx, subscription.ID)
+	require.NoError(t, err)
+	require.True(t, stored.IsPaused())
+
+	require.NoError(t, repository.ResumeWithOutbox(ctx, subscription.ID, extended, message))
+}
+
+func TestFindByEmailAndCity_IgnoresEmailCase(t *testing.T) {
+	repository := db.NewGormRepository(openTestDatabase(t))
+	subscription := subscribe(t, repository, "Mixed.Case@example.com", "Kyiv")
+
+	found, err := repository.FindByEmailAndCity(context.Background(), "mixed.case@EXAMPLE.com", "Kyiv")
+	require.NoError(t, err)
+	require.Equal(t, subscription.ID, found.ID)
+}
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+type GormSuppressionRepository struct {
+	db *gorm.DB
+}
+
+func NewGormSuppressionRepository(db *gorm.DB) *GormSuppressionRepository {
+	return &GormSuppressionRepository{
+		db: db,
+	}
+}
+
+func (r *GormSuppressionRepository) Save(ctx context.Context, s *domain.Suppression) error {
+	tx := r.db.WithContext(ctx)
+
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(ToSuppressionModel(s)).Error
+}
+
+func (r *GormSuppressionRepository) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	var count int64
+
+	tx := r.db.WithContext(ctx)
+
+	if err := tx.Model(&SuppressionModel{}).Where("email = ?", domain.NormalizeEmail(email)).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+type GormWebhookEndpointRepository struct {
+	db *gorm.DB
+}
+
+func NewGormWebhookEndpointRepository(db *gorm.DB) *GormWebhookEndpointRepository {
+	return &GormWebhookEndpointRepository{
+		db: db,
+	}
+}
+
+func (r *GormWebhookEndpointRepository) Find(ctx context.Context, url string) (*domain.WebhookEndpoint, error) {
+	var model WebhookEndpointModel
+
+	tx := r.db.WithContext(ctx)
+
+	if err := tx.Where("url = ?", url).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrWebhookEndpointNotFound
+		}
+		return nil, err
+	}
+
+	return ToWebhookEndpoint(&model), nil
+}
+
+func (r *GormWebhookEndpointRepository) List(ctx context.Context) ([]domain.WebhookEndpoint, error) {
+	var models []WebhookEndpointModel
+
+	tx := r.db.WithContext(ctx)
+
+	if err := tx.Order("url").Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]domain.WebhookEndpoint, len(models))
+	for i := range models {
+		endpoints[i] = *ToWebhookEndpoint(&models[i])
+	}
+
+	return endpoints, nil
+}
+
+func (r *GormWebhookEndpointRepository) RecordSuccess(ctx context.Context, url string, at time.Time) error {
+	tx := r.db.WithContext(ctx)
+
+	return tx.Exec(`
+		INSERT INTO webhook_endpoints (url, consecutive_failures, total_failures, last_success_at)
+		VALUES (?, 0, 0, ?)
+		ON CONFLICT (url) DO UPDATE SET
+			consecutive_failures = 0,
+			last_success_at = EXCLUDED.last_success_at`,
+		url, at,
+	).Error
+}
+
+// RecordFailure increments the counters in a single upsert so concurrent
+// deliveries to the same URL are all counted.
+func (r *GormWebhookEndpointRepository) RecordFailure(ctx context.Context, url, reason string, at time.Time, disableAfter int) (*domain.WebhookEndpoint, error) {
+	var model WebhookEndpointModel
+
+	tx := r.db.WithContext(ctx)
+
+	result := tx.Raw(`
+		INSERT INTO webhook_endpoints (url, consecutive_failures, total_failures, last_error, last_failure_at, disabled_at)
+		VALUES (@url, 1, 1, @reason, @at, CASE WHEN @disable_after <= 1 THEN @at::timestamptz END)
+		ON CONFLICT (url) DO UPDATE SET
+			consecutive_failures = webhook_endpoints.consecutive_failures + 1,
+			total_failures = webhook_endpoints.total_failures + 1,
+			last_error = EXCLUDED.last_error,
+			last_failure_at = EXCLUDED.last_failure_at,
+			disabled_at = COALESCE(
+				webhook_endpoints.disabled_at,
+				CASE WHEN webhook_endpoints.consecutive_failures + 1 >= @disable_after THEN EXCLUDED.last_failure_at END
+			)
+		RETURNING *`,
+		map[string]interface{}{"url": url, "reason": reason, "at": at, "disable_after": disableAfter},
+	).Scan(&model)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return ToWebhookEndpoint(&model), nil
+}
+
+func (r *GormWebhookEndpointRepository) Enable(ctx context.Context, url string) error {
+	tx := r.db.WithContext(ctx)
+
+	result := tx.Model(&WebhookEndpointModel{}).
+		Where("url = ?", url).
+		Updates(map[string]interface{}{"consecutive_failures": 0, "disabled_at": nil})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrWebhookEndpointNotFound
+	}
+
+	return nil
+}
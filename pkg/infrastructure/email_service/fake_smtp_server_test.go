@@ -0,0 +1,164 @@
+package smtp
+
+import (
+	"encoding/base64"
+	"io"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+// fakeSMTPServer speaks just enough SMTP for gomail: EHLO, AUTH PLAIN,
+// MAIL, RCPT, DATA, RSET, NOOP and QUIT. Recipients starting with
+// "rejected@" get a 550 reply.
+type fakeSMTPServer struct {
+	listener net.Listener
+	username string
+	password string
+
+	mu          sync.Mutex
+	connections int
+	active      map[net.Conn]bool
+	messages    []fakeMessage
+}
+
+func startFakeSMTPServer(t *testing.T, username, password string) *fakeSMTPServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &fakeSMTPServer{listener: listener, username: username, password: password, active: map[net.Conn]bool{}}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+
+	return server
+}
+
+func (s *fakeSMTPServer) config() SMTPConfig {
+	host, port, _ := net.SplitHostPort(s.listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+
+	return SMTPConfig{Host: host, Port: portNumber, Username: s.username, Password: s.password, From: "weather@example.com"}
+}
+
+func (s *fakeSMTPServer) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+func (s *fakeSMTPServer) Messages() []fakeMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]fakeMessage(nil), s.messages...)
+}
+
+// DropConnections closes every open session, as a server does with idle
+// clients.
+func (s *fakeSMTPServer) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.active {
+		conn.Close()
+	}
+}
+
+func (s *fakeSMTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.connections++
+		s.active[conn] = true
+		s.mu.Unlock()
+
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTPServer) handle(conn net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.active, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	text := textproto.NewConn(conn)
+	reply := func(line string) { text.PrintfLine("%s", line) }
+
+	reply("220 fake ESMTP")
+
+	var message fakeMessage
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		command, argument, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(command) {
+		case "EHLO", "HELO":
+			if s.username != "" {
+				reply("250-fake")
+				reply("250 AUTH PLAIN")
+			} else {
+				reply("250 fake")
+			}
+		case "AUTH":
+			_, encoded, _ := strings.Cut(argument, " ")
+			credentials, _ := base64.StdEncoding.DecodeString(encoded)
+			if string(credentials) == "\x00"+s.username+"\x00"+s.password {
+				reply("235 authenticated")
+			} else {
+				reply("535 authentication failed")
+			}
+		case "MAIL":
+			message = fakeMessage{From: strings.Trim(strings.TrimPrefix(argument, "FROM:"), "<>")}
+			reply("250 ok")
+		case "RCPT":
+			recipient := strings.Trim(strings.TrimPrefix(argument, "TO:"), "<>")
+			if strings.HasPrefix(recipient, "rejected@") {
+				reply("550 mailbox unavailable")
+				continue
+			}
+			message.To = append(message.To, recipient)
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			data, err := io.ReadAll(text.DotReader())
+			if err != nil {
+				return
+			}
+			message.Data = string(data)
+
+			s.mu.Lock()
+			s.messages = append(s.messages, message)
+			s.mu.Unlock()
+
+			reply("250 queued")
+		case "RSET", "NOOP":
+			reply("250 ok")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package feedback
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/textproto"
+	"strings"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+// ParseDSN parses a delivery status notification (RFC 3464), the
+// multipart/report message a mail server sends back when it cannot deliver.
+// Recipients that failed with a 5.x.x status are hard bounces; other failed
+// or delayed recipients are soft bounces.
+func ParseDSN(r io.Reader) ([]domain.EmailFeedback, error) {
+	message, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, invalidPayload(err)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, invalidPayload(err)
+	}
+
+	if mediaType != "multipart/report" || !strings.EqualFold(params["report-type"], "delivery-status") {
+		return nil, invalidPayload(fmt.Errorf("not a delivery status notification: %s", mediaType))
+	}
+
+	parts := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			return nil, invalidPayload(errors.New("delivery status part not found"))
+		}
+		if err != nil {
+			return nil, invalidPayload(err)
+		}
+
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if partType == "message/delivery-status" {
+			return parseDeliveryStatus(part)
+		}
+	}
+}
+
+// parseDeliveryStatus reads the per-message field block followed by one
+// block per recipient.
+func parseDeliveryStatus(r io.Reader) ([]domain.EmailFeedback, error) {
+	reader := textproto.NewReader(bufio.NewReader(r))
+
+	var feedback []domain.EmailFeedback
+	for {
+		fields, err := reader.ReadMIMEHeader()
+		if len(fields) > 0 {
+			if item, ok := recipientFeedback(fields); ok {
+				feedback = append(feedback, item)
+			}
+		}
+
+		if err == io.EOF {
+			return feedback, nil
+		}
+		if err != nil {
+			return nil, invalidPayload(err)
+		}
+	}
+}
+
+func recipientFeedback(fields textproto.MIMEHeader) (domain.EmailFeedback, bool) {
+	recipient := typedField(fields.Get("Final-Recipient"))
+	if recipient == "" {
+		recipient = typedField(fields.Get("Original-Recipient"))
+	}
+	if recipient == "" {
+		return domain.EmailFeedback{}, false
+	}
+
+	status := fields.Get("Status")
+	detail := typedField(fields.Get("Diagnostic-Code"))
+	if detail == "" {
+		detail = status
+	}
+
+	switch strings.ToLower(fields.Get("Action")) {
+	case "failed":
+		if strings.HasPrefix(status, "5.") {
+			return domain.EmailFeedback{Email: recipient, Type: domain.HardBounceFeedback, Detail: detail}, true
+		}
+		return domain.EmailFeedback{Email: recipient, Type: domain.SoftBounceFeedback, Detail: detail}, true
+	case "delayed":
+		return domain.EmailFeedback{Email: recipient, Type: domain.SoftBounceFeedback, Detail: detail}, true
+	}
+
+	return domain.EmailFeedback{}, false
+}
+
+// typedField strips the type from a field such as
+// "rfc822; user@example.com" or "smtp; 550 5.1.1 User unknown".
+func typedField(value string) string {
+	if _, rest, ok := strings.Cut(value, ";"); ok {
+		value = rest
+	}
+	return strings.TrimSpace(value)
+}
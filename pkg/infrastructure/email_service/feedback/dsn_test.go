@@ -0,0 +1,64 @@
+package feedback
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+const bounceMessage = "From: MAILER-DAEMON@mail.example.com\r\n" +
+	"To: weather@example.com\r\n" +
+	"Subject: Undelivered Mail Returned to Sender\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/report; report-type=delivery-status; boundary=\"BOUNDARY\"\r\n" +
+	"\r\n" +
+	"--BOUNDARY\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"\r\n" +
+	"Your message could not be delivered.\r\n" +
+	"--BOUNDARY\r\n" +
+	"Content-Type: message/delivery-status\r\n" +
+	"\r\n" +
+	"Reporting-MTA: dns; mail.example.com\r\n" +
+	"Arrival-Date: Sun, 1 Jun 2025 12:00:00 +0000\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; gone@example.com\r\n" +
+	"Action: failed\r\n" +
+	"Status: 5.1.1\r\n" +
+	"Diagnostic-Code: smtp; 550 5.1.1 <gone@example.com>: Recipient address rejected\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; full@example.com\r\n" +
+	"Action: failed\r\n" +
+	"Status: 4.2.2\r\n" +
+	"\r\n" +
+	"Final-Recipient: rfc822; ok@example.com\r\n" +
+	"Action: delivered\r\n" +
+	"Status: 2.0.0\r\n" +
+	"\r\n" +
+	"--BOUNDARY\r\n" +
+	"Content-Type: text/rfc822-headers\r\n" +
+	"\r\n" +
+	"Subject: Weather update\r\n" +
+	"--BOUNDARY--\r\n"
+
+func TestParseDSN(t *testing.T) {
+	feedback, err := ParseDSN(strings.NewReader(bounceMessage))
+	require.NoError(t, err)
+
+	assert.Equal(t, []domain.EmailFeedback{
+		{Email: "gone@example.com", Type: domain.HardBounceFeedback, Detail: "550 5.1.1 <gone@example.com>: Recipient address rejected"},
+		{Email: "full@example.com", Type: domain.SoftBounceFeedback, Detail: "4.2.2"},
+	}, feedback)
+}
+
+func TestParseDSNRejectsOtherMessages(t *testing.T) {
+	_, err := ParseDSN(strings.NewReader("Subject: hello\r\nContent-Type: text/plain\r\n\r\nhi\r\n"))
+	assert.ErrorIs(t, err, domain.ErrInvalidFeedbackPayload)
+
+	_, err = ParseDSN(strings.NewReader("Content-Type: multipart/report; report-type=delivery-status; boundary=B\r\n\r\n--B\r\nContent-Type: text/plain\r\n\r\nhi\r\n--B--\r\n"))
+	assert.ErrorIs(t, err, domain.ErrInvalidFeedbackPayload)
+}
@@ -0,0 +1,80 @@
+// Package feedback parses bounce and complaint notifications: webhook
+// payloads from mail providers and delivery status notifications returned to
+// a local mailbox.
+package feedback
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+const (
+	ProviderGeneric  = "generic"
+	ProviderSES      = "ses"
+	ProviderSendGrid = "sendgrid"
+)
+
+var parsers = map[string]func([]byte) ([]domain.EmailFeedback, error){
+	ProviderGeneric:  ParseGeneric,
+	ProviderSES:      ParseSES,
+	ProviderSendGrid: ParseSendGrid,
+}
+
+// ParseWebhook parses a notification posted by provider. Errors wrap
+// domain.ErrUnknownFeedbackProvider or domain.ErrInvalidFeedbackPayload.
+func ParseWebhook(provider string, payload []byte) ([]domain.EmailFeedback, error) {
+	parse, ok := parsers[provider]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", domain.ErrUnknownFeedbackProvider, provider)
+	}
+
+	return parse(payload)
+}
+
+type genericFeedback struct {
+	Email  string                   `json:"email"`
+	Type   domain.EmailFeedbackType `json:"type"`
+	Detail string                   `json:"detail"`
+}
+
+// ParseGeneric parses a feedback object, or an array of them, of the form
+// {"email": "...", "type": "hard_bounce" | "soft_bounce" | "complaint",
+// "detail": "..."}.
+func ParseGeneric(payload []byte) ([]domain.EmailFeedback, error) {
+	var items []genericFeedback
+	if bytes.HasPrefix(bytes.TrimSpace(payload), []byte("[")) {
+		if err := json.Unmarshal(payload, &items); err != nil {
+			return nil, invalidPayload(err)
+		}
+	} else {
+		var item genericFeedback
+		if err := json.Unmarshal(payload, &item); err != nil {
+			return nil, invalidPayload(err)
+		}
+		items = append(items, item)
+	}
+
+	feedback := make([]domain.EmailFeedback, len(items))
+	for i, item := range items {
+		switch item.Type {
+		case domain.HardBounceFeedback, domain.SoftBounceFeedback, domain.ComplaintFeedback:
+		default:
+			return nil, invalidPayload(fmt.Errorf("unknown feedback type %q", item.Type))
+		}
+
+		if item.Email == "" {
+			return nil, invalidPayload(fmt.Errorf("feedback without an email address"))
+		}
+
+		feedback[i] = domain.EmailFeedback{Email: item.Email, Type: item.Type, Detail: item.Detail}
+	}
+
+	return feedback, nil
+}
+
+func invalidPayload(err error) error {
+	return fmt.Errorf("%w: %w", domain.ErrInvalidFeedbackPayload, err)
+}
@@ -0,0 +1,39 @@
+package feedback
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+func TestParseGeneric(t *testing.T) {
+	feedback, err := ParseWebhook(ProviderGeneric, []byte(`{"email":"a@example.com","type":"complaint"}`))
+	require.NoError(t, err)
+	assert.Equal(t, []domain.EmailFeedback{{Email: "a@example.com", Type: domain.ComplaintFeedback}}, feedback)
+
+	feedback, err = ParseWebhook(ProviderGeneric, []byte(`[
+		{"email":"a@example.com","type":"hard_bounce","detail":"550 5.1.1 unknown user"},
+		{"email":"b@example.com","type":"soft_bounce"}
+	]`))
+	require.NoError(t, err)
+	assert.Equal(t, []domain.EmailFeedback{
+		{Email: "a@example.com", Type: domain.HardBounceFeedback, Detail: "550 5.1.1 unknown user"},
+		{Email: "b@example.com", Type: domain.SoftBounceFeedback},
+	}, feedback)
+}
+
+func TestParseWebhookErrors(t *testing.T) {
+	_, err := ParseWebhook("mailgun", []byte(`{}`))
+	assert.ErrorIs(t, err, domain.ErrUnknownFeedbackProvider)
+
+	for _, payload := range []string{`not json`, `{"email":"a@example.com","type":"opened"}`, `{"type":"complaint"}`} {
+		_, err := ParseWebhook(ProviderGeneric, []byte(payload))
+		assert.ErrorIs(t, err, domain.ErrInvalidFeedbackPayload, payload)
+	}
+
+	_, err = ParseWebhook(ProviderSendGrid, []byte(`{"event":"bounce"}`))
+	assert.ErrorIs(t, err, domain.ErrInvalidFeedbackPayload)
+}
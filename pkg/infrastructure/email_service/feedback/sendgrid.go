@@ -0,0 +1,40 @@
+package feedback
+
+import (
+	"encoding/json"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+type sendGridEvent struct {
+	Email  string `json:"email"`
+	Event  string `json:"event"`
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+// ParseSendGrid parses a SendGrid event webhook batch. Bounces are hard
+// unless SendGrid marks them as blocked, spam reports are complaints and
+// other events are ignored.
+func ParseSendGrid(payload []byte) ([]domain.EmailFeedback, error) {
+	var events []sendGridEvent
+	if err := json.Unmarshal(payload, &events); err != nil {
+		return nil, invalidPayload(err)
+	}
+
+	var feedback []domain.EmailFeedback
+	for _, event := range events {
+		switch event.Event {
+		case "bounce":
+			feedbackType := domain.HardBounceFeedback
+			if event.Type == "blocked" {
+				feedbackType = domain.SoftBounceFeedback
+			}
+			feedback = append(feedback, domain.EmailFeedback{Email: event.Email, Type: feedbackType, Detail: event.Reason})
+		case "spamreport":
+			feedback = append(feedback, domain.EmailFeedback{Email: event.Email, Type: domain.ComplaintFeedback})
+		}
+	}
+
+	return feedback, nil
+}
@@ -0,0 +1,26 @@
+package feedback
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+func TestParseSendGrid(t *testing.T) {
+	feedback, err := ParseSendGrid([]byte(`[
+		{"email":"gone@example.com","event":"bounce","type":"bounce","reason":"550 5.1.1 user unknown"},
+		{"email":"busy@example.com","event":"bounce","type":"blocked","reason":"421 try later"},
+		{"email":"angry@example.com","event":"spamreport"},
+		{"email":"happy@example.com","event":"delivered"}
+	]`))
+	require.NoError(t, err)
+
+	assert.Equal(t, []domain.EmailFeedback{
+		{Email: "gone@example.com", Type: domain.HardBounceFeedback, Detail: "550 5.1.1 user unknown"},
+		{Email: "busy@example.com", Type: domain.SoftBounceFeedback, Detail: "421 try later"},
+		{Email: "angry@example.com", Type: domain.ComplaintFeedback},
+	}, feedback)
+}
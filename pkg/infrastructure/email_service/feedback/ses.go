@@ -0,0 +1,91 @@
+package feedback
+
+import (
+	"encoding/json"
+	"log"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+// snsEnvelope is how SNS delivers SES notifications to an HTTP endpoint.
+type snsEnvelope struct {
+	Type         string `json:"Type"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+type sesRecipient struct {
+	EmailAddress   string `json:"emailAddress"`
+	DiagnosticCode string `json:"diagnosticCode"`
+}
+
+type sesNotification struct {
+	NotificationType string `json:"notificationType"`
+	// EventType replaces NotificationType in configuration set events.
+	EventType string `json:"eventType"`
+	Bounce    struct {
+		BounceType        string         `json:"bounceType"`
+		BounceSubType     string         `json:"bounceSubType"`
+		BouncedRecipients []sesRecipient `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Complaint struct {
+		ComplaintFeedbackType string         `json:"complaintFeedbackType"`
+		ComplainedRecipients  []sesRecipient `json:"complainedRecipients"`
+	} `json:"complaint"`
+}
+
+// ParseSES parses an SES bounce or complaint notification, either posted
+// directly or wrapped in an SNS message. Other notification types yield no
+// feedback. SNS subscription confirmations are logged, as the subscription
+// has to be confirmed by visiting SubscribeURL.
+func ParseSES(payload []byte) ([]domain.EmailFeedback, error) {
+	var envelope snsEnvelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, invalidPayload(err)
+	}
+
+	switch envelope.Type {
+	case "SubscriptionConfirmation":
+		log.Printf("Confirm the SES feedback SNS subscription at %s", envelope.SubscribeURL)
+		return nil, nil
+	case "Notification":
+		payload = []byte(envelope.Message)
+	}
+
+	var notification sesNotification
+	if err := json.Unmarshal(payload, &notification); err != nil {
+		return nil, invalidPayload(err)
+	}
+
+	notificationType := notification.NotificationType
+	if notificationType == "" {
+		notificationType = notification.EventType
+	}
+
+	var feedback []domain.EmailFeedback
+	switch notificationType {
+	case "Bounce":
+		feedbackType := domain.SoftBounceFeedback
+		if notification.Bounce.BounceType == "Permanent" {
+			feedbackType = domain.HardBounceFeedback
+		}
+
+		for _, recipient := range notification.Bounce.BouncedRecipients {
+			detail := recipient.DiagnosticCode
+			if detail == "" {
+				detail = notification.Bounce.BounceType + "/" + notification.Bounce.BounceSubType
+			}
+			feedback = append(feedback, domain.EmailFeedback{Email: recipient.EmailAddress, Type: feedbackType, Detail: detail})
+		}
+	case "Complaint":
+		for _, recipient := range notification.Complaint.ComplainedRecipients {
+			feedback = append(feedback, domain.EmailFeedback{
+				Email:  recipient.EmailAddress,
+				Type:   domain.ComplaintFeedback,
+				Detail: notification.Complaint.ComplaintFeedbackType,
+			})
+		}
+	}
+
+	return feedback, nil
+}
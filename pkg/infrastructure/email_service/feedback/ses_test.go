@@ -0,0 +1,58 @@
+package feedback
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+const sesBounce = `{
+	"notificationType": "Bounce",
+	"bounce": {
+		"bounceType": "Permanent",
+		"bounceSubType": "General",
+		"bouncedRecipients": [
+			{"emailAddress": "gone@example.com", "diagnosticCode": "smtp; 550 5.1.1 user unknown"},
+			{"emailAddress": "old@example.com"}
+		]
+	}
+}`
+
+func TestParseSESBounce(t *testing.T) {
+	feedback, err := ParseSES([]byte(sesBounce))
+	require.NoError(t, err)
+	assert.Equal(t, []domain.EmailFeedback{
+		{Email: "gone@example.com", Type: domain.HardBounceFeedback, Detail: "smtp; 550 5.1.1 user unknown"},
+		{Email: "old@example.com", Type: domain.HardBounceFeedback, Detail: "Permanent/General"},
+	}, feedback)
+
+	feedback, err = ParseSES([]byte(`{"eventType":"Bounce","bounce":{"bounceType":"Transient","bouncedRecipients":[{"emailAddress":"full@example.com"}]}}`))
+	require.NoError(t, err)
+	require.Len(t, feedback, 1)
+	assert.Equal(t, domain.SoftBounceFeedback, feedback[0].Type)
+}
+
+func TestParseSESComplaintInSNSEnvelope(t *testing.T) {
+	message := `{"notificationType":"Complaint","complaint":{"complaintFeedbackType":"abuse","complainedRecipients":[{"emailAddress":"angry@example.com"}]}}`
+	envelope, err := json.Marshal(map[string]string{"Type": "Notification", "Message": message})
+	require.NoError(t, err)
+
+	feedback, err := ParseSES(envelope)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.EmailFeedback{{Email: "angry@example.com", Type: domain.ComplaintFeedback, Detail: "abuse"}}, feedback)
+}
+
+func TestParseSESIgnoresOtherMessages(t *testing.T) {
+	for _, payload := range []string{
+		`{"Type":"SubscriptionConfirmation","SubscribeURL":"https://sns.example.com/confirm"}`,
+		`{"notificationType":"Delivery","delivery":{"recipients":["a@example.com"]}}`,
+	} {
+		feedback, err := ParseSES([]byte(payload))
+		require.NoError(t, err)
+		assert.Empty(t, feedback)
+	}
+}
@@ -0,0 +1,56 @@
+package smtp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+// FileSink writes each message to its own .eml file in a directory instead
+// of sending it, for development and CI. The files open in any mail client.
+type FileSink struct {
+	dir  string
+	from string
+	now  func() time.Time
+}
+
+func NewFileSink(dir, from string) (*FileSink, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	return &FileSink{dir: dir, from: from, now: time.Now}, nil
+}
+
+func (s *FileSink) SendMessage(ctx context.Context, recipient string, subject string, body string, headers domain.EmailHeaders) error {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrEmailTransient, err)
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", s.now().UTC().Format("20060102T150405.000000000Z"), hex.EncodeToString(suffix))
+	path := filepath.Join(s.dir, name)
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("%w: failed to create %s: %w", domain.ErrEmailTransient, path, err)
+	}
+
+	if _, err := newMessage(s.from, recipient, subject, body, headers).WriteTo(file); err != nil {
+		file.Close()
+		os.Remove(path)
+		return fmt.Errorf("%w: failed to write %s: %w", domain.ErrEmailTransient, path, err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("%w: failed to write %s: %w", domain.ErrEmailTransient, path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package smtp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+func TestFileSinkWritesEmlFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	sink, err := NewFileSink(dir, "weather@example.com")
+	require.NoError(t, err)
+
+	headers := domain.ListUnsubscribeHeaders("https://weather.example.com/unsubscribe/token")
+	require.NoError(t, sink.SendMessage(context.Background(), "user@example.com", "Weather update", "<p>Sunny</p>", headers))
+	require.NoError(t, sink.SendMessage(context.Background(), "other@example.com", "Weather update", "<p>Rain</p>", nil))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	contents := ""
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		contents += string(data)
+	}
+
+	assert.Contains(t, contents, "To: user@example.com")
+	assert.Contains(t, contents, "To: other@example.com")
+	assert.Contains(t, contents, "From: weather@example.com")
+	assert.Contains(t, contents, "List-Unsubscribe: <https://weather.example.com/unsubscribe/token>")
+	assert.Contains(t, contents, "List-Unsubscribe-Post: List-Unsubscribe=One-Click")
+	assert.Contains(t, contents, "Content-Type: multipart/alternative")
+	assert.Contains(t, contents, "Content-Type: text/plain; charset=UTF-8\r\n\r\nSunny")
+	assert.Contains(t, contents, "<p>Sunny</p>")
+}
+
+func TestClassifySMTPError(t *testing.T) {
+	assert.ErrorIs(t, classifySMTPError(&textproto.Error{Code: 550, Msg: "mailbox unavailable"}), domain.ErrEmailPermanent)
+	assert.ErrorIs(t, classifySMTPError(&textproto.Error{Code: 535, Msg: "authentication failed"}), domain.ErrEmailPermanent)
+	assert.ErrorIs(t, classifySMTPError(&textproto.Error{Code: 451, Msg: "try again later"}), domain.ErrEmailTransient)
+	assert.ErrorIs(t, classifySMTPError(errors.New("dial tcp: connection refused")), domain.ErrEmailTransient)
+	assert.ErrorIs(t, classifySMTPError(errors.New("dial tcp 127.0.0.1:550: connect: connection refused")), domain.ErrEmailTransient)
+	assert.ErrorIs(t, classifySMTPError(fmt.Errorf("gomail: could not send email 1: %v", &textproto.Error{Code: 550, Msg: "mailbox unavailable"})), domain.ErrEmailPermanent)
+	assert.ErrorIs(t, classifySMTPError(fmt.Errorf("gomail: could not send email 1: %v", &textproto.Error{Code: 421, Msg: "service not available"})), domain.ErrEmailTransient)
+}
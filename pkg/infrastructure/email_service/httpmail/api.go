@@ -0,0 +1,96 @@
+package httpmail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/email_service/plaintext"
+)
+
+const (
+	DefaultAPIURL  = "https://api.sendgrid.com/v3/mail/send"
+	requestTimeout = 10 * time.Second
+)
+
+type APIConfig struct {
+	// URL is the send endpoint; it defaults to DefaultAPIURL.
+	URL    string
+	APIKey string
+	From   string
+}
+
+// APIClient sends mail through an HTTP API that takes a SendGrid v3 style
+// JSON message and a bearer API key.
+type APIClient struct {
+	config APIConfig
+	client *http.Client
+}
+
+func NewAPIClient(config APIConfig) *APIClient {
+	if config.URL == "" {
+		config.URL = DefaultAPIURL
+	}
+
+	return &APIClient{
+		config: config,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+type apiAddress struct {
+	Email string `json:"email"`
+}
+
+type apiPersonalization struct {
+	To []apiAddress `json:"to"`
+}
+
+type apiContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type apiMessage struct {
+	Personalizations []apiPersonalization `json:"personalizations"`
+	From             apiAddress           `json:"from"`
+	Subject          string               `json:"subject"`
+	Content          []apiContent         `json:"content"`
+	Headers          domain.EmailHeaders  `json:"headers,omitempty"`
+}
+
+func (c *APIClient) SendMessage(ctx context.Context, recipient string, subject string, body string, headers domain.EmailHeaders) error {
+	payload, err := json.Marshal(apiMessage{
+		Personalizations: []apiPersonalization{{To: []apiAddress{{Email: recipient}}}},
+		From:             apiAddress{Email: c.config.From},
+		Subject:          subject,
+		// The API requires text/plain to come before text/html.
+		Content: []apiContent{
+			{Type: "text/plain", Value: plaintext.FromHTML(body)},
+			{Type: "text/html", Value: body},
+		},
+		Headers: headers,
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrEmailPermanent, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrEmailPermanent, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return requestError("mail API", err)
+	}
+	defer resp.Body.Close()
+
+	return checkResponse("mail API", resp)
+}
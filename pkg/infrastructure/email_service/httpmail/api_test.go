@@ -0,0 +1,62 @@
+package httpmail
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+func TestAPIClientSendMessage(t *testing.T) {
+	var received apiMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer key", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	client := NewAPIClient(APIConfig{URL: server.URL, APIKey: "key", From: "weather@example.com"})
+	headers := domain.ListUnsubscribeHeaders("https://weather.example.com/unsubscribe/token")
+
+	require.NoError(t, client.SendMessage(context.Background(), "user@example.com", "Weather update", "<p>Sunny</p>", headers))
+
+	assert.Equal(t, "user@example.com", received.Personalizations[0].To[0].Email)
+	assert.Equal(t, "weather@example.com", received.From.Email)
+	assert.Equal(t, "Weather update", received.Subject)
+	assert.Equal(t, []apiContent{{Type: "text/plain", Value: "Sunny"}, {Type: "text/html", Value: "<p>Sunny</p>"}}, received.Content)
+	assert.Equal(t, headers, received.Headers)
+}
+
+func TestAPIClientClassifiesErrors(t *testing.T) {
+	for status, class := range map[int]error{
+		http.StatusBadRequest:          domain.ErrEmailPermanent,
+		http.StatusUnauthorized:        domain.ErrEmailPermanent,
+		http.StatusTooManyRequests:     domain.ErrEmailTransient,
+		http.StatusInternalServerError: domain.ErrEmailTransient,
+		http.StatusServiceUnavailable:  domain.ErrEmailTransient,
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, `{"errors":[{"message":"nope"}]}`, status)
+		}))
+
+		err := NewAPIClient(APIConfig{URL: server.URL}).SendMessage(context.Background(), "user@example.com", "s", "b", nil)
+		assert.ErrorIs(t, err, class, "status %d", status)
+		assert.Contains(t, err.Error(), "nope")
+
+		server.Close()
+	}
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	err := NewAPIClient(APIConfig{URL: server.URL}).SendMessage(context.Background(), "user@example.com", "s", "b", nil)
+	assert.ErrorIs(t, err, domain.ErrEmailTransient, "unreachable server")
+}
@@ -0,0 +1,38 @@
+package httpmail
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+// maxErrorBody caps how much of an error response is kept in the error.
+const maxErrorBody = 1024
+
+// checkResponse returns nil for a 2xx response and otherwise an error
+// wrapping domain.ErrEmailTransient for throttling (429) and server errors
+// (5xx), or domain.ErrEmailPermanent for any other status.
+func checkResponse(provider string, resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	detail := strings.TrimSpace(string(body))
+
+	class := domain.ErrEmailPermanent
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		class = domain.ErrEmailTransient
+	}
+
+	return fmt.Errorf("%w: %s returned %d: %s", class, provider, resp.StatusCode, detail)
+}
+
+// requestError classifies a failure to get any response, such as a timeout
+// or refused connection, as transient.
+func requestError(provider string, err error) error {
+	return fmt.Errorf("%w: %s request failed: %w", domain.ErrEmailTransient, provider, err)
+}
@@ -0,0 +1,110 @@
+package httpmail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/email_service/plaintext"
+)
+
+const sesService = "ses"
+
+type SESConfig struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// Endpoint overrides https://email.<region>.amazonaws.com, e.g. for an
+	// SES-compatible local stand-in.
+	Endpoint string
+	From     string
+}
+
+// SESClient sends mail through the SES v2 SendEmail API, signing requests
+// with AWS Signature Version 4.
+type SESClient struct {
+	config SESConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewSESClient(config SESConfig) *SESClient {
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://email.%s.amazonaws.com", config.Region)
+	}
+
+	return &SESClient{
+		config: config,
+		client: &http.Client{Timeout: requestTimeout},
+		now:    time.Now,
+	}
+}
+
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset,omitempty"`
+}
+
+type sesHeader struct {
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+}
+
+type sesSimpleMessage struct {
+	Subject sesContent `json:"Subject"`
+	Body    struct {
+		Text sesContent `json:"Text"`
+		Html sesContent `json:"Html"`
+	} `json:"Body"`
+	Headers []sesHeader `json:"Headers,omitempty"`
+}
+
+type sesSendEmailRequest struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	Content struct {
+		Simple sesSimpleMessage `json:"Simple"`
+	} `json:"Content"`
+}
+
+func (c *SESClient) SendMessage(ctx context.Context, recipient string, subject string, body string, headers domain.EmailHeaders) error {
+	var request sesSendEmailRequest
+	request.FromEmailAddress = c.config.From
+	request.Destination.ToAddresses = []string{recipient}
+	request.Content.Simple.Subject = sesContent{Data: subject, Charset: "UTF-8"}
+	request.Content.Simple.Body.Text = sesContent{Data: plaintext.FromHTML(body), Charset: "UTF-8"}
+	request.Content.Simple.Body.Html = sesContent{Data: body, Charset: "UTF-8"}
+	for name, value := range headers {
+		request.Content.Simple.Headers = append(request.Content.Simple.Headers, sesHeader{Name: name, Value: value})
+	}
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrEmailPermanent, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.Endpoint+"/v2/email/outbound-emails", bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrEmailPermanent, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	signV4(req, payload, credentials{
+		AccessKeyID:     c.config.AccessKeyID,
+		SecretAccessKey: c.config.SecretAccessKey,
+	}, c.config.Region, sesService, c.now())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return requestError("SES", err)
+	}
+	defer resp.Body.Close()
+
+	return checkResponse("SES", resp)
+}
@@ -0,0 +1,69 @@
+package httpmail
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+func TestSESClientSendMessage(t *testing.T) {
+	var received sesSendEmailRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v2/email/outbound-emails", r.URL.Path)
+		assert.Equal(t, "20250601T120000Z", r.Header.Get("X-Amz-Date"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"),
+			"AWS4-HMAC-SHA256 Credential=AKID/20250601/eu-west-1/ses/aws4_request, SignedHeaders=content-type;host;x-amz-date, Signature="))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.Write([]byte(`{"MessageId":"1"}`))
+	}))
+	defer server.Close()
+
+	client := NewSESClient(SESConfig{
+		Region:          "eu-west-1",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		Endpoint:        server.URL,
+		From:            "weather@example.com",
+	})
+	client.now = func() time.Time { return time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC) }
+
+	headers := domain.EmailHeaders{domain.ListUnsubscribeHeader: "<https://weather.example.com/unsubscribe/token>"}
+	require.NoError(t, client.SendMessage(context.Background(), "user@example.com", "Weather update", "<p>Sunny</p>", headers))
+
+	assert.Equal(t, "weather@example.com", received.FromEmailAddress)
+	assert.Equal(t, []string{"user@example.com"}, received.Destination.ToAddresses)
+	assert.Equal(t, "Weather update", received.Content.Simple.Subject.Data)
+	assert.Equal(t, "Sunny", received.Content.Simple.Body.Text.Data)
+	assert.Equal(t, "<p>Sunny</p>", received.Content.Simple.Body.Html.Data)
+	assert.Equal(t, []sesHeader{{Name: domain.ListUnsubscribeHeader, Value: "<https://weather.example.com/unsubscribe/token>"}}, received.Content.Simple.Headers)
+}
+
+func TestSESClientClassifiesErrors(t *testing.T) {
+	for status, class := range map[int]error{
+		http.StatusBadRequest:          domain.ErrEmailPermanent,
+		http.StatusTooManyRequests:     domain.ErrEmailTransient,
+		http.StatusInternalServerError: domain.ErrEmailTransient,
+	} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+
+		err := NewSESClient(SESConfig{Region: "eu-west-1", Endpoint: server.URL}).SendMessage(context.Background(), "user@example.com", "s", "b", nil)
+		assert.ErrorIs(t, err, class, "status %d", status)
+
+		server.Close()
+	}
+}
+
+func TestNewSESClientDefaultsEndpointToRegion(t *testing.T) {
+	assert.Equal(t, "https://email.eu-west-1.amazonaws.com", NewSESClient(SESConfig{Region: "eu-west-1"}).config.Endpoint)
+}
@@ -0,0 +1,144 @@
+// Package plaintext renders the plain-text alternative of an HTML email.
+package plaintext
+
+import (
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// paragraphElements are set apart by a blank line in the text version.
+var paragraphElements = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Blockquote: true, atom.Footer: true,
+	atom.Form: true, atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true,
+	atom.H5: true, atom.H6: true, atom.Header: true, atom.Hr: true, atom.Ol: true,
+	atom.P: true, atom.Section: true, atom.Table: true, atom.Ul: true,
+}
+
+// lineElements start on a new line.
+var lineElements = map[atom.Atom]bool{
+	atom.Br: true, atom.Div: true, atom.Tr: true,
+}
+
+// hiddenElements are not shown to the reader, so their text is dropped.
+var hiddenElements = map[atom.Atom]bool{
+	atom.Head: true, atom.Script: true, atom.Style: true, atom.Title: true,
+}
+
+// FromHTML renders body as plain text: markup is dropped, block elements
+// become lines, list items get a dash and links keep their URL after the
+// link text.
+func FromHTML(body string) string {
+	tokenizer := html.NewTokenizer(strings.NewReader(body))
+
+	var text strings.Builder
+	var href string
+	var linkText strings.Builder
+	hidden := 0
+
+	write := func(s string) {
+		if href != "" {
+			linkText.WriteString(s)
+		}
+		text.WriteString(s)
+	}
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return tidy(text.String())
+		case html.TextToken:
+			if hidden == 0 {
+				write(collapseSpace(string(tokenizer.Text())))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch {
+			case hiddenElements[token.DataAtom]:
+				if token.Type == html.StartTagToken {
+					hidden++
+				}
+			case token.DataAtom == atom.Li:
+				text.WriteString("\n- ")
+			case token.DataAtom == atom.A:
+				href = attribute(token, "href")
+				linkText.Reset()
+			case paragraphElements[token.DataAtom]:
+				text.WriteString("\n\n")
+			case lineElements[token.DataAtom]:
+				text.WriteString("\n")
+			}
+		case html.EndTagToken:
+			token := tokenizer.Token()
+			switch {
+			case hiddenElements[token.DataAtom]:
+				if hidden > 0 {
+					hidden--
+				}
+			case token.DataAtom == atom.A:
+				if href != "" && strings.TrimSpace(linkText.String()) != href {
+					text.WriteString(" (" + href + ")")
+				}
+				href = ""
+			case paragraphElements[token.DataAtom]:
+				text.WriteString("\n\n")
+			}
+		}
+	}
+}
+
+// collapseSpace turns each run of whitespace into a single space, keeping
+// the spaces at either end so words around inline tags stay apart.
+func collapseSpace(text string) string {
+	var b strings.Builder
+	space := false
+
+	for _, r := range text {
+		if strings.ContainsRune(" \t\r\n\f", r) {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+
+	return b.String()
+}
+
+func attribute(token html.Token, name string) string {
+	for _, attr := range token.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// tidy trims every line and collapses runs of blank lines into one.
+func tidy(text string) string {
+	var lines []string
+	blank := false
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			blank = true
+			continue
+		}
+
+		if blank && len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		blank = false
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
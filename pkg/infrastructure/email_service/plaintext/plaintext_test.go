@@ -0,0 +1,43 @@
+package plaintext
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromHTML(t *testing.T) {
+	body := `<!DOCTYPE html>
+<html>
+<head>
+    <title>Weather</title>
+    <style>body { color: #333; }</style>
+</head>
+<body>
+    <h1>Weather in   Kyiv</h1>
+    <p>It is <b>sunny</b> and 21&deg;C.</p>
+    <ul>
+        <li>Humidity: 40%</li>
+        <li>Wind: 3 m/s</li>
+    </ul>
+    <p><a href="https://weather.example.com/confirm/token">Confirm subscription</a></p>
+    <p><a href="https://weather.example.com">https://weather.example.com</a></p>
+</body>
+</html>`
+
+	assert.Equal(t, `Weather in Kyiv
+
+It is sunny and 21°C.
+
+- Humidity: 40%
+- Wind: 3 m/s
+
+Confirm subscription (https://weather.example.com/confirm/token)
+
+https://weather.example.com`, FromHTML(body))
+}
+
+func TestFromHTMLPlainInput(t *testing.T) {
+	assert.Equal(t, "Hello\nworld", FromHTML("Hello<br>world"))
+	assert.Equal(t, "", FromHTML(""))
+}
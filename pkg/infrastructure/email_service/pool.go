@@ -0,0 +1,306 @@
+package smtp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gopkg.in/gomail.v2"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+const (
+	DefaultPoolSize    = 4
+	DefaultIdleTimeout = 30 * time.Second
+
+	// domainLimiterSweepInterval is how often limiters of domains with no
+	// pending sends are dropped, so one-off domains do not pile up.
+	domainLimiterSweepInterval = time.Minute
+)
+
+type PoolConfig struct {
+	SMTPConfig
+	// MaxConnections bounds the open connections; it defaults to
+	// DefaultPoolSize.
+	MaxConnections int
+	// IdleTimeout is how long an unused connection is kept before it is
+	// closed instead of reused, since servers drop idle sessions. It
+	// defaults to DefaultIdleTimeout.
+	IdleTimeout time.Duration
+	// RateLimit caps messages per second across all recipients and
+	// DomainRateLimit caps them per recipient domain; zero means unlimited.
+	RateLimit       float64
+	DomainRateLimit float64
+}
+
+type pooledConn struct {
+	sender   gomail.SendCloser
+	lastUsed time.Time
+}
+
+// Pool sends mail over a bounded set of authenticated SMTP connections that
+// are kept open between messages. A connection that fails is closed and, if
+// it had been sitting idle, the message is retried once on a fresh one.
+type Pool struct {
+	config PoolConfig
+	dial   func() (gomail.SendCloser, error)
+	now    func() time.Time
+
+	// slots holds one token per connection that may be in use.
+	slots chan struct{}
+
+	mu   sync.Mutex
+	idle []*pooledConn
+	open int
+
+	limiter        *rateLimiter
+	domainMu       sync.Mutex
+	domainLimiters map[string]*rateLimiter
+	domainSweptAt  time.Time
+
+	dials        atomic.Int64
+	dialFailures atomic.Int64
+	sent         atomic.Int64
+	failed       atomic.Int64
+	throttled    atomic.Int64
+}
+
+func NewPool(config PoolConfig) *Pool {
+	if config.MaxConnections <= 0 {
+		config.MaxConnections = DefaultPoolSize
+	}
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = DefaultIdleTimeout
+	}
+
+	return &Pool{
+		config: config,
+		// A Dialer caches the negotiated auth mechanism, so each dial gets
+		// its own to stay safe under concurrent dials.
+		dial: func() (gomail.SendCloser, error) {
+			return gomail.NewDialer(config.Host, config.Port, config.Username, config.Password).Dial()
+		},
+		now:            time.Now,
+		slots:          make(chan struct{}, config.MaxConnections),
+		limiter:        newRateLimiter(config.RateLimit),
+		domainLimiters: map[string]*rateLimiter{},
+	}
+}
+
+func (p *Pool) SendMessage(ctx context.Context, recipient string, subject string, body string, headers domain.EmailHeaders) error {
+	if err := p.throttle(ctx, recipient); err != nil {
+		return fmt.Errorf("%w: %w", domain.ErrEmailTransient, err)
+	}
+
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return fmt.Errorf("%w: %w", domain.ErrEmailTransient, ctx.Err())
+	}
+	defer func() { <-p.slots }()
+
+	message := newMessage(p.config.From, recipient, subject, body, headers)
+
+	err := p.send(recipient, message)
+	if err != nil {
+		p.failed.Add(1)
+		log.Printf("Failed to send email: %v", err)
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	p.sent.Add(1)
+	return nil
+}
+
+// send delivers message on an idle connection or a new one. A reused
+// connection may have been dropped by the server while idle, so a
+// transient failure on it is retried once on a fresh connection.
+func (p *Pool) send(recipient string, message *gomail.Message) error {
+	conn, reused, err := p.acquire()
+	if err != nil {
+		return err
+	}
+
+	err = p.sendOn(conn, recipient, message)
+	if err == nil || !reused || errors.Is(err, domain.ErrEmailPermanent) {
+		return err
+	}
+
+	conn, err = p.connect()
+	if err != nil {
+		return err
+	}
+
+	return p.sendOn(conn, recipient, message)
+}
+
+func (p *Pool) sendOn(conn *pooledConn, recipient string, message *gomail.Message) error {
+	if err := conn.sender.Send(p.config.From, []string{recipient}, message); err != nil {
+		// After a failed transaction the session state is unknown, so the
+		// connection is not reused.
+		p.discard(conn)
+		return classifySMTPError(err)
+	}
+
+	conn.lastUsed = p.now()
+
+	p.mu.Lock()
+	p.idle = append(p.idle, conn)
+	p.mu.Unlock()
+
+	return nil
+}
+
+// acquire returns the most recently used idle connection that has not
+// timed out, or dials a new one. The caller must hold a slot.
+func (p *Pool) acquire() (*pooledConn, bool, error) {
+	var reused *pooledConn
+	var expired []*pooledConn
+
+	p.mu.Lock()
+	for len(p.idle) > 0 {
+		conn := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+
+		if p.now().Sub(conn.lastUsed) < p.config.IdleTimeout {
+			reused = conn
+			break
+		}
+
+		p.open--
+		expired = append(expired, conn)
+	}
+	p.mu.Unlock()
+
+	for _, conn := range expired {
+		conn.sender.Close()
+	}
+
+	if reused != nil {
+		return reused, true, nil
+	}
+
+	conn, err := p.connect()
+	return conn, false, err
+}
+
+func (p *Pool) connect() (*pooledConn, error) {
+	p.dials.Add(1)
+
+	sender, err := p.dial()
+	if err != nil {
+		p.dialFailures.Add(1)
+		return nil, classifySMTPError(err)
+	}
+
+	p.mu.Lock()
+	p.open++
+	p.mu.Unlock()
+
+	return &pooledConn{sender: sender, lastUsed: p.now()}, nil
+}
+
+func (p *Pool) discard(conn *pooledConn) {
+	conn.sender.Close()
+
+	p.mu.Lock()
+	p.open--
+	p.mu.Unlock()
+}
+
+func (p *Pool) throttle(ctx context.Context, recipient string) error {
+	waited, err := p.limiter.Wait(ctx)
+	if err != nil {
+		return err
+	}
+
+	domainWaited, err := p.domainLimiter(recipient).Wait(ctx)
+	if err != nil {
+		return err
+	}
+
+	if waited || domainWaited {
+		p.throttled.Add(1)
+	}
+
+	return nil
+}
+
+func (p *Pool) domainLimiter(recipient string) *rateLimiter {
+	if p.config.DomainRateLimit <= 0 {
+		return nil
+	}
+
+	_, domainName, _ := strings.Cut(recipient, "@")
+	domainName = strings.ToLower(domainName)
+
+	p.domainMu.Lock()
+	defer p.domainMu.Unlock()
+
+	if now := p.now(); now.Sub(p.domainSweptAt) >= domainLimiterSweepInterval {
+		p.sweepDomainLimiters()
+		p.domainSweptAt = now
+	}
+
+	limiter, ok := p.domainLimiters[domainName]
+	if !ok {
+		limiter = newRateLimiter(p.config.DomainRateLimit)
+		p.domainLimiters[domainName] = limiter
+	}
+
+	return limiter
+}
+
+// sweepDomainLimiters drops the limiters whose reserved slots have all
+// passed; a new one is created on the domain's next send. The caller must
+// hold domainMu.
+func (p *Pool) sweepDomainLimiters() {
+	now := time.Now()
+	for domainName, limiter := range p.domainLimiters {
+		if limiter.idle(now) {
+			delete(p.domainLimiters, domainName)
+		}
+	}
+}
+
+func (p *Pool) PoolStats() domain.EmailPoolStats {
+	p.mu.Lock()
+	open, idle := p.open, len(p.idle)
+	p.mu.Unlock()
+
+	return domain.EmailPoolStats{
+		MaxConnections: p.config.MaxConnections,
+		Open:           open,
+		Idle:           idle,
+		Dials:          p.dials.Load(),
+		DialFailures:   p.dialFailures.Load(),
+		Sent:           p.sent.Load(),
+		Failed:         p.failed.Load(),
+		Throttled:      p.throttled.Load(),
+	}
+}
+
+// Close closes the idle connections. It is meant for shutdown, once no
+// more messages are being sent.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	idle := p.idle
+	p.idle = nil
+	p.open -= len(idle)
+	p.mu.Unlock()
+
+	var errs []error
+	for _, conn := range idle {
+		if err := conn.sender.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,198 @@
+package smtp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/gomail.v2"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+func TestPoolReusesConnections(t *testing.T) {
+	server := startFakeSMTPServer(t, "user", "secret")
+	pool := NewPool(PoolConfig{SMTPConfig: server.config(), MaxConnections: 2})
+	defer pool.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			recipient := fmt.Sprintf("user%d@example.com", i)
+			assert.NoError(t, pool.SendMessage(context.Background(), recipient, "Weather update", "<p>Sunny</p>", nil))
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, server.Messages(), 20)
+	assert.LessOrEqual(t, server.Connections(), 2)
+
+	stats := pool.PoolStats()
+	assert.Equal(t, 2, stats.MaxConnections)
+	assert.Equal(t, int64(20), stats.Sent)
+	assert.LessOrEqual(t, stats.Open, 2)
+	assert.Equal(t, stats.Open, stats.Idle)
+	assert.Equal(t, int64(server.Connections()), stats.Dials)
+}
+
+func TestPoolSendsHeaders(t *testing.T) {
+	server := startFakeSMTPServer(t, "", "")
+	pool := NewPool(PoolConfig{SMTPConfig: server.config()})
+	defer pool.Close()
+
+	headers := domain.ListUnsubscribeHeaders("https://weather.example.com/unsubscribe/token")
+	require.NoError(t, pool.SendMessage(context.Background(), "user@example.com", "Weather update", "<p>Sunny</p>", headers))
+
+	messages := server.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "weather@example.com", messages[0].From)
+	assert.Equal(t, []string{"user@example.com"}, messages[0].To)
+	assert.Contains(t, messages[0].Data, "List-Unsubscribe: <https://weather.example.com/unsubscribe/token>")
+	assert.Contains(t, messages[0].Data, "List-Unsubscribe-Post: List-Unsubscribe=One-Click")
+}
+
+func TestPoolReconnectsAfterDroppedConnection(t *testing.T) {
+	server := startFakeSMTPServer(t, "", "")
+	pool := NewPool(PoolConfig{SMTPConfig: server.config(), MaxConnections: 1})
+	defer pool.Close()
+
+	require.NoError(t, pool.SendMessage(context.Background(), "a@example.com", "s", "b", nil))
+	server.DropConnections()
+	require.NoError(t, pool.SendMessage(context.Background(), "b@example.com", "s", "b", nil))
+
+	assert.Len(t, server.Messages(), 2)
+	assert.Equal(t, 2, server.Connections())
+	assert.Equal(t, 1, pool.PoolStats().Open)
+}
+
+func TestPoolClosesIdleConnections(t *testing.T) {
+	server := startFakeSMTPServer(t, "", "")
+	pool := NewPool(PoolConfig{SMTPConfig: server.config(), IdleTimeout: time.Minute})
+	defer pool.Close()
+
+	now := time.Now()
+	pool.now = func() time.Time { return now }
+
+	require.NoError(t, pool.SendMessage(context.Background(), "a@example.com", "s", "b", nil))
+	now = now.Add(2 * time.Minute)
+	require.NoError(t, pool.SendMessage(context.Background(), "b@example.com", "s", "b", nil))
+
+	assert.Equal(t, 2, server.Connections())
+	assert.Equal(t, 1, pool.PoolStats().Open)
+}
+
+func TestPoolClassifiesErrors(t *testing.T) {
+	server := startFakeSMTPServer(t, "user", "secret")
+
+	pool := NewPool(PoolConfig{SMTPConfig: server.config()})
+	defer pool.Close()
+
+	err := pool.SendMessage(context.Background(), "rejected@example.com", "s", "b", nil)
+	assert.ErrorIs(t, err, domain.ErrEmailPermanent)
+	require.NoError(t, pool.SendMessage(context.Background(), "user@example.com", "s", "b", nil))
+	assert.Equal(t, int64(1), pool.PoolStats().Failed)
+
+	config := server.config()
+	config.Password = "wrong"
+	err = NewPool(PoolConfig{SMTPConfig: config}).SendMessage(context.Background(), "user@example.com", "s", "b", nil)
+	assert.ErrorIs(t, err, domain.ErrEmailPermanent, "failed authentication")
+
+	config = server.config()
+	config.Port = 1
+	unreachable := NewPool(PoolConfig{SMTPConfig: config})
+	err = unreachable.SendMessage(context.Background(), "user@example.com", "s", "b", nil)
+	assert.ErrorIs(t, err, domain.ErrEmailTransient, "refused connection")
+	assert.Equal(t, int64(1), unreachable.PoolStats().DialFailures)
+}
+
+func TestClassifySMTPErrorFromGomailSend(t *testing.T) {
+	server := startFakeSMTPServer(t, "", "")
+	config := server.config()
+
+	sender, err := gomail.NewDialer(config.Host, config.Port, "", "").Dial()
+	require.NoError(t, err)
+	defer sender.Close()
+
+	// gomail.Send wraps the server's reply with %v.
+	err = gomail.Send(sender, newMessage(config.From, "rejected@example.com", "s", "b", nil))
+	require.Error(t, err)
+	assert.ErrorIs(t, classifySMTPError(err), domain.ErrEmailPermanent)
+}
+
+func TestPoolThrottlesPerDomain(t *testing.T) {
+	server := startFakeSMTPServer(t, "", "")
+	pool := NewPool(PoolConfig{SMTPConfig: server.config(), DomainRateLimit: 20})
+	defer pool.Close()
+
+	start := time.Now()
+	for _, recipient := range []string{"a@example.com", "b@example.com", "c@EXAMPLE.com", "d@other.com"} {
+		require.NoError(t, pool.SendMessage(context.Background(), recipient, "s", "b", nil))
+	}
+
+	// Three messages to example.com need two 50ms gaps; other.com is not held up.
+	assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	assert.Equal(t, int64(2), pool.PoolStats().Throttled)
+}
+
+func TestPoolDropsIdleDomainLimiters(t *testing.T) {
+	server := startFakeSMTPServer(t, "", "")
+	pool := NewPool(PoolConfig{SMTPConfig: server.config(), DomainRateLimit: 1000})
+	defer pool.Close()
+
+	now := time.Now()
+	pool.now = func() time.Time { return now }
+
+	for _, recipient := range []string{"a@one.example", "b@two.example"} {
+		require.NoError(t, pool.SendMessage(context.Background(), recipient, "s", "b", nil))
+	}
+	assert.Len(t, pool.domainLimiters, 2)
+
+	// Both 1ms slots have passed by the next sweep.
+	time.Sleep(5 * time.Millisecond)
+	now = now.Add(domainLimiterSweepInterval)
+	require.NoError(t, pool.SendMessage(context.Background(), "c@three.example", "s", "b", nil))
+	assert.Len(t, pool.domainLimiters, 1)
+	assert.Contains(t, pool.domainLimiters, "three.example")
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := newRateLimiter(50)
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := limiter.Wait(context.Background())
+		require.NoError(t, err)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 60*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	limiter = newRateLimiter(0.001)
+	_, err := limiter.Wait(ctx)
+	require.NoError(t, err, "the first slot is free")
+	_, err = limiter.Wait(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// A cancelled wait gives its slot to the next caller.
+	limiter = newRateLimiter(10)
+	start = time.Now()
+	_, err = limiter.Wait(context.Background())
+	require.NoError(t, err)
+	timeout, cancelTimeout := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelTimeout()
+	_, err = limiter.Wait(timeout)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	_, err = limiter.Wait(context.Background())
+	require.NoError(t, err)
+	assert.Less(t, time.Since(start), 150*time.Millisecond)
+
+	waited, err := (*rateLimiter)(nil).Wait(ctx)
+	assert.False(t, waited)
+	assert.NoError(t, err)
+}
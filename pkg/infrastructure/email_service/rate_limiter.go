@@ -0,0 +1,72 @@
+package smtp
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// rateLimiter spaces events evenly at a fixed rate. Each Wait reserves the
+// next free slot, so concurrent callers queue up instead of bursting.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// newRateLimiter returns nil, which never waits, for a non-positive rate.
+func newRateLimiter(perSecond float64) *rateLimiter {
+	if perSecond <= 0 {
+		return nil
+	}
+
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / perSecond)}
+}
+
+// Wait blocks until the caller's slot and reports whether it had to wait.
+// A caller that gives up hands its slot back unless a later one has been
+// reserved since, so cancelled sends do not slow down the ones after them.
+func (l *rateLimiter) Wait(ctx context.Context) (bool, error) {
+	if l == nil {
+		return false, nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	slot := l.next
+	if slot.Before(now) {
+		slot = now
+	}
+	l.next = slot.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := slot.Sub(now)
+	if delay <= 0 {
+		return false, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true, nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		if l.next.Equal(slot.Add(l.interval)) {
+			l.next = slot
+		}
+		l.mu.Unlock()
+
+		return true, ctx.Err()
+	}
+}
+
+// idle reports whether every reserved slot has passed, in which case the
+// limiter behaves like a new one.
+func (l *rateLimiter) idle(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return !l.next.After(now)
+}
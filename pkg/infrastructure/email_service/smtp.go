@@ -1,13 +1,16 @@
 package smtp
 
 import (
-	"context"
+	"errors"
 	"fmt"
-	"log"
+	"net/textproto"
+	"regexp"
+	"strconv"
 
 	"gopkg.in/gomail.v2"
 
 	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/email_service/plaintext"
 )
 
 type SMTPConfig struct {
@@ -18,31 +21,53 @@ type SMTPConfig struct {
 	From     string
 }
 
-type SmtpService struct {
-	config SMTPConfig
-}
+func newMessage(from, recipient, subject, body string, headers domain.EmailHeaders) *gomail.Message {
+	m := gomail.NewMessage()
 
-func NewSmtpService(config SMTPConfig) domain.EmailService {
-	return &SmtpService{
-		config: config,
+	m.SetHeader("From", from)
+	m.SetHeader("To", recipient)
+	m.SetHeader("Subject", subject)
+	for name, value := range headers {
+		m.SetHeader(name, value)
 	}
+
+	// Clients that cannot show HTML fall back to the first part.
+	m.SetBody("text/plain", plaintext.FromHTML(body))
+	m.AddAlternative("text/html", body)
+
+	return m
 }
 
-func (s *SmtpService) SendMessage(ctx context.Context, recipient string, subject string, body string) error {
-	m := gomail.NewMessage()
+// smtpReplyPattern finds an SMTP reply code at the start of an error
+// message or after a ": " prefix, as in "gomail: could not send email 1:
+// 550 mailbox unavailable".
+var smtpReplyPattern = regexp.MustCompile(`(?:^|: )([2-5][0-9]{2})[ -]`)
 
-	m.SetHeader("From", s.config.From)
-	m.SetHeader("To", recipient)
-	m.SetHeader("Subject", subject)
+// classifySMTPError treats 5xx replies (rejected recipient, failed
+// authentication) as permanent and everything else, including network
+// errors and 4xx replies, as transient.
+func classifySMTPError(err error) error {
+	if smtpReplyCode(err) >= 500 {
+		return fmt.Errorf("%w: %w", domain.ErrEmailPermanent, err)
+	}
 
-	m.SetBody("text/html", body)
+	return fmt.Errorf("%w: %w", domain.ErrEmailTransient, err)
+}
 
-	d := gomail.NewDialer(s.config.Host, s.config.Port, s.config.Username, s.config.Password)
+// smtpReplyCode returns the server's reply code, or 0 for errors without
+// one. gomail formats the replies it wraps with %v, so the code is parsed
+// from the message when the *textproto.Error is no longer in the chain.
+func smtpReplyCode(err error) int {
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code
+	}
 
-	if err := d.DialAndSend(m); err != nil {
-		log.Printf("Failed to send email: %v", err)
-		return fmt.Errorf("failed to send email: %w", err)
+	match := smtpReplyPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0
 	}
 
-	return nil
+	code, _ := strconv.Atoi(match[1])
+	return code
 }
@@ -0,0 +1,37 @@
+package smtp
+
+import (
+	"context"
+	"fmt"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
+)
+
+// SuppressionFilter refuses to send to suppressed addresses and passes
+// everything else to the wrapped transport. Refusals are permanent, so
+// they are not retried.
+type SuppressionFilter struct {
+	next         domain.EmailService
+	suppressions domain_repository.SuppressionRepository
+}
+
+func NewSuppressionFilter(next domain.EmailService, suppressions domain_repository.SuppressionRepository) *SuppressionFilter {
+	return &SuppressionFilter{
+		next:         next,
+		suppressions: suppressions,
+	}
+}
+
+func (f *SuppressionFilter) SendMessage(ctx context.Context, recipient string, subject string, body string, headers domain.EmailHeaders) error {
+	suppressed, err := f.suppressions.IsSuppressed(ctx, recipient)
+	if err != nil {
+		return fmt.Errorf("%w: failed to check suppression list: %w", domain.ErrEmailTransient, err)
+	}
+
+	if suppressed {
+		return fmt.Errorf("%w: %w: %s", domain.ErrEmailPermanent, domain.ErrEmailSuppressed, recipient)
+	}
+
+	return f.next.SendMessage(ctx, recipient, subject, body, headers)
+}
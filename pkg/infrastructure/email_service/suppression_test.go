@@ -0,0 +1,50 @@
+package smtp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+type stubSuppressions struct {
+	suppressed map[string]bool
+	err        error
+}
+
+func (s stubSuppressions) Save(ctx context.Context, suppression *domain.Suppression) error {
+	return nil
+}
+
+func (s stubSuppressions) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	return s.suppressed[domain.NormalizeEmail(email)], s.err
+}
+
+type countingEmailService struct {
+	recipients []string
+}
+
+func (s *countingEmailService) SendMessage(ctx context.Context, recipient string, subject string, body string, headers domain.EmailHeaders) error {
+	s.recipients = append(s.recipients, recipient)
+	return nil
+}
+
+func TestSuppressionFilter(t *testing.T) {
+	next := &countingEmailService{}
+	filter := NewSuppressionFilter(next, stubSuppressions{suppressed: map[string]bool{"gone@example.com": true}})
+
+	assert.NoError(t, filter.SendMessage(context.Background(), "user@example.com", "s", "b", nil))
+
+	err := filter.SendMessage(context.Background(), "Gone@Example.com", "s", "b", nil)
+	assert.ErrorIs(t, err, domain.ErrEmailSuppressed)
+	assert.ErrorIs(t, err, domain.ErrEmailPermanent)
+	assert.Equal(t, []string{"user@example.com"}, next.recipients)
+
+	failing := NewSuppressionFilter(next, stubSuppressions{err: errors.New("connection refused")})
+	err = failing.SendMessage(context.Background(), "user@example.com", "s", "b", nil)
+	assert.ErrorIs(t, err, domain.ErrEmailTransient)
+	assert.Len(t, next.recipients, 1)
+}
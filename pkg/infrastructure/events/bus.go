@@ -0,0 +1,28 @@
+package events
+
+import (
+	"context"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+// Dispatcher takes the events the outbox relay reads from the outbox. An
+// error means the event was not taken and the relay retries the message.
+type Dispatcher interface {
+	Dispatch(ctx context.Context, event domain.Event) error
+}
+
+// Bus is an EventPublisher with a lifecycle that the outbox relay
+// dispatches to. It is implemented by the in-process Publisher and by
+// RedisStreamsPublisher.
+type Bus interface {
+	domain.EventPublisher
+	Dispatcher
+	Start()
+	Close()
+}
+
+var (
+	_ Bus = (*Publisher)(nil)
+	_ Bus = (*RedisStreamsPublisher)(nil)
+)
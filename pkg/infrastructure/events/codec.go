@@ -0,0 +1,48 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	value_objects "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+)
+
+// EncodePayload serialises an event payload so it can be stored or sent
+// across process boundaries.
+func EncodePayload(event domain.Event) ([]byte, error) {
+	data, err := json.Marshal(event.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s payload: %w", event.Type, err)
+	}
+
+	return data, nil
+}
+
+// DecodePayload restores a payload into the concrete type the registered
+// handlers expect for eventType.
+func DecodePayload(eventType domain.EventType, data []byte) (interface{}, error) {
+	switch eventType {
+	case domain.UserSubscribed, domain.SubscriptionResumed:
+		var subscription entity.Subscription
+		if err := json.Unmarshal(data, &subscription); err != nil {
+			return nil, fmt.Errorf("failed to decode %s payload: %w", eventType, err)
+		}
+		return &subscription, nil
+	case domain.WeatherEvent:
+		var weather value_objects.WeatherEvent
+		if err := json.Unmarshal(data, &weather); err != nil {
+			return nil, fmt.Errorf("failed to decode %s payload: %w", eventType, err)
+		}
+		return weather, nil
+	case domain.ManageLinkRequested:
+		var link value_objects.ManageLink
+		if err := json.Unmarshal(data, &link); err != nil {
+			return nil, fmt.Errorf("failed to decode %s payload: %w", eventType, err)
+		}
+		return link, nil
+	default:
+		return nil, fmt.Errorf("unknown event type: %s", eventType)
+	}
+}
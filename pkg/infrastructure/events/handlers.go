@@ -3,8 +3,14 @@ package events
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"html/template"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
 
 	config "github.com/danik-tro/weather-subscriber/pkg"
 	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
@@ -14,11 +20,75 @@ import (
 	"github.com/danik-tro/weather-subscriber/pkg/external/weather"
 )
 
+var errWebhooksNotConfigured = errors.New("webhook delivery is not configured")
+
+const (
+	digestForecastDays   = 2
+	defaultDigestWorkers = 10
+	// deliveryClaimTimeout is how long a claimed digest may stay pending
+	// before another run assumes the sender died and takes it over.
+	deliveryClaimTimeout = 10 * time.Minute
+)
+
 type Handler struct {
 	EmailService   domain.EmailService
 	WeatherService *weather.WeatherService
 	Repository     domain_repository.SubscriptionRepository
+	Deliveries     domain_repository.DeliveryRepository
 	Config         config.Config
+	// Templates holds the email templates, named after their files.
+	Templates *template.Template
+	// ActionLinks signs the confirm, unsubscribe and pause links.
+	ActionLinks domain.ActionLinks
+	// DigestWorkers is the number of digests sent concurrently.
+	DigestWorkers int
+	// Webhooks delivers digests to subscribers that registered a webhook
+	// URL. Those digests fail while it is nil.
+	Webhooks domain.NotificationChannel
+}
+
+// actionURL signs a link to path for action on subscription id, valid from
+// issuedAt until expiresAt.
+func (h *Handler) actionURL(path string, id uuid.UUID, action domain.LinkAction, issuedAt, expiresAt time.Time) string {
+	return fmt.Sprintf("%s/%s/%s", h.Config.BaseURL, path, h.actionToken(id, action, issuedAt, expiresAt))
+}
+
+func (h *Handler) actionToken(id uuid.UUID, action domain.LinkAction, issuedAt, expiresAt time.Time) string {
+	return h.ActionLinks.Sign(domain.ActionLink{
+		SubscriptionID: id,
+		Action:         action,
+		IssuedAt:       issuedAt,
+		ExpiresAt:      expiresAt,
+	})
+}
+
+func (h *Handler) unsubscribeURL(id uuid.UUID) string {
+	now := time.Now()
+	return h.actionURL("unsubscribe", id, domain.UnsubscribeLinkAction, now, now.Add(h.Config.ActionLinkTTL))
+}
+
+// unsubscribeToken is the token of the unsubscribe link, for channels that
+// carry it as data rather than as a URL.
+func (h *Handler) unsubscribeToken(id uuid.UUID) string {
+	now := time.Now()
+	return h.actionToken(id, domain.UnsubscribeLinkAction, now, now.Add(h.Config.ActionLinkTTL))
+}
+
+func (h *Handler) pauseURL(id uuid.UUID) string {
+	now := time.Now()
+	return h.actionURL("pause", id, domain.PauseLinkAction, now, now.Add(h.Config.ActionLinkTTL))
+}
+
+// confirmURL is issued as of the confirmation email, so a resend supersedes
+// it. Without a confirmation TTL it lasts as long as other action links.
+func (h *Handler) confirmURL(subscription *entity.Subscription) string {
+	ttl := h.Config.ConfirmationTokenTTL
+	if ttl <= 0 {
+		ttl = h.Config.ActionLinkTTL
+	}
+
+	sentAt := subscription.ConfirmationSentAt
+	return h.actionURL("confirm", subscription.ID, domain.ConfirmLinkAction, sentAt, sentAt.Add(ttl))
 }
 
 func (h *Handler) UserSubscribed() domain.EventHandler {
@@ -29,19 +99,22 @@ func (h *Handler) UserSubscribed() domain.EventHandler {
 			return fmt.Errorf("invalid event type: %T", event)
 		}
 
-		confirmationLink := fmt.Sprintf("%s/confirm/%s", h.Config.BaseURL, subscription.ConfirmationToken)
-
-		confirmationTmpl, err := template.ParseFiles("templates/confirmation.html")
+		confirmationLink := h.confirmURL(subscription)
 
+		confirmationTmpl, err := h.emailTemplate("confirmation.html")
 		if err != nil {
-			return fmt.Errorf("failed to parse confirmation template: %w", err)
+			return err
 		}
 
 		confirmData := struct {
 			ConfirmURL string
+			ExpiresAt  string
 		}{
 			ConfirmURL: confirmationLink,
 		}
+		if ttl := h.Config.ConfirmationTokenTTL; ttl > 0 {
+			confirmData.ExpiresAt = subscription.ConfirmationSentAt.Add(ttl).UTC().Format("January 2, 2006 15:04 MST")
+		}
 
 		var bodyBuffer bytes.Buffer
 		if err := confirmationTmpl.Execute(&bodyBuffer, confirmData); err != nil {
@@ -50,104 +123,415 @@ func (h *Handler) UserSubscribed() domain.EventHandler {
 
 		message := bodyBuffer.String()
 
-		h.EmailService.SendMessage(ctx, subscription.Email, "Confirm your email", message)
+		if err := h.EmailService.SendMessage(ctx, subscription.Email, "Confirm your email", message, nil); err != nil {
+			return fmt.Errorf("failed to send confirmation email: %w", err)
+		}
 
 		return nil
 	}
 }
 
-func (h *Handler) WeatherEvent() domain.EventHandler {
+func (h *Handler) ManageLinkRequested() domain.EventHandler {
 	return func(ctx context.Context, event domain.Event) error {
-		weather, ok := event.Payload.(value_objects.WeatherEvent)
+		link, ok := event.Payload.(value_objects.ManageLink)
 
 		if !ok {
 			return fmt.Errorf("invalid event type: %T", event)
 		}
 
-		unsubscribeLink := fmt.Sprintf("%s/unsubscribe/%s", h.Config.BaseURL, weather.UnsubscribeToken)
+		manageTmpl, err := h.emailTemplate("manage_link.html")
+		if err != nil {
+			return err
+		}
+
+		manageData := struct {
+			ManageURL      string
+			ExpiresMinutes int
+		}{
+			ManageURL:      fmt.Sprintf("%s/manage/%s", h.Config.BaseURL, link.Token),
+			ExpiresMinutes: int(time.Until(link.ExpiresAt).Round(time.Minute).Minutes()),
+		}
+
+		var bodyBuffer bytes.Buffer
+		if err := manageTmpl.Execute(&bodyBuffer, manageData); err != nil {
+			return fmt.Errorf("failed to execute manage link template: %w", err)
+		}
+
+		if err := h.EmailService.SendMessage(ctx, link.Email, "Manage your weather subscriptions", bodyBuffer.String(), nil); err != nil {
+			return fmt.Errorf("failed to send manage link email: %w", err)
+		}
+
+		return nil
+	}
+}
+
+func (h *Handler) SubscriptionResumed() domain.EventHandler {
+	return func(ctx context.Context, event domain.Event) error {
+		subscription, ok := event.Payload.(*entity.Subscription)
 
-		weatherTmpl, err := template.ParseFiles("templates/weather_update.html")
+		if !ok {
+			return fmt.Errorf("invalid event type: %T", event)
+		}
 
+		resumedTmpl, err := h.emailTemplate("subscription_resumed.html")
 		if err != nil {
-			return fmt.Errorf("failed to parse confirmation template: %w", err)
+			return err
 		}
 
-		weatherData := struct {
-			UnsubscribeURL string
+		unsubscribeLink := h.unsubscribeURL(subscription.ID)
+
+		resumedData := struct {
 			City           string
-			Temperature    float64
-			Humidity       float64
-			Description    string
+			Frequency      string
+			PauseURL       string
+			UnsubscribeURL string
 		}{
+			City:           subscription.City,
+			Frequency:      strings.ToLower(string(subscription.Frequency)),
+			PauseURL:       h.pauseURL(subscription.ID),
 			UnsubscribeURL: unsubscribeLink,
-			City:           weather.City,
-			Temperature:    weather.Temperature,
-			Humidity:       weather.Humidity,
-			Description:    weather.Description,
 		}
 
 		var bodyBuffer bytes.Buffer
-		if err := weatherTmpl.Execute(&bodyBuffer, weatherData); err != nil {
-			return fmt.Errorf("failed to execute weather update template: %w", err)
+		if err := resumedTmpl.Execute(&bodyBuffer, resumedData); err != nil {
+			return fmt.Errorf("failed to execute subscription resumed template: %w", err)
 		}
 
-		message := bodyBuffer.String()
-
-		h.EmailService.SendMessage(ctx, weather.Email, "Weather update", message)
+		if err := h.EmailService.SendMessage(ctx, subscription.Email, "Your weather updates have resumed", bodyBuffer.String(), domain.ListUnsubscribeHeaders(unsubscribeLink)); err != nil {
+			return fmt.Errorf("failed to send subscription resumed email: %w", err)
+		}
 
 		return nil
 	}
 }
 
+// FetchAndUpdateWeatherSubscribers sends the digest to every due subscriber
+// of frequency. Subscribers are grouped by city so each city's weather is
+// fetched once, and the emails are sent by DigestWorkers concurrent workers.
 func (h *Handler) FetchAndUpdateWeatherSubscribers(frequency entity.Frequency) domain.EventHandler {
 	return func(ctx context.Context, event domain.Event) error {
 		subscriptions, err := h.Repository.GetConfirmedSubscriptions(ctx, frequency)
 		if err != nil {
-			return fmt.Errorf("failed to get daily subscriptions: %w", err)
+			return fmt.Errorf("failed to get %s subscriptions: %w", strings.ToLower(string(frequency)), err)
+		}
+
+		weatherTmpl, err := h.emailTemplate("weather_update.html")
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+
+		cities := map[string][]entity.Subscriber{}
+		due := 0
+		for _, subscription := range subscriptions {
+			if !subscription.IsDue(now) {
+				continue
+			}
+
+			city := weather.NormalizeCityName(subscription.City)
+			cities[city] = append(cities[city], subscription)
+			due++
+		}
+
+		if due == 0 {
+			return nil
+		}
+
+		progress := newDigestProgress(strings.ToLower(string(frequency)), due)
+		stopProgress := progress.report(digestProgressInterval)
+		defer stopProgress()
+
+		tasks := make(chan digestTask)
+
+		var wg sync.WaitGroup
+		for range h.digestWorkers() {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for task := range tasks {
+					h.deliverDigest(ctx, weatherTmpl, task, now, progress)
+				}
+			}()
+		}
+
+	produce:
+		for _, subscribers := range cities {
+			report := h.cityReport(ctx, frequency, subscribers[0].City)
+
+			for _, subscription := range subscribers {
+				select {
+				case tasks <- digestTask{subscription: subscription, report: report}:
+				case <-ctx.Done():
+					break produce
+				}
+			}
+		}
+
+		close(tasks)
+		wg.Wait()
+
+		return ctx.Err()
+	}
+}
+
+// cityReport is the weather shared by every subscriber of one city.
+type cityReport struct {
+	weather  *value_objects.Weather
+	forecast []value_objects.ForecastDay
+	err      error
+}
+
+type digestTask struct {
+	subscription entity.Subscriber
+	report       *cityReport
+}
+
+func (h *Handler) digestWorkers() int {
+	if h.DigestWorkers <= 0 {
+		return defaultDigestWorkers
+	}
+
+	return h.DigestWorkers
+}
+
+func (h *Handler) cityReport(ctx context.Context, frequency entity.Frequency, city string) *cityReport {
+	weather, err := h.WeatherService.GetWeather(ctx, city)
+	if err != nil {
+		fmt.Printf("Failed to get weather for city %s: %v\n", city, err)
+		return &cityReport{err: err}
+	}
+
+	report := &cityReport{weather: weather}
+
+	if frequency == entity.FrequencyDaily {
+		forecast, err := h.WeatherService.GetForecast(ctx, city, digestForecastDays)
+		if err != nil {
+			fmt.Printf("Failed to get forecast for city %s: %v\n", city, err)
+		} else {
+			report.forecast = forecast.Days
+		}
+	}
+
+	return report
+}
+
+func (h *Handler) deliverDigest(ctx context.Context, weatherTmpl *template.Template, task digestTask, now time.Time, progress *digestProgress) {
+	subscription := task.subscription
+	period := subscription.CurrentPeriod(now)
+
+	claimed, err := h.Deliveries.Claim(ctx, subscription.ID, period, deliveryClaimTimeout)
+	if err != nil {
+		fmt.Printf("Failed to claim delivery for subscription %s: %v\n", subscription.ID, err)
+		progress.failed.Add(1)
+		return
+	}
+	if !claimed {
+		progress.skipped.Add(1)
+		return
+	}
+
+	if task.report.err != nil {
+		h.markDeliveryFailed(ctx, subscription.ID, period, task.report.err)
+		progress.failed.Add(1)
+		return
+	}
+
+	if subscription.WebhookURL != "" {
+		h.deliverWebhookDigest(ctx, task, period, progress)
+		return
+	}
+
+	unsubscribeLink := h.unsubscribeURL(subscription.ID)
+
+	emailData := struct {
+		City           string
+		Temperature    float64
+		Humidity       float64
+		Description    string
+		Forecast       []value_objects.ForecastDay
+		UnsubscribeURL string
+		ManageURL      string
+		PauseURL       string
+	}{
+		City:           subscription.City,
+		Temperature:    task.report.weather.Temperature,
+		Humidity:       task.report.weather.Humidity,
+		Description:    task.report.weather.Description,
+		Forecast:       task.report.forecast,
+		UnsubscribeURL: unsubscribeLink,
+		ManageURL:      fmt.Sprintf("%s/manage", h.Config.BaseURL),
+		PauseURL:       h.pauseURL(subscription.ID),
+	}
+
+	var bodyBuffer bytes.Buffer
+	if err := weatherTmpl.Execute(&bodyBuffer, emailData); err != nil {
+		fmt.Printf("Failed to execute weather template: %v\n", err)
+		h.markDeliveryFailed(ctx, subscription.ID, period, err)
+		progress.failed.Add(1)
+		return
+	}
+
+	if err := h.EmailService.SendMessage(
+		ctx,
+		subscription.Email,
+		fmt.Sprintf("Daily Weather Update for %s", subscription.City),
+		bodyBuffer.String(),
+		domain.ListUnsubscribeHeaders(unsubscribeLink),
+	); err != nil {
+		fmt.Printf("Failed to send weather update email: %v\n", err)
+		h.markDeliveryFailed(ctx, subscription.ID, period, err)
+		progress.failed.Add(1)
+		return
+	}
+
+	if err := h.Deliveries.MarkDelivered(ctx, subscription.ID, period, time.Now().UTC()); err != nil {
+		fmt.Printf("Failed to mark subscription %s as sent: %v\n", subscription.ID, err)
+	}
+
+	progress.sent.Add(1)
+}
+
+// deliverWebhookDigest posts the digest to the subscriber's webhook instead
+// of emailing it. The delivery has already been claimed.
+func (h *Handler) deliverWebhookDigest(ctx context.Context, task digestTask, period time.Time, progress *digestProgress) {
+	subscription := task.subscription
+
+	if h.Webhooks == nil {
+		h.markDeliveryFailed(ctx, subscription.ID, period, errWebhooksNotConfigured)
+		progress.failed.Add(1)
+		return
+	}
+
+	event := value_objects.WeatherEvent{
+		City:             subscription.City,
+		Temperature:      task.report.weather.Temperature,
+		Humidity:         task.report.weather.Humidity,
+		Description:      task.report.weather.Description,
+		Forecast:         task.report.forecast,
+		Email:            subscription.Email,
+		UnsubscribeToken: h.unsubscribeToken(subscription.ID),
+	}
+
+	if err := h.Webhooks.Deliver(ctx, subscription.WebhookURL, event); err != nil {
+		fmt.Printf("Failed to deliver weather update to webhook for subscription %s: %v\n", subscription.ID, err)
+		h.markDeliveryFailed(ctx, subscription.ID, period, err)
+		progress.failed.Add(1)
+		return
+	}
+
+	if err := h.Deliveries.MarkDelivered(ctx, subscription.ID, period, time.Now().UTC()); err != nil {
+		fmt.Printf("Failed to mark subscription %s as sent: %v\n", subscription.ID, err)
+	}
+
+	progress.sent.Add(1)
+}
+
+func (h *Handler) markDeliveryFailed(ctx context.Context, subscriptionID uuid.UUID, period time.Time, cause error) {
+	if err := h.Deliveries.MarkFailed(ctx, subscriptionID, period, cause.Error()); err != nil {
+		fmt.Printf("Failed to record failed delivery for subscription %s: %v\n", subscriptionID, err)
+	}
+}
+
+// EvaluateAlertSubscriptions checks every confirmed alert subscription
+// against the cached weather data and emails subscribers whose rule has
+// just started to hold.
+func (h *Handler) EvaluateAlertSubscriptions() domain.EventHandler {
+	return func(ctx context.Context, event domain.Event) error {
+		subscriptions, err := h.Repository.GetConfirmedAlertSubscriptions(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get alert subscriptions: %w", err)
+		}
+
+		alertTmpl, err := h.emailTemplate("weather_alert.html")
+		if err != nil {
+			return err
 		}
 
 		for _, subscription := range subscriptions {
+			rule := subscription.AlertRule
+			if rule == nil {
+				continue
+			}
+
 			weather, err := h.WeatherService.GetWeather(ctx, subscription.City)
 			if err != nil {
 				fmt.Printf("Failed to get weather for city %s: %v\n", subscription.City, err)
 				continue
 			}
-			emailData := struct {
+
+			var today *value_objects.ForecastDay
+			if rule.Metric.NeedsForecast() {
+				forecast, err := h.WeatherService.GetForecast(ctx, subscription.City, 1)
+				if err != nil {
+					fmt.Printf("Failed to get forecast for city %s: %v\n", subscription.City, err)
+					continue
+				}
+				if len(forecast.Days) > 0 {
+					today = &forecast.Days[0]
+				}
+			}
+
+			value, err := rule.Metric.Value(*weather, today)
+			if err != nil {
+				fmt.Printf("Failed to evaluate alert for subscription %s: %v\n", subscription.ID, err)
+				continue
+			}
+
+			now := time.Now().UTC()
+			triggered, notify := rule.Evaluate(value, subscription.AlertTriggered, subscription.LastSentAt, now)
+
+			if !notify {
+				if triggered != subscription.AlertTriggered {
+					if err := h.Repository.UpdateAlertState(ctx, subscription.ID, triggered, nil); err != nil {
+						fmt.Printf("Failed to update alert state: %v\n", err)
+					}
+				}
+				continue
+			}
+
+			unsubscribeLink := h.unsubscribeURL(subscription.ID)
+
+			alertData := struct {
 				City           string
-				Temperature    float64
-				Humidity       float64
+				Metric         string
+				Comparator     string
+				Threshold      float64
+				Value          float64
 				Description    string
 				UnsubscribeURL string
+				PauseURL       string
 			}{
 				City:           subscription.City,
-				Temperature:    weather.Temperature,
-				Humidity:       weather.Humidity,
+				Metric:         strings.ToLower(strings.ReplaceAll(string(rule.Metric), "_", " ")),
+				Comparator:     rule.Comparator.Symbol(),
+				Threshold:      rule.Threshold,
+				Value:          value,
 				Description:    weather.Description,
-				UnsubscribeURL: fmt.Sprintf("%s/unsubscribe/%s", h.Config.BaseURL, subscription.UnsubscribeToken),
-			}
-
-			weatherTmpl, err := template.ParseFiles("templates/weather_update.html")
-			if err != nil {
-				fmt.Printf("Failed to parse weather template: %v\n", err)
-				continue
+				UnsubscribeURL: unsubscribeLink,
+				PauseURL:       h.pauseURL(subscription.ID),
 			}
 
 			var bodyBuffer bytes.Buffer
-			if err := weatherTmpl.Execute(&bodyBuffer, emailData); err != nil {
-				fmt.Printf("Failed to execute weather template: %v\n", err)
+			if err := alertTmpl.Execute(&bodyBuffer, alertData); err != nil {
+				fmt.Printf("Failed to execute alert template: %v\n", err)
 				continue
 			}
 
 			if err := h.EmailService.SendMessage(
 				ctx,
 				subscription.Email,
-				fmt.Sprintf("Daily Weather Update for %s", subscription.City),
+				fmt.Sprintf("Weather alert for %s", subscription.City),
 				bodyBuffer.String(),
+				domain.ListUnsubscribeHeaders(unsubscribeLink),
 			); err != nil {
-				fmt.Printf("Failed to send weather update email: %v\n", err)
+				fmt.Printf("Failed to send weather alert email: %v\n", err)
 				continue
 			}
 
+			if err := h.Repository.UpdateAlertState(ctx, subscription.ID, true, &now); err != nil {
+				fmt.Printf("Failed to update alert state: %v\n", err)
+			}
 		}
 
 		return nil
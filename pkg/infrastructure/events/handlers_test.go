@@ -0,0 +1,241 @@
+package events
+
+import (
+	"context"
+	"html/template"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	config "github.com/danik-tro/weather-subscriber/pkg"
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
+	value_objects "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+	"github.com/danik-tro/weather-subscriber/pkg/external/weather"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/tokens"
+)
+
+type countingWeatherClient struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (c *countingWeatherClient) GetCurrentWeather(ctx context.Context, city string) (*value_objects.Weather, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls[city]++
+	return &value_objects.Weather{Temperature: 10, Description: "Sunny"}, nil
+}
+
+func (c *countingWeatherClient) GetForecast(ctx context.Context, city string, days int) (*value_objects.Forecast, error) {
+	return &value_objects.Forecast{}, nil
+}
+
+func (c *countingWeatherClient) GetLocation(ctx context.Context, city string) (*value_objects.Location, error) {
+	return &value_objects.Location{Name: city}, nil
+}
+
+type noopWeatherCache struct{}
+
+func (noopWeatherCache) GetWeather(ctx context.Context, city string) (*value_objects.Weather, error) {
+	return nil, nil
+}
+
+func (noopWeatherCache) SetWeather(ctx context.Context, city string, weather *value_objects.Weather, ttl time.Duration) error {
+	return nil
+}
+
+func (noopWeatherCache) GetForecast(ctx context.Context, city string, days int) (*value_objects.Forecast, error) {
+	return nil, nil
+}
+
+func (noopWeatherCache) SetForecast(ctx context.Context, city string, days int, forecast *value_objects.Forecast, ttl time.Duration) error {
+	return nil
+}
+
+func (noopWeatherCache) GetLocation(ctx context.Context, city string) (*value_objects.Location, error) {
+	return nil, nil
+}
+
+func (noopWeatherCache) SetLocation(ctx context.Context, city string, location *value_objects.Location, ttl time.Duration) error {
+	return nil
+}
+
+type recordingEmailService struct {
+	mu         sync.Mutex
+	recipients []string
+	bodies     []string
+	headers    []domain.EmailHeaders
+}
+
+func (s *recordingEmailService) SendMessage(ctx context.Context, recipient string, subject string, body string, headers domain.EmailHeaders) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recipients = append(s.recipients, recipient)
+	s.bodies = append(s.bodies, body)
+	s.headers = append(s.headers, headers)
+	return nil
+}
+
+type recordingNotificationChannel struct {
+	mu      sync.Mutex
+	targets []string
+	events  []value_objects.WeatherEvent
+}
+
+func (c *recordingNotificationChannel) Deliver(ctx context.Context, target string, event value_objects.WeatherEvent) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.targets = append(c.targets, target)
+	c.events = append(c.events, event)
+	return nil
+}
+
+type stubSubscriptionRepository struct {
+	domain_repository.SubscriptionRepository
+	subscribers []entity.Subscriber
+}
+
+func (r *stubSubscriptionRepository) GetConfirmedSubscriptions(ctx context.Context, frequency entity.Frequency) ([]entity.Subscriber, error) {
+	return r.subscribers, nil
+}
+
+type memoryDeliveryRepository struct {
+	mu        sync.Mutex
+	delivered map[uuid.UUID]bool
+}
+
+func (r *memoryDeliveryRepository) Claim(ctx context.Context, subscriptionID uuid.UUID, period time.Time, staleAfter time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return !r.delivered[subscriptionID], nil
+}
+
+func (r *memoryDeliveryRepository) MarkDelivered(ctx context.Context, subscriptionID uuid.UUID, period time.Time, sentAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.delivered[subscriptionID] = true
+	return nil
+}
+
+func (r *memoryDeliveryRepository) MarkFailed(ctx context.Context, subscriptionID uuid.UUID, period time.Time, reason string) error {
+	return nil
+}
+
+func TestFetchAndUpdateWeatherSubscribers_GroupsByCity(t *testing.T) {
+	subscriber := func(email, city string) entity.Subscriber {
+		return entity.Subscriber{ID: uuid.New(), Email: email, City: city, Frequency: entity.FrequencyHourly}
+	}
+	subscribers := []entity.Subscriber{
+		subscriber("a@example.com", "Kyiv"),
+		subscriber("b@example.com", "kyiv "),
+		subscriber("c@example.com", "KYIV"),
+		subscriber("d@example.com", "Lviv"),
+		subscriber("e@example.com", "Lviv"),
+	}
+
+	client := &countingWeatherClient{calls: map[string]int{}}
+	emails := &recordingEmailService{}
+	deliveries := &memoryDeliveryRepository{delivered: map[uuid.UUID]bool{subscribers[4].ID: true}}
+	links, err := tokens.NewHMACActionLinks("test:secret")
+	require.NoError(t, err)
+
+	handler := Handler{
+		EmailService:   emails,
+		WeatherService: weather.NewWeatherService(client, noopWeatherCache{}),
+		Repository:     &stubSubscriptionRepository{subscribers: subscribers},
+		Deliveries:     deliveries,
+		Templates:      template.Must(template.New("weather_update.html").Parse("{{.City}} {{.Temperature}}")),
+		ActionLinks:    links,
+		DigestWorkers:  3,
+	}
+
+	err = handler.FetchAndUpdateWeatherSubscribers(entity.FrequencyHourly)(context.Background(), domain.Event{})
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]int{"KYIV": 1, "LVIV": 1}, client.calls)
+	require.ElementsMatch(t, []string{"a@example.com", "b@example.com", "c@example.com", "d@example.com"}, emails.recipients)
+	require.Len(t, deliveries.delivered, 5)
+
+	require.Len(t, emails.headers, 4)
+	for _, headers := range emails.headers {
+		require.Equal(t, domain.ListUnsubscribeOneClick, headers[domain.ListUnsubscribePostHeader])
+		require.Regexp(t, `^</unsubscribe/[A-Za-z0-9_.-]+>$`, headers[domain.ListUnsubscribeHeader])
+	}
+}
+
+func TestFetchAndUpdateWeatherSubscribers_DeliversToWebhooks(t *testing.T) {
+	subscribers := []entity.Subscriber{
+		{ID: uuid.New(), Email: "mail@example.com", City: "Kyiv", Frequency: entity.FrequencyHourly},
+		{ID: uuid.New(), Email: "hook@example.com", City: "Kyiv", Frequency: entity.FrequencyHourly, WebhookURL: "https://hooks.example.com/weather"},
+	}
+
+	emails := &recordingEmailService{}
+	webhooks := &recordingNotificationChannel{}
+	deliveries := &memoryDeliveryRepository{delivered: map[uuid.UUID]bool{}}
+	links, err := tokens.NewHMACActionLinks("test:secret")
+	require.NoError(t, err)
+
+	handler := Handler{
+		EmailService:   emails,
+		WeatherService: weather.NewWeatherService(&countingWeatherClient{calls: map[string]int{}}, noopWeatherCache{}),
+		Repository:     &stubSubscriptionRepository{subscribers: subscribers},
+		Deliveries:     deliveries,
+		Config:         config.Config{ActionLinkTTL: time.Hour},
+		Templates:      template.Must(template.New("weather_update.html").Parse("{{.City}}")),
+		ActionLinks:    links,
+		Webhooks:       webhooks,
+	}
+
+	err = handler.FetchAndUpdateWeatherSubscribers(entity.FrequencyHourly)(context.Background(), domain.Event{})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"mail@example.com"}, emails.recipients)
+	require.Equal(t, []string{"https://hooks.example.com/weather"}, webhooks.targets)
+	require.Len(t, deliveries.delivered, 2)
+
+	event := webhooks.events[0]
+	require.Equal(t, "Kyiv", event.City)
+	require.Equal(t, "Sunny", event.Description)
+	require.Equal(t, "hook@example.com", event.Email)
+	link, err := links.Verify(event.UnsubscribeToken, domain.UnsubscribeLinkAction)
+	require.NoError(t, err)
+	require.Equal(t, subscribers[1].ID, link.SubscriptionID)
+}
+
+func TestUserSubscribedSendsSignedConfirmationLink(t *testing.T) {
+	links, err := tokens.NewHMACActionLinks("test:secret")
+	require.NoError(t, err)
+
+	emails := &recordingEmailService{}
+	handler := Handler{
+		EmailService: emails,
+		Config:       config.Config{BaseURL: "https://weather.example.com", ConfirmationTokenTTL: time.Hour},
+		Templates:    template.Must(template.New("confirmation.html").Parse("{{.ConfirmURL}}")),
+		ActionLinks:  links,
+	}
+
+	subscription, err := entity.NewSubscription("user@example.com", "Kyiv", entity.FrequencyDaily)
+	require.NoError(t, err)
+
+	require.NoError(t, handler.UserSubscribed()(context.Background(), domain.Event{Type: domain.UserSubscribed, Payload: subscription}))
+	require.Len(t, emails.bodies, 1)
+
+	match := regexp.MustCompile(`https://weather\.example\.com/confirm/([A-Za-z0-9_.-]+)`).FindStringSubmatch(emails.bodies[0])
+	require.NotNil(t, match)
+
+	link, err := links.Verify(match[1], domain.ConfirmLinkAction)
+	require.NoError(t, err)
+	require.Equal(t, subscription.ID, link.SubscriptionID)
+	require.True(t, link.ExpiresAt.Equal(subscription.ConfirmationSentAt.Add(time.Hour).Truncate(time.Second)))
+}
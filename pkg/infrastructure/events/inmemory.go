@@ -2,6 +2,7 @@ package events
 
 import (
 	"context"
+	"errors"
 	"log"
 	"runtime"
 	"sync"
@@ -60,15 +61,22 @@ func (p *Publisher) Trigger(event domain.Event) []error {
 		return nil
 	}
 
-	var errors []error
+	var errs []error
 
 	for _, handler := range handlers {
 		if err := handler(event.Context, event); err != nil {
-			errors = append(errors, err)
+			errs = append(errs, err)
 		}
 	}
 
-	return errors
+	return errs
+}
+
+// Dispatch runs the handlers right away, so a failing handler makes the
+// outbox relay retry the message with its retry policy.
+func (p *Publisher) Dispatch(ctx context.Context, event domain.Event) error {
+	event.Context = ctx
+	return errors.Join(p.Trigger(event)...)
 }
 
 func (p *Publisher) TriggerAsync(event domain.Event) {
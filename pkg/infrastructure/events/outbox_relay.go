@@ -0,0 +1,158 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
+)
+
+const outboxLease = time.Minute
+
+// OutboxRelay polls the outbox table and dispatches pending messages,
+// marking them as published only once the dispatcher took them: after every
+// handler succeeded on the in-process bus, or once the event was added to
+// the stream on Redis. Failed messages are retried with the event's backoff
+// and dead-lettered once its retry policy is exhausted.
+type OutboxRelay struct {
+	repo        domain_repository.OutboxRepository
+	deadLetters domain_repository.DeadLetterRepository
+	dispatcher  Dispatcher
+	policies    RetryPolicies
+	interval    time.Duration
+	batchSize   int
+	wg          sync.WaitGroup
+	ctx         context.Context
+	cancel      context.CancelFunc
+}
+
+func NewOutboxRelay(repo domain_repository.OutboxRepository, deadLetters domain_repository.DeadLetterRepository, dispatcher Dispatcher, policies RetryPolicies, interval time.Duration, batchSize int) *OutboxRelay {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &OutboxRelay{
+		repo:        repo,
+		deadLetters: deadLetters,
+		dispatcher:  dispatcher,
+		policies:    policies,
+		interval:    interval,
+		batchSize:   batchSize,
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+func (r *OutboxRelay) Start() {
+	r.wg.Add(1)
+
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := r.RelayPending(r.ctx); err != nil && !errors.Is(err, context.Canceled) {
+				log.Printf("Outbox relay failed: %v", err)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-r.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// RelayPending publishes one batch of pending messages and returns how many
+// were published successfully.
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	messages, err := r.repo.ClaimPending(ctx, r.batchSize, outboxLease)
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+
+	published := 0
+
+	for _, message := range messages {
+		if err := r.publish(ctx, message); err != nil {
+			log.Printf("Failed to publish outbox message %s (%s, attempt %d): %v", message.ID, message.EventType, message.Attempts, err)
+			r.retryOrDeadLetter(ctx, message, err)
+			continue
+		}
+
+		if err := r.repo.MarkPublished(ctx, message.ID); err != nil {
+			log.Printf("Failed to mark outbox message %s as published: %v", message.ID, err)
+			continue
+		}
+
+		published++
+	}
+
+	return published, nil
+}
+
+func (r *OutboxRelay) retryOrDeadLetter(ctx context.Context, message domain.OutboxMessage, cause error) {
+	policy := r.policies.For(message.EventType)
+
+	var decodeErr *payloadError
+	if !policy.Exhausted(message.Attempts) && !errors.As(cause, &decodeErr) && retryable(cause) {
+		retryAt := time.Now().UTC().Add(policy.Backoff(message.Attempts))
+		if err := r.repo.MarkFailed(ctx, message.ID, cause.Error(), retryAt); err != nil {
+			log.Printf("Failed to mark outbox message %s as failed: %v", message.ID, err)
+		}
+		return
+	}
+
+	deadLetter(ctx, r.deadLetters, domain.Event{Type: message.EventType}, message.Payload, message.Attempts, cause)
+
+	// The message now lives in the dead-letter store; replaying it enqueues
+	// a fresh outbox message.
+	if err := r.repo.MarkPublished(ctx, message.ID); err != nil {
+		log.Printf("Failed to retire outbox message %s: %v", message.ID, err)
+	}
+}
+
+func (r *OutboxRelay) publish(ctx context.Context, message domain.OutboxMessage) error {
+	payload, err := DecodePayload(message.EventType, message.Payload)
+	if err != nil {
+		// A payload that cannot be decoded will never succeed, so it is
+		// dead-lettered straight away.
+		return &payloadError{err: err}
+	}
+
+	return r.dispatcher.Dispatch(ctx, domain.Event{
+		Type:    message.EventType,
+		Payload: payload,
+	})
+}
+
+func (r *OutboxRelay) Close() {
+	r.cancel()
+	r.wg.Wait()
+	log.Println("Outbox relay closed")
+}
+
+type payloadError struct {
+	err error
+}
+
+func (e *payloadError) Error() string {
+	return e.err.Error()
+}
+
+func (e *payloadError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,224 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	value_objects "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+)
+
+type fakeOutboxRepository struct {
+	pending   []domain.OutboxMessage
+	published []uuid.UUID
+	failed    map[uuid.UUID]string
+	retryAt   map[uuid.UUID]time.Time
+}
+
+func (r *fakeOutboxRepository) Enqueue(ctx context.Context, message *domain.OutboxMessage) error {
+	r.pending = append(r.pending, *message)
+	return nil
+}
+
+func (r *fakeOutboxRepository) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]domain.OutboxMessage, error) {
+	claimed := r.pending
+	r.pending = nil
+	return claimed, nil
+}
+
+func (r *fakeOutboxRepository) MarkPublished(ctx context.Context, id uuid.UUID) error {
+	r.published = append(r.published, id)
+	return nil
+}
+
+func (r *fakeOutboxRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string, retryAt time.Time) error {
+	if r.failed == nil {
+		r.failed = map[uuid.UUID]string{}
+		r.retryAt = map[uuid.UUID]time.Time{}
+	}
+	r.failed[id] = reason
+	r.retryAt[id] = retryAt
+	return nil
+}
+
+type fakeDeadLetterRepository struct {
+	mu    sync.Mutex
+	saved []domain.DeadLetter
+}
+
+func (r *fakeDeadLetterRepository) Save(ctx context.Context, deadLetter *domain.DeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.saved = append(r.saved, *deadLetter)
+	return nil
+}
+
+func (r *fakeDeadLetterRepository) Saved() []domain.DeadLetter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]domain.DeadLetter(nil), r.saved...)
+}
+
+func (r *fakeDeadLetterRepository) List(ctx context.Context, limit, offset int, includeReplayed bool) ([]domain.DeadLetter, error) {
+	return r.saved, nil
+}
+
+func (r *fakeDeadLetterRepository) FindByID(ctx context.Context, id uuid.UUID) (*domain.DeadLetter, error) {
+	return nil, domain.ErrDeadLetterNotFound
+}
+
+func (r *fakeDeadLetterRepository) Replay(ctx context.Context, id uuid.UUID, message *domain.OutboxMessage) error {
+	return nil
+}
+
+func TestDecodePayload_RoundTrip(t *testing.T) {
+	subscription, err := entity.NewSubscription("test@example.com", "Kyiv", entity.FrequencyDaily)
+	require.NoError(t, err)
+
+	data, err := EncodePayload(domain.Event{Type: domain.UserSubscribed, Payload: subscription})
+	require.NoError(t, err)
+
+	payload, err := DecodePayload(domain.UserSubscribed, data)
+	require.NoError(t, err)
+
+	decoded, ok := payload.(*entity.Subscription)
+	require.True(t, ok)
+	require.Equal(t, subscription.ID, decoded.ID)
+	require.Equal(t, subscription.ConfirmationTokenHash, decoded.ConfirmationTokenHash)
+	require.Empty(t, decoded.ConfirmationToken)
+	require.NotContains(t, string(data), subscription.ConfirmationToken)
+
+	_, err = DecodePayload("UNKNOWN", data)
+	require.Error(t, err)
+}
+
+func TestOutboxRelay_RelayPending(t *testing.T) {
+	subscription, err := entity.NewSubscription("test@example.com", "Kyiv", entity.FrequencyDaily)
+	require.NoError(t, err)
+
+	data, err := EncodePayload(domain.Event{Type: domain.UserSubscribed, Payload: subscription})
+	require.NoError(t, err)
+
+	ok := domain.NewOutboxMessage(domain.UserSubscribed, data)
+	failing := domain.NewOutboxMessage(domain.UserSubscribed, data)
+
+	publisher := NewPublisher(1, 1)
+	publisher.Register(domain.UserSubscribed, func(ctx context.Context, event domain.Event) error {
+		if event.Payload.(*entity.Subscription).Email == "fail@example.com" {
+			return errors.New("smtp unavailable")
+		}
+		return nil
+	})
+
+	failingSubscription := *subscription
+	failingSubscription.Email = "fail@example.com"
+	failing.Payload, err = EncodePayload(domain.Event{Type: domain.UserSubscribed, Payload: &failingSubscription})
+	require.NoError(t, err)
+
+	repo := &fakeOutboxRepository{pending: []domain.OutboxMessage{*ok, *failing}}
+	relay := NewOutboxRelay(repo, &fakeDeadLetterRepository{}, publisher, nil, time.Second, 10)
+
+	published, err := relay.RelayPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+	require.Equal(t, []uuid.UUID{ok.ID}, repo.published)
+	require.Contains(t, repo.failed[failing.ID], "smtp unavailable")
+}
+
+func TestOutboxRelay_DeadLettersExhaustedMessages(t *testing.T) {
+	publisher := NewPublisher(1, 1)
+	publisher.Register(domain.UserSubscribed, func(ctx context.Context, event domain.Event) error {
+		return errors.New("smtp unavailable")
+	})
+
+	subscription, err := entity.NewSubscription("test@example.com", "Kyiv", entity.FrequencyDaily)
+	require.NoError(t, err)
+	data, err := EncodePayload(domain.Event{Type: domain.UserSubscribed, Payload: subscription})
+	require.NoError(t, err)
+
+	retrying := domain.NewOutboxMessage(domain.UserSubscribed, data)
+	retrying.Attempts = 1
+	exhausted := domain.NewOutboxMessage(domain.UserSubscribed, data)
+	exhausted.Attempts = 3
+	undecodable := domain.NewOutboxMessage(domain.UserSubscribed, []byte(`"not a subscription"`))
+	undecodable.Attempts = 1
+
+	repo := &fakeOutboxRepository{pending: []domain.OutboxMessage{*retrying, *exhausted, *undecodable}}
+	deadLetters := &fakeDeadLetterRepository{}
+	policies := RetryPolicies{domain.UserSubscribed: {MaxAttempts: 3, InitialBackoff: time.Second}}
+
+	relay := NewOutboxRelay(repo, deadLetters, publisher, policies, time.Second, 10)
+
+	published, err := relay.RelayPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 0, published)
+
+	require.Contains(t, repo.failed, retrying.ID)
+	require.ElementsMatch(t, []uuid.UUID{exhausted.ID, undecodable.ID}, repo.published)
+	require.Len(t, deadLetters.saved, 2)
+	require.Equal(t, 3, deadLetters.saved[0].Attempts)
+	require.Equal(t, data, deadLetters.saved[0].Payload)
+}
+
+func TestOutboxRelay_RetriesAfterBackoff(t *testing.T) {
+	publisher := NewPublisher(1, 1)
+	publisher.Register(domain.WeatherEvent, func(ctx context.Context, event domain.Event) error {
+		return errors.New("smtp unavailable")
+	})
+
+	data, err := EncodePayload(domain.Event{Type: domain.WeatherEvent, Payload: value_objects.WeatherEvent{Email: "test@example.com"}})
+	require.NoError(t, err)
+	message := domain.NewOutboxMessage(domain.WeatherEvent, data)
+	message.Attempts = 2
+
+	repo := &fakeOutboxRepository{pending: []domain.OutboxMessage{*message}}
+	policies := RetryPolicies{domain.WeatherEvent: {MaxAttempts: 3, InitialBackoff: time.Minute, Multiplier: 2}}
+	relay := NewOutboxRelay(repo, &fakeDeadLetterRepository{}, publisher, policies, time.Second, 10)
+
+	before := time.Now()
+	_, err = relay.RelayPending(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, "smtp unavailable", repo.failed[message.ID])
+	require.WithinDuration(t, before.Add(2*time.Minute), repo.retryAt[message.ID], time.Second)
+	require.Empty(t, repo.published)
+}
+
+func TestOutboxRelay_DeadLettersPermanentEmailFailuresWithoutRetrying(t *testing.T) {
+	publisher := NewPublisher(1, 1)
+
+	calls := 0
+	publisher.Register(domain.WeatherEvent, func(ctx context.Context, event domain.Event) error {
+		calls++
+		return fmt.Errorf("failed to send weather update email: %w", domain.ErrEmailPermanent)
+	})
+
+	data, err := EncodePayload(domain.Event{Type: domain.WeatherEvent, Payload: value_objects.WeatherEvent{Email: "test@example.com"}})
+	require.NoError(t, err)
+	message := domain.NewOutboxMessage(domain.WeatherEvent, data)
+	message.Attempts = 1
+
+	repo := &fakeOutboxRepository{pending: []domain.OutboxMessage{*message}}
+	deadLetters := &fakeDeadLetterRepository{}
+	policies := RetryPolicies{domain.WeatherEvent: {MaxAttempts: 3, InitialBackoff: time.Millisecond}}
+	relay := NewOutboxRelay(repo, deadLetters, publisher, policies, time.Second, 10)
+
+	_, err = relay.RelayPending(context.Background())
+	require.NoError(t, err)
+
+	require.Equal(t, 1, calls)
+	require.Empty(t, repo.failed)
+	require.Equal(t, []uuid.UUID{message.ID}, repo.published)
+	require.Len(t, deadLetters.saved, 1)
+	require.Equal(t, 1, deadLetters.saved[0].Attempts)
+}
@@ -0,0 +1,66 @@
+package events
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+const digestProgressInterval = 10 * time.Second
+
+// digestProgress counts the outcome of every subscriber in a digest run.
+type digestProgress struct {
+	name    string
+	total   int
+	sent    atomic.Int64
+	skipped atomic.Int64
+	failed  atomic.Int64
+	started time.Time
+}
+
+func newDigestProgress(name string, total int) *digestProgress {
+	return &digestProgress{
+		name:    name,
+		total:   total,
+		started: time.Now(),
+	}
+}
+
+func (p *digestProgress) String() string {
+	sent, skipped, failed := p.sent.Load(), p.skipped.Load(), p.failed.Load()
+
+	return fmt.Sprintf(
+		"%s digest: %d/%d processed (%d sent, %d skipped, %d failed) in %s",
+		p.name, sent+skipped+failed, p.total, sent, skipped, failed, time.Since(p.started).Round(time.Millisecond),
+	)
+}
+
+// report logs the progress every interval until the returned stop function
+// is called, which logs the final summary.
+func (p *digestProgress) report(interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				log.Println(p)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-finished
+		log.Println(p)
+	}
+}
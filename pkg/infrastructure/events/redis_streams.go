@@ -0,0 +1,546 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
+)
+
+const (
+	streamFieldType    = "type"
+	streamFieldPayload = "payload"
+)
+
+type RedisStreamsConfig struct {
+	// Prefix namespaces the stream keys: <prefix>:events:<event type>.
+	Prefix string
+	// Group is the consumer group shared by all replicas, so every event is
+	// handled by exactly one of them.
+	Group string
+	// Consumer identifies this replica within the group.
+	Consumer string
+	// BatchSize is the number of entries read per XREADGROUP call.
+	BatchSize int64
+	// Block is how long XREADGROUP waits for new entries.
+	Block time.Duration
+	// ClaimIdle is how long an entry may stay unacknowledged before another
+	// consumer takes it over, e.g. after the original consumer crashed.
+	ClaimIdle time.Duration
+	// ReclaimInterval is how often pending entries are checked for retry.
+	ReclaimInterval time.Duration
+	// ConsumerIdle is how long another consumer may go without reading
+	// before it is removed from the group, once none of its entries are
+	// pending. Consumer names change on every restart, so without this the
+	// group keeps growing.
+	ConsumerIdle time.Duration
+	// MaxLen approximately caps the length of each stream.
+	MaxLen int64
+}
+
+// RedisStreamsPublisher is a domain.EventPublisher backed by Redis Streams.
+// Events dispatched from the outbox survive restarts and are shared between
+// replicas through a consumer group; an entry is acknowledged only after all
+// of its handlers succeeded or it was dead-lettered. Retries are redeliveries of
+// the pending entry rather than in-process sleeps, so a failing event never
+// blocks the consumer.
+type RedisStreamsPublisher struct {
+	client      *redis.Client
+	config      RedisStreamsConfig
+	handlers    map[domain.EventType][]domain.EventHandler
+	policies    RetryPolicies
+	deadLetters domain_repository.DeadLetterRepository
+	// inFlight holds the IDs of entries this consumer is handling right now,
+	// so reclaim does not pick them up a second time.
+	inFlight sync.Map
+	mu       sync.RWMutex
+	wg       sync.WaitGroup
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+func NewRedisStreamsPublisher(redisAddress, password string, db int, config RedisStreamsConfig) (*RedisStreamsPublisher, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     redisAddress,
+		Password: password,
+		DB:       db,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Ping(ctx).Err(); err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+
+	if config.Group == "" {
+		config.Group = "weather_subscriber"
+	}
+	if config.Consumer == "" {
+		config.Consumer = defaultConsumerName()
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 10
+	}
+	if config.Block <= 0 {
+		config.Block = 5 * time.Second
+	}
+	if config.ClaimIdle <= 0 {
+		config.ClaimIdle = time.Minute
+	}
+	if config.ReclaimInterval <= 0 {
+		config.ReclaimInterval = 5 * time.Second
+	}
+	if config.ConsumerIdle <= 0 {
+		config.ConsumerIdle = time.Hour
+	}
+	if config.MaxLen <= 0 {
+		config.MaxLen = 10000
+	}
+
+	publisherCtx, publisherCancel := context.WithCancel(context.Background())
+
+	return &RedisStreamsPublisher{
+		client:   client,
+		config:   config,
+		handlers: make(map[domain.EventType][]domain.EventHandler),
+		ctx:      publisherCtx,
+		cancel:   publisherCancel,
+	}, nil
+}
+
+func defaultConsumerName() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+func (p *RedisStreamsPublisher) UseRetries(policies RetryPolicies, deadLetters domain_repository.DeadLetterRepository) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.policies = policies
+	p.deadLetters = deadLetters
+}
+
+func (p *RedisStreamsPublisher) Register(eventType domain.EventType, handler domain.EventHandler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.handlers[eventType] = append(p.handlers[eventType], handler)
+}
+
+// Trigger runs the handlers in-process, like the in-memory publisher; events
+// from Dispatch and TriggerAsync go through the stream.
+func (p *RedisStreamsPublisher) Trigger(event domain.Event) []error {
+	p.mu.RLock()
+	handlers := p.handlers[event.Type]
+	p.mu.RUnlock()
+
+	var errs []error
+
+	for _, handler := range handlers {
+		if err := handler(event.Context, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+func (p *RedisStreamsPublisher) TriggerAsync(event domain.Event) {
+	ctx, cancel := context.WithTimeout(p.ctx, 5*time.Second)
+	defer cancel()
+
+	if err := p.Dispatch(ctx, event); err != nil {
+		log.Printf("Dropping event %s: %v", event.Type, err)
+	}
+}
+
+// Dispatch adds event to its stream. From then on the consumer group
+// handles, retries and dead-letters it, so the outbox message can be marked
+// published.
+func (p *RedisStreamsPublisher) Dispatch(ctx context.Context, event domain.Event) error {
+	values, err := encodeStreamMessage(event)
+	if err != nil {
+		return err
+	}
+
+	if err := p.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: p.streamKey(event.Type),
+		MaxLen: p.config.MaxLen,
+		Approx: true,
+		Values: values,
+	}).Err(); err != nil {
+		return fmt.Errorf("failed to add event %s to Redis: %w", event.Type, err)
+	}
+
+	return nil
+}
+
+// Start creates the consumer groups for every registered event type and
+// starts the consumer and the pending-entry reclaimer. Handlers must be
+// registered before Start.
+func (p *RedisStreamsPublisher) Start() {
+	p.mu.RLock()
+	streams := make(map[string]domain.EventType, len(p.handlers))
+	for eventType := range p.handlers {
+		streams[p.streamKey(eventType)] = eventType
+	}
+	p.mu.RUnlock()
+
+	if len(streams) == 0 {
+		return
+	}
+
+	for stream := range streams {
+		err := p.client.XGroupCreateMkStream(p.ctx, stream, p.config.Group, "0").Err()
+		if err != nil && !isBusyGroup(err) {
+			log.Printf("Failed to create consumer group for %s: %v", stream, err)
+		}
+	}
+
+	p.wg.Add(2)
+	go p.consume(streams)
+	go p.reclaim(streams)
+
+	log.Printf("Redis Streams consumer %s started in group %s", p.config.Consumer, p.config.Group)
+}
+
+func (p *RedisStreamsPublisher) consume(streams map[string]domain.EventType) {
+	defer p.wg.Done()
+
+	args := make([]string, 0, len(streams)*2)
+	for stream := range streams {
+		args = append(args, stream)
+	}
+	for range streams {
+		args = append(args, ">")
+	}
+
+	for p.ctx.Err() == nil {
+		results, err := p.client.XReadGroup(p.ctx, &redis.XReadGroupArgs{
+			Group:    p.config.Group,
+			Consumer: p.config.Consumer,
+			Streams:  args,
+			Count:    p.config.BatchSize,
+			Block:    p.config.Block,
+		}).Result()
+
+		if err != nil {
+			if errors.Is(err, redis.Nil) || p.ctx.Err() != nil {
+				continue
+			}
+
+			log.Printf("Failed to read events from Redis: %v", err)
+			p.sleep(time.Second)
+			continue
+		}
+
+		for _, result := range results {
+			for _, message := range result.Messages {
+				p.handleMessage(result.Stream, message, 1)
+			}
+		}
+	}
+}
+
+// reclaim retries failed entries and takes over entries of crashed
+// consumers, then removes consumers that are gone. Both passes walk the
+// whole pending list with a cursor, so entries that are not due yet never
+// hide the ones behind them.
+func (p *RedisStreamsPublisher) reclaim(streams map[string]domain.EventType) {
+	defer p.wg.Done()
+
+	for p.sleep(p.config.ReclaimInterval) {
+		for stream, eventType := range streams {
+			p.mu.RLock()
+			policy := p.policies.For(eventType)
+			p.mu.RUnlock()
+
+			p.retryPending(stream, policy)
+			p.takeOver(stream, policy)
+			p.removeIdleConsumers(stream)
+		}
+	}
+}
+
+// retryPending claims this consumer's failed entries once they have been
+// idle for the backoff of their retry policy.
+func (p *RedisStreamsPublisher) retryPending(stream string, policy domain.RetryPolicy) {
+	start := "-"
+
+	for p.ctx.Err() == nil {
+		pending, err := p.client.XPendingExt(p.ctx, &redis.XPendingExtArgs{
+			Stream:   stream,
+			Group:    p.config.Group,
+			Start:    start,
+			End:      "+",
+			Count:    p.config.BatchSize,
+			Consumer: p.config.Consumer,
+		}).Result()
+
+		if err != nil {
+			if p.ctx.Err() == nil {
+				log.Printf("Failed to list pending events on %s: %v", stream, err)
+			}
+			return
+		}
+
+		for _, entry := range pending {
+			minIdle := policy.Backoff(int(entry.RetryCount))
+			if entry.Idle < minIdle {
+				continue
+			}
+
+			if _, busy := p.inFlight.Load(entry.ID); busy {
+				continue
+			}
+
+			messages, err := p.client.XClaim(p.ctx, &redis.XClaimArgs{
+				Stream:   stream,
+				Group:    p.config.Group,
+				Consumer: p.config.Consumer,
+				MinIdle:  minIdle,
+				Messages: []string{entry.ID},
+			}).Result()
+
+			if err != nil {
+				if p.ctx.Err() == nil {
+					log.Printf("Failed to claim pending event %s on %s: %v", entry.ID, stream, err)
+				}
+				continue
+			}
+
+			for _, message := range messages {
+				p.handleMessage(stream, message, int(entry.RetryCount)+1)
+			}
+		}
+
+		if int64(len(pending)) < p.config.BatchSize {
+			return
+		}
+		start = "(" + pending[len(pending)-1].ID
+	}
+}
+
+// takeOver moves entries that no consumer has touched for longer than
+// ClaimIdle, and longer than any backoff of the retry policy, to this
+// consumer and handles them. XAUTOCLAIM resumes from the cursor it returns
+// until it has covered the whole pending list.
+func (p *RedisStreamsPublisher) takeOver(stream string, policy domain.RetryPolicy) {
+	minIdle := max(p.config.ClaimIdle, policy.LongestBackoff())
+	start := "0-0"
+
+	for p.ctx.Err() == nil {
+		messages, next, err := p.client.XAutoClaim(p.ctx, &redis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    p.config.Group,
+			Consumer: p.config.Consumer,
+			MinIdle:  minIdle,
+			Start:    start,
+			Count:    p.config.BatchSize,
+		}).Result()
+
+		if err != nil {
+			if p.ctx.Err() == nil {
+				log.Printf("Failed to claim abandoned events on %s: %v", stream, err)
+			}
+			return
+		}
+
+		for _, message := range messages {
+			p.handleMessage(stream, message, p.deliveryCount(stream, message.ID))
+		}
+
+		if next == "" || next == "0-0" {
+			return
+		}
+		start = next
+	}
+}
+
+// deliveryCount returns how many times a claimed entry has been delivered,
+// including the claim itself.
+func (p *RedisStreamsPublisher) deliveryCount(stream, id string) int {
+	pending, err := p.client.XPendingExt(p.ctx, &redis.XPendingExtArgs{
+		Stream: stream,
+		Group:  p.config.Group,
+		Start:  id,
+		End:    id,
+		Count:  1,
+	}).Result()
+
+	if err != nil || len(pending) == 0 {
+		if err != nil && p.ctx.Err() == nil {
+			log.Printf("Failed to read delivery count of event %s on %s: %v", id, stream, err)
+		}
+		return 1
+	}
+
+	return int(pending[0].RetryCount)
+}
+
+// removeIdleConsumers deletes other consumers that have not read for
+// ConsumerIdle. Consumers with pending entries are kept until takeOver has
+// moved them, since deleting a consumer discards its pending entries.
+func (p *RedisStreamsPublisher) removeIdleConsumers(stream string) {
+	consumers, err := p.client.XInfoConsumers(p.ctx, stream, p.config.Group).Result()
+	if err != nil {
+		if p.ctx.Err() == nil {
+			log.Printf("Failed to list consumers on %s: %v", stream, err)
+		}
+		return
+	}
+
+	for _, consumer := range consumers {
+		if consumer.Name == p.config.Consumer || consumer.Pending > 0 || consumer.Idle < p.config.ConsumerIdle {
+			continue
+		}
+
+		if err := p.client.XGroupDelConsumer(p.ctx, stream, p.config.Group, consumer.Name).Err(); err != nil {
+			if p.ctx.Err() == nil {
+				log.Printf("Failed to remove idle consumer %s on %s: %v", consumer.Name, stream, err)
+			}
+			continue
+		}
+
+		log.Printf("Removed idle consumer %s from group %s on %s", consumer.Name, p.config.Group, stream)
+	}
+}
+
+// handleMessage runs the handlers for one stream entry. delivery is how many
+// times the entry has been delivered, including this one. Failed entries
+// stay pending so reclaim retries them, until the retry policy is exhausted
+// and they are dead-lettered.
+func (p *RedisStreamsPublisher) handleMessage(stream string, message redis.XMessage, delivery int) {
+	if _, busy := p.inFlight.LoadOrStore(message.ID, struct{}{}); busy {
+		return
+	}
+	defer p.inFlight.Delete(message.ID)
+
+	p.mu.RLock()
+	deadLetters := p.deadLetters
+	p.mu.RUnlock()
+
+	event, payload, err := decodeStreamMessage(message)
+	if err != nil {
+		// The entry can never be handled, so it is dead-lettered instead of
+		// being redelivered forever.
+		if event.Type == "" {
+			event.Type = domain.EventType(stream)
+		}
+
+		deadLetter(context.Background(), deadLetters, event, payload, delivery, err)
+		p.ack(stream, message.ID)
+		return
+	}
+
+	event.Context = p.ctx
+
+	p.mu.RLock()
+	handlers := p.handlers[event.Type]
+	policy := p.policies.For(event.Type)
+	p.mu.RUnlock()
+
+	var errs []error
+	for _, handler := range handlers {
+		if err := handler(event.Context, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		p.ack(stream, message.ID)
+		return
+	}
+
+	err = errors.Join(errs...)
+	log.Printf("Error handling event %s %s (attempt %d/%d): %v", event.Type, message.ID, delivery, policy.MaxAttempts, err)
+
+	if policy.Exhausted(delivery) || !retryable(err) {
+		deadLetter(context.Background(), deadLetters, event, payload, delivery, err)
+		p.ack(stream, message.ID)
+	}
+}
+
+func (p *RedisStreamsPublisher) ack(stream, id string) {
+	if err := p.client.XAck(context.Background(), stream, p.config.Group, id).Err(); err != nil {
+		log.Printf("Failed to acknowledge event %s on %s: %v", id, stream, err)
+	}
+}
+
+// sleep waits for d and reports whether the publisher is still running.
+func (p *RedisStreamsPublisher) sleep(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-p.ctx.Done():
+		return false
+	}
+}
+
+func (p *RedisStreamsPublisher) streamKey(eventType domain.EventType) string {
+	return fmt.Sprintf("%s:events:%s", p.config.Prefix, eventType)
+}
+
+func (p *RedisStreamsPublisher) Close() {
+	p.cancel()
+	p.wg.Wait()
+
+	if err := p.client.Close(); err != nil {
+		log.Printf("Failed to close Redis client: %v", err)
+	}
+
+	log.Println("Redis Streams publisher closed")
+}
+
+func encodeStreamMessage(event domain.Event) (map[string]interface{}, error) {
+	payload, err := EncodePayload(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		streamFieldType:    string(event.Type),
+		streamFieldPayload: string(payload),
+	}, nil
+}
+
+// decodeStreamMessage restores the event from a stream entry. The raw
+// payload is returned even on error so it can be dead-lettered.
+func decodeStreamMessage(message redis.XMessage) (domain.Event, []byte, error) {
+	eventType, _ := message.Values[streamFieldType].(string)
+	rawPayload, _ := message.Values[streamFieldPayload].(string)
+	payload := []byte(rawPayload)
+
+	if eventType == "" {
+		return domain.Event{}, payload, fmt.Errorf("stream entry %s has no event type", message.ID)
+	}
+
+	decoded, err := DecodePayload(domain.EventType(eventType), payload)
+	if err != nil {
+		return domain.Event{Type: domain.EventType(eventType)}, payload, err
+	}
+
+	return domain.Event{Type: domain.EventType(eventType), Payload: decoded}, payload, nil
+}
+
+func isBusyGroup(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP")
+}
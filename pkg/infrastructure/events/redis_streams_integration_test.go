@@ -0,0 +1,121 @@
+//go:build integration
+
+package events
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+)
+
+// Run with a disposable Redis:
+//
+//	TEST_REDIS_ADDRESS=localhost:6379 go test -tags integration ./pkg/infrastructure/events/...
+func newTestRedisPublisher(t *testing.T) *RedisStreamsPublisher {
+	t.Helper()
+
+	address := os.Getenv("TEST_REDIS_ADDRESS")
+	if address == "" {
+		t.Skip("TEST_REDIS_ADDRESS is not set")
+	}
+
+	publisher, err := NewRedisStreamsPublisher(address, "", 0, RedisStreamsConfig{
+		Prefix:          "test-" + uuid.NewString(),
+		Block:           100 * time.Millisecond,
+		ReclaimInterval: 100 * time.Millisecond,
+	})
+	require.NoError(t, err)
+
+	return publisher
+}
+
+// closeTestRedisPublisher stops the consumers before deleting the stream, so
+// they do not read from a stream that is gone.
+func closeTestRedisPublisher(publisher *RedisStreamsPublisher, stream string) {
+	publisher.cancel()
+	publisher.wg.Wait()
+	publisher.client.Del(context.Background(), stream)
+	publisher.Close()
+}
+
+func TestOutboxRelay_DispatchesThroughRedisStream(t *testing.T) {
+	publisher := newTestRedisPublisher(t)
+
+	handled := make(chan *entity.Subscription, 1)
+	publisher.Register(domain.UserSubscribed, func(ctx context.Context, event domain.Event) error {
+		handled <- event.Payload.(*entity.Subscription)
+		return nil
+	})
+	publisher.UseRetries(nil, &fakeDeadLetterRepository{})
+	publisher.Start()
+
+	stream := publisher.streamKey(domain.UserSubscribed)
+	defer closeTestRedisPublisher(publisher, stream)
+
+	subscription, err := entity.NewSubscription("stream@example.com", "Kyiv", entity.FrequencyDaily)
+	require.NoError(t, err)
+	data, err := EncodePayload(domain.Event{Type: domain.UserSubscribed, Payload: subscription})
+	require.NoError(t, err)
+	message := domain.NewOutboxMessage(domain.UserSubscribed, data)
+
+	repo := &fakeOutboxRepository{pending: []domain.OutboxMessage{*message}}
+	relay := NewOutboxRelay(repo, &fakeDeadLetterRepository{}, publisher, nil, time.Second, 10)
+
+	published, err := relay.RelayPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+	require.Equal(t, []uuid.UUID{message.ID}, repo.published)
+
+	length, err := publisher.client.XLen(context.Background(), stream).Result()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), length, "the event was added to the stream")
+
+	select {
+	case received := <-handled:
+		require.Equal(t, subscription.ID, received.ID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the handler did not receive the event from the stream")
+	}
+
+	require.Eventually(t, func() bool {
+		pending, err := publisher.client.XPending(context.Background(), stream, publisher.config.Group).Result()
+		return err == nil && pending.Count == 0
+	}, 5*time.Second, 50*time.Millisecond, "the entry was acknowledged")
+}
+
+func TestRedisStreamsPublisher_RetriesThenDeadLetters(t *testing.T) {
+	publisher := newTestRedisPublisher(t)
+
+	var attempts atomic.Int32
+	publisher.Register(domain.UserSubscribed, func(ctx context.Context, event domain.Event) error {
+		attempts.Add(1)
+		return errors.New("smtp unavailable")
+	})
+	deadLetters := &fakeDeadLetterRepository{}
+	publisher.UseRetries(RetryPolicies{domain.UserSubscribed: {MaxAttempts: 2, InitialBackoff: 10 * time.Millisecond}}, deadLetters)
+	publisher.Start()
+
+	stream := publisher.streamKey(domain.UserSubscribed)
+	defer closeTestRedisPublisher(publisher, stream)
+
+	subscription, err := entity.NewSubscription("stream@example.com", "Kyiv", entity.FrequencyDaily)
+	require.NoError(t, err)
+	require.NoError(t, publisher.Dispatch(context.Background(), domain.Event{Type: domain.UserSubscribed, Payload: subscription}))
+
+	require.Eventually(t, func() bool { return len(deadLetters.Saved()) == 1 }, 5*time.Second, 50*time.Millisecond)
+	require.Equal(t, int32(2), attempts.Load())
+	require.Equal(t, 2, deadLetters.Saved()[0].Attempts)
+
+	pending, err := publisher.client.XPending(context.Background(), stream, publisher.config.Group).Result()
+	require.NoError(t, err)
+	require.Zero(t, pending.Count)
+}
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	value_objects "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+)
+
+func TestStreamMessage_RoundTrip(t *testing.T) {
+	weather := value_objects.WeatherEvent{
+		City:        "Kyiv",
+		Temperature: -3,
+		Forecast:    []value_objects.ForecastDay{{Date: "2025-01-01", MaxTemperature: 1}},
+		Email:       "test@example.com",
+	}
+
+	values, err := encodeStreamMessage(domain.Event{Type: domain.WeatherEvent, Payload: weather})
+	require.NoError(t, err)
+
+	event, payload, err := decodeStreamMessage(redis.XMessage{ID: "1-0", Values: values})
+	require.NoError(t, err)
+	require.Equal(t, domain.WeatherEvent, event.Type)
+	require.Equal(t, weather, event.Payload)
+	require.Equal(t, values[streamFieldPayload], string(payload))
+}
+
+func TestDecodeStreamMessage_Invalid(t *testing.T) {
+	_, _, err := decodeStreamMessage(redis.XMessage{ID: "1-0", Values: map[string]interface{}{streamFieldPayload: "{}"}})
+	require.Error(t, err)
+
+	event, payload, err := decodeStreamMessage(redis.XMessage{ID: "2-0", Values: map[string]interface{}{
+		streamFieldType:    string(domain.UserSubscribed),
+		streamFieldPayload: "not json",
+	}})
+	require.Error(t, err)
+	require.Equal(t, domain.UserSubscribed, event.Type)
+	require.Equal(t, []byte("not json"), payload)
+}
+
+func TestIsBusyGroup(t *testing.T) {
+	require.True(t, isBusyGroup(errors.New("BUSYGROUP Consumer Group name already exists")))
+	require.False(t, isBusyGroup(errors.New("NOGROUP No such key")))
+	require.False(t, isBusyGroup(nil))
+}
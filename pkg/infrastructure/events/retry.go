@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
+)
+
+// RetryPolicies holds per-event retry policies; event types without an
+// entry use domain.DefaultRetryPolicy.
+type RetryPolicies map[domain.EventType]domain.RetryPolicy
+
+func (p RetryPolicies) For(eventType domain.EventType) domain.RetryPolicy {
+	if policy, ok := p[eventType]; ok {
+		return policy
+	}
+
+	return domain.DefaultRetryPolicy
+}
+
+// retryable reports whether err may go away on a later attempt. Email
+// failures classified as permanent will not.
+func retryable(err error) bool {
+	return !errors.Is(err, domain.ErrEmailPermanent)
+}
+
+// deadLetter stores event in the dead-letter store; a failure here is only
+// logged since there is nowhere left to put the event.
+func deadLetter(ctx context.Context, store domain_repository.DeadLetterRepository, event domain.Event, payload []byte, attempts int, cause error) {
+	if store == nil {
+		log.Printf("Dropping event %s after %d attempts: %v", event.Type, attempts, cause)
+		return
+	}
+
+	if payload == nil {
+		encoded, err := EncodePayload(event)
+		if err != nil {
+			log.Printf("Dropping event %s after %d attempts, payload cannot be encoded: %v", event.Type, attempts, err)
+			return
+		}
+		payload = encoded
+	}
+
+	// Keep malformed payloads as a JSON string so they can still be
+	// inspected.
+	if !json.Valid(payload) {
+		payload, _ = json.Marshal(string(payload))
+	}
+
+	if err := store.Save(ctx, domain.NewDeadLetter(event.Type, payload, cause.Error(), attempts)); err != nil {
+		log.Printf("Failed to dead-letter event %s: %v", event.Type, err)
+		return
+	}
+
+	log.Printf("Event %s dead-lettered after %d attempts: %v", event.Type, attempts, cause)
+}
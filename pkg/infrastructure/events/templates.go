@@ -0,0 +1,18 @@
+package events
+
+import (
+	"fmt"
+	"html/template"
+)
+
+// emailTemplate returns the email template called name from the set parsed
+// at startup; parsed templates are safe for concurrent execution.
+func (h *Handler) emailTemplate(name string) (*template.Template, error) {
+	if h.Templates != nil {
+		if tmpl := h.Templates.Lookup(name); tmpl != nil {
+			return tmpl, nil
+		}
+	}
+
+	return nil, fmt.Errorf("email template %s not found", name)
+}
@@ -0,0 +1,308 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
+	value_objects "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+)
+
+const (
+	WebhookEventType = "weather.update"
+
+	IDHeader        = "X-Webhook-ID"
+	TimestampHeader = "X-Webhook-Timestamp"
+	// SignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of
+	// the timestamp header, a dot and the request body, keyed with the
+	// signing secret.
+	SignatureHeader = "X-Webhook-Signature"
+
+	defaultWebhookTimeout      = 10 * time.Second
+	defaultWebhookMaxAttempts  = 3
+	defaultWebhookRetryDelay   = time.Second
+	defaultWebhookDisableAfter = 5
+	// maxErrorBodySize caps how much of a failed response ends up in the
+	// endpoint's last error.
+	maxErrorBodySize = 512
+)
+
+// errBlockedAddress is returned when a webhook host resolves to an address
+// that is not publicly routable, such as loopback, a private network or the
+// cloud metadata service.
+var errBlockedAddress = errors.New("webhook address is not publicly routable")
+
+// blockedPrefixes are special-purpose ranges that netip does not already
+// classify as loopback, private, link-local or multicast.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+type WebhookConfig struct {
+	Secret string
+	// Timeout bounds each attempt, including reading the response.
+	Timeout time.Duration
+	// MaxAttempts is how many times one delivery is tried before it counts
+	// as a failure of the endpoint.
+	MaxAttempts int
+	// RetryDelay is the wait before the first retry; it doubles after each.
+	RetryDelay time.Duration
+	// DisableAfter is how many failed deliveries in a row disable the
+	// endpoint.
+	DisableAfter int
+}
+
+// WebhookChannel POSTs weather updates as signed JSON to subscriber
+// webhooks and tracks the health of every endpoint it delivers to.
+type WebhookChannel struct {
+	config    WebhookConfig
+	client    *http.Client
+	endpoints domain_repository.WebhookEndpointRepository
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func NewWebhookChannel(config WebhookConfig, endpoints domain_repository.WebhookEndpointRepository) *WebhookChannel {
+	if config.Timeout <= 0 {
+		config.Timeout = defaultWebhookTimeout
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultWebhookMaxAttempts
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = defaultWebhookRetryDelay
+	}
+	if config.DisableAfter <= 0 {
+		config.DisableAfter = defaultWebhookDisableAfter
+	}
+
+	return &WebhookChannel{
+		config:    config,
+		client:    newWebhookClient(config.Timeout, false),
+		endpoints: endpoints,
+		now:       time.Now,
+		sleep:     sleepContext,
+	}
+}
+
+// newWebhookClient returns a client that only connects to public addresses
+// unless allowPrivate is set. The check runs on the address being dialed,
+// after DNS resolution, so a hostname cannot be pointed at an internal
+// service.
+func newWebhookClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = rejectInternalAddress
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would be dialed in place of the webhook host and bypass the
+	// address check.
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		// A redirect is treated as a failure rather than followed, so a
+		// delivery only ever reaches the registered URL.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func rejectInternalAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %s", errBlockedAddress, host)
+	}
+	ip = ip.Unmap()
+
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return fmt.Errorf("%w: %s", errBlockedAddress, ip)
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return fmt.Errorf("%w: %s", errBlockedAddress, ip)
+		}
+	}
+
+	return nil
+}
+
+type webhookPayload struct {
+	ID        string         `json:"id"`
+	Type      string         `json:"type"`
+	CreatedAt time.Time      `json:"created_at"`
+	Data      webhookWeather `json:"data"`
+}
+
+type webhookWeather struct {
+	City             string                      `json:"city"`
+	Country          string                      `json:"country,omitempty"`
+	Temperature      float64                     `json:"temperature"`
+	Humidity         float64                     `json:"humidity"`
+	Description      string                      `json:"description"`
+	Forecast         []value_objects.ForecastDay `json:"forecast,omitempty"`
+	Email            string                      `json:"email"`
+	UnsubscribeToken string                      `json:"unsubscribe_token,omitempty"`
+}
+
+// Deliver sends event to url, retrying timeouts, network errors, 408, 429
+// and 5xx responses. Other 4xx responses and hosts that resolve to internal
+// addresses are not retried. A delivery that
+// still fails is counted against the endpoint, and disabled endpoints are
+// not called at all.
+func (c *WebhookChannel) Deliver(ctx context.Context, url string, event value_objects.WeatherEvent) error {
+	endpoint, err := c.endpoints.Find(ctx, url)
+	if err != nil && !errors.Is(err, domain.ErrWebhookEndpointNotFound) {
+		return fmt.Errorf("failed to load webhook endpoint: %w", err)
+	}
+	if endpoint != nil && endpoint.Disabled() {
+		return fmt.Errorf("%w: %s", domain.ErrWebhookDisabled, url)
+	}
+
+	id := uuid.New().String()
+	body, err := json.Marshal(webhookPayload{
+		ID:        id,
+		Type:      WebhookEventType,
+		CreatedAt: c.now().UTC(),
+		Data: webhookWeather{
+			City:             event.City,
+			Country:          event.Country,
+			Temperature:      event.Temperature,
+			Humidity:         event.Humidity,
+			Description:      event.Description,
+			Forecast:         event.Forecast,
+			Email:            event.Email,
+			UnsubscribeToken: event.UnsubscribeToken,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	delay := c.config.RetryDelay
+	for attempt := 1; ; attempt++ {
+		retryable, err := c.post(ctx, url, id, body)
+		if err == nil {
+			if err := c.endpoints.RecordSuccess(ctx, url, c.now().UTC()); err != nil {
+				log.Printf("Failed to record webhook success for %s: %v", url, err)
+			}
+			return nil
+		}
+
+		if !retryable || attempt >= c.config.MaxAttempts || ctx.Err() != nil {
+			return c.recordFailure(ctx, url, err)
+		}
+
+		if err := c.sleep(ctx, delay); err != nil {
+			return c.recordFailure(ctx, url, err)
+		}
+		delay *= 2
+	}
+}
+
+// post makes one delivery attempt and reports whether a failure is worth
+// retrying.
+func (c *WebhookChannel) post(ctx context.Context, url, id string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(c.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "weather-subscriber-webhooks")
+	req.Header.Set(IDHeader, id)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(c.config.Secret, timestamp, body))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return !errors.Is(err, errBlockedAddress), fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		io.Copy(io.Discard, resp.Body)
+		return false, nil
+	}
+
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	err = fmt.Errorf("webhook returned HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+
+	retryable := resp.StatusCode >= 500 ||
+		resp.StatusCode == http.StatusRequestTimeout ||
+		resp.StatusCode == http.StatusTooManyRequests
+
+	return retryable, err
+}
+
+func (c *WebhookChannel) recordFailure(ctx context.Context, url string, cause error) error {
+	// The delivery context may be what failed; the counter must still be
+	// written.
+	endpoint, err := c.endpoints.RecordFailure(context.WithoutCancel(ctx), url, cause.Error(), c.now().UTC(), c.config.DisableAfter)
+	if err != nil {
+		log.Printf("Failed to record webhook failure for %s: %v", url, err)
+		return cause
+	}
+
+	if endpoint.Disabled() {
+		log.Printf("Disabled webhook %s after %d consecutive failures", url, endpoint.ConsecutiveFailures)
+	}
+
+	return cause
+}
+
+// Sign returns the signature header value for a payload sent at timestamp.
+// Receivers recompute it with the shared secret and compare in constant
+// time.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
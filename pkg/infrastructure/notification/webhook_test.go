@@ -0,0 +1,282 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	value_objects "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+)
+
+// memoryEndpoints mirrors GormWebhookEndpointRepository in memory.
+type memoryEndpoints struct {
+	mu        sync.Mutex
+	endpoints map[string]*domain.WebhookEndpoint
+}
+
+func newMemoryEndpoints() *memoryEndpoints {
+	return &memoryEndpoints{endpoints: map[string]*domain.WebhookEndpoint{}}
+}
+
+func (m *memoryEndpoints) Find(_ context.Context, url string) (*domain.WebhookEndpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	endpoint, ok := m.endpoints[url]
+	if !ok {
+		return nil, domain.ErrWebhookEndpointNotFound
+	}
+	copied := *endpoint
+	return &copied, nil
+}
+
+func (m *memoryEndpoints) List(context.Context) ([]domain.WebhookEndpoint, error) {
+	return nil, nil
+}
+
+func (m *memoryEndpoints) endpoint(url string) *domain.WebhookEndpoint {
+	if _, ok := m.endpoints[url]; !ok {
+		m.endpoints[url] = &domain.WebhookEndpoint{URL: url}
+	}
+	return m.endpoints[url]
+}
+
+func (m *memoryEndpoints) RecordSuccess(_ context.Context, url string, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	endpoint := m.endpoint(url)
+	endpoint.ConsecutiveFailures = 0
+	endpoint.LastSuccessAt = &at
+	return nil
+}
+
+func (m *memoryEndpoints) RecordFailure(_ context.Context, url, reason string, at time.Time, disableAfter int) (*domain.WebhookEndpoint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	endpoint := m.endpoint(url)
+	endpoint.ConsecutiveFailures++
+	endpoint.TotalFailures++
+	endpoint.LastError = reason
+	endpoint.LastFailureAt = &at
+	if endpoint.DisabledAt == nil && endpoint.ConsecutiveFailures >= disableAfter {
+		endpoint.DisabledAt = &at
+	}
+	copied := *endpoint
+	return &copied, nil
+}
+
+func (m *memoryEndpoints) Enable(_ context.Context, url string) error {
+	return nil
+}
+
+func newTestChannel(config WebhookConfig, endpoints *memoryEndpoints) (*WebhookChannel, *[]time.Duration) {
+	channel := NewWebhookChannel(config, endpoints)
+	// httptest servers listen on loopback.
+	channel.client = newWebhookClient(channel.config.Timeout, true)
+
+	var delays []time.Duration
+	channel.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	return channel, &delays
+}
+
+var testEvent = value_objects.WeatherEvent{
+	City:        "Kyiv",
+	Temperature: 21.5,
+	Humidity:    40,
+	Description: "Sunny",
+	Forecast:    []value_objects.ForecastDay{{Date: "2026-10-19", MinTemperature: 12, MaxTemperature: 22, Description: "Cloudy"}},
+	Email:       "user@example.com",
+}
+
+func TestWebhookChannel_DeliversSignedPayload(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	endpoints := newMemoryEndpoints()
+	channel, _ := newTestChannel(WebhookConfig{Secret: "secret"}, endpoints)
+
+	require.NoError(t, channel.Deliver(context.Background(), server.URL, testEvent))
+
+	require.NotNil(t, received)
+	assert.Equal(t, http.MethodPost, received.Method)
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.NotEmpty(t, received.Header.Get(IDHeader))
+	timestamp := received.Header.Get(TimestampHeader)
+	assert.Equal(t, Sign("secret", timestamp, body), received.Header.Get(SignatureHeader))
+	assert.NotEqual(t, Sign("other", timestamp, body), received.Header.Get(SignatureHeader))
+
+	var payload map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, received.Header.Get(IDHeader), payload["id"])
+	assert.Equal(t, WebhookEventType, payload["type"])
+	data := payload["data"].(map[string]interface{})
+	assert.Equal(t, "Kyiv", data["city"])
+	assert.Equal(t, 21.5, data["temperature"])
+	assert.Equal(t, "user@example.com", data["email"])
+	assert.Len(t, data["forecast"], 1)
+	assert.NotContains(t, data, "country")
+
+	endpoint, err := endpoints.Find(context.Background(), server.URL)
+	require.NoError(t, err)
+	assert.NotNil(t, endpoint.LastSuccessAt)
+}
+
+func TestWebhookChannel_RetriesTransientFailures(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	endpoints := newMemoryEndpoints()
+	channel, delays := newTestChannel(WebhookConfig{Secret: "secret", MaxAttempts: 3, RetryDelay: time.Second}, endpoints)
+
+	require.NoError(t, channel.Deliver(context.Background(), server.URL, testEvent))
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *delays)
+
+	endpoint, err := endpoints.Find(context.Background(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, 0, endpoint.ConsecutiveFailures)
+}
+
+func TestWebhookChannel_DoesNotRetryClientErrors(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.Error(w, "unknown hook", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	endpoints := newMemoryEndpoints()
+	channel, _ := newTestChannel(WebhookConfig{Secret: "secret"}, endpoints)
+
+	err := channel.Deliver(context.Background(), server.URL, testEvent)
+	require.ErrorContains(t, err, "HTTP 404: unknown hook")
+	assert.Equal(t, 1, attempts)
+
+	endpoint, err := endpoints.Find(context.Background(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, 1, endpoint.ConsecutiveFailures)
+	assert.Contains(t, endpoint.LastError, "HTTP 404")
+}
+
+func TestWebhookChannel_TimesOutSlowEndpoints(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	channel, _ := newTestChannel(WebhookConfig{Secret: "secret", Timeout: 50 * time.Millisecond, MaxAttempts: 2}, newMemoryEndpoints())
+
+	start := time.Now()
+	err := channel.Deliver(context.Background(), server.URL, testEvent)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestWebhookChannel_DisablesAfterRepeatedFailures(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	endpoints := newMemoryEndpoints()
+	channel, _ := newTestChannel(WebhookConfig{Secret: "secret", MaxAttempts: 2, DisableAfter: 2}, endpoints)
+
+	require.Error(t, channel.Deliver(context.Background(), server.URL, testEvent))
+	require.Error(t, channel.Deliver(context.Background(), server.URL, testEvent))
+	assert.Equal(t, 4, attempts)
+
+	endpoint, err := endpoints.Find(context.Background(), server.URL)
+	require.NoError(t, err)
+	assert.True(t, endpoint.Disabled())
+	assert.Equal(t, int64(2), endpoint.TotalFailures)
+
+	err = channel.Deliver(context.Background(), server.URL, testEvent)
+	assert.ErrorIs(t, err, domain.ErrWebhookDisabled)
+	assert.Equal(t, 4, attempts, "disabled endpoints are not called")
+}
+
+func TestWebhookChannel_DoesNotFollowRedirects(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("redirect was followed")
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+	defer server.Close()
+
+	channel, _ := newTestChannel(WebhookConfig{Secret: "secret"}, newMemoryEndpoints())
+
+	require.ErrorContains(t, channel.Deliver(context.Background(), server.URL, testEvent), "HTTP 307")
+}
+
+func TestWebhookChannel_RejectsInternalAddresses(t *testing.T) {
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+	}))
+	defer server.Close()
+
+	endpoints := newMemoryEndpoints()
+	channel := NewWebhookChannel(WebhookConfig{Secret: "secret"}, endpoints)
+	var delays []time.Duration
+	channel.sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+
+	err := channel.Deliver(context.Background(), server.URL, testEvent)
+	assert.ErrorIs(t, err, errBlockedAddress)
+	assert.Zero(t, attempts)
+	assert.Empty(t, delays, "blocked addresses are not retried")
+
+	endpoint, err := endpoints.Find(context.Background(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, 1, endpoint.ConsecutiveFailures)
+}
+
+func TestRejectInternalAddress(t *testing.T) {
+	blocked := []string{
+		"127.0.0.1:443", "10.1.2.3:443", "172.16.0.1:443", "192.168.1.1:443",
+		"169.254.169.254:80", "100.64.0.1:443", "0.0.0.0:443", "[::1]:443",
+		"[fe80::1]:443", "[fc00::1]:443", "[::ffff:127.0.0.1]:443", "[64:ff9b::a00:1]:443",
+	}
+	for _, address := range blocked {
+		assert.ErrorIs(t, rejectInternalAddress("tcp", address, nil), errBlockedAddress, address)
+	}
+
+	for _, address := range []string{"93.184.216.34:443", "[2606:4700::1111]:443"} {
+		assert.NoError(t, rejectInternalAddress("tcp", address, nil), address)
+	}
+}
@@ -0,0 +1,153 @@
+package tokens
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+// actionLinkPurpose is mixed into the signature so a token signed with the
+// same key for anything else is not accepted as an action link.
+const actionLinkPurpose = "action-link:"
+
+// actionLinkHeaderSize is the subscription ID followed by the issue and
+// expiry times; the action name takes the rest of the payload.
+const actionLinkHeaderSize = 16 + 8 + 8
+
+var keyIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+type signingKey struct {
+	id     string
+	secret []byte
+}
+
+// HMACActionLinks signs action links as
+// keyID "." base64url(payload) "." base64url(HMAC-SHA256(keyID, payload)).
+// The first key signs new links; every key verifies, so a new key can be put
+// in front and the old one dropped once the links it signed have expired.
+type HMACActionLinks struct {
+	keys map[string][]byte
+	// signingKey is the ID of the key new links are signed with.
+	signingKey string
+	now        func() time.Time
+}
+
+// NewHMACActionLinks parses keys in the form "id:secret,id:secret".
+func NewHMACActionLinks(keys string) (*HMACActionLinks, error) {
+	parsed, err := parseSigningKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	links := &HMACActionLinks{
+		keys:       make(map[string][]byte, len(parsed)),
+		signingKey: parsed[0].id,
+		now:        time.Now,
+	}
+	for _, key := range parsed {
+		links.keys[key.id] = key.secret
+	}
+
+	return links, nil
+}
+
+func parseSigningKeys(keys string) ([]signingKey, error) {
+	parsed := []signingKey{}
+	seen := map[string]bool{}
+
+	for _, entry := range strings.Split(keys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		id, secret, found := strings.Cut(entry, ":")
+		if !found || secret == "" {
+			return nil, fmt.Errorf("signing key %q must be in the form id:secret", id)
+		}
+		if !keyIDPattern.MatchString(id) {
+			return nil, fmt.Errorf("signing key ID %q may only contain letters, digits, '-' and '_'", id)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("duplicate signing key ID %q", id)
+		}
+
+		seen[id] = true
+		parsed = append(parsed, signingKey{id: id, secret: []byte(secret)})
+	}
+
+	if len(parsed) == 0 {
+		return nil, fmt.Errorf("at least one signing key is required")
+	}
+
+	return parsed, nil
+}
+
+func (l *HMACActionLinks) Sign(link domain.ActionLink) string {
+	payload := make([]byte, actionLinkHeaderSize, actionLinkHeaderSize+len(link.Action))
+	copy(payload, link.SubscriptionID[:])
+	binary.BigEndian.PutUint64(payload[16:], uint64(link.IssuedAt.Unix()))
+	binary.BigEndian.PutUint64(payload[24:], uint64(link.ExpiresAt.Unix()))
+	payload = append(payload, link.Action...)
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	signature := l.sign(l.keys[l.signingKey], l.signingKey, encoded)
+
+	return l.signingKey + "." + encoded + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func (l *HMACActionLinks) Verify(token string, action domain.LinkAction) (*domain.ActionLink, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, domain.ErrInvalidActionLink
+	}
+	keyID, encoded, signature := parts[0], parts[1], parts[2]
+
+	secret, ok := l.keys[keyID]
+	if !ok {
+		return nil, domain.ErrInvalidActionLink
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, l.sign(secret, keyID, encoded)) {
+		return nil, domain.ErrInvalidActionLink
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(payload) <= actionLinkHeaderSize {
+		return nil, domain.ErrInvalidActionLink
+	}
+
+	link := &domain.ActionLink{
+		SubscriptionID: uuid.UUID(payload[:16]),
+		IssuedAt:       time.Unix(int64(binary.BigEndian.Uint64(payload[16:])), 0).UTC(),
+		ExpiresAt:      time.Unix(int64(binary.BigEndian.Uint64(payload[24:])), 0).UTC(),
+		Action:         domain.LinkAction(payload[actionLinkHeaderSize:]),
+	}
+
+	if link.Action != action {
+		return nil, domain.ErrInvalidActionLink
+	}
+
+	// Unsubscribing must keep working from any email ever sent.
+	if link.Action != domain.UnsubscribeLinkAction && !l.now().Before(link.ExpiresAt) {
+		return nil, domain.ErrActionLinkExpired
+	}
+
+	return link, nil
+}
+
+func (l *HMACActionLinks) sign(secret []byte, keyID, payload string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(actionLinkPurpose + keyID + "." + payload))
+	return mac.Sum(nil)
+}
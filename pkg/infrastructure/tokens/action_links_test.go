@@ -0,0 +1,100 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+func TestHMACActionLinks(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	links, err := NewHMACActionLinks("2025-06:new-secret, 2025-01:old-secret")
+	require.NoError(t, err)
+	links.now = func() time.Time { return now }
+
+	link := domain.ActionLink{
+		SubscriptionID: uuid.New(),
+		Action:         domain.UnsubscribeLinkAction,
+		IssuedAt:       now,
+		ExpiresAt:      now.Add(time.Hour),
+	}
+
+	token := links.Sign(link)
+	assert.True(t, strings.HasPrefix(token, "2025-06."))
+
+	verified, err := links.Verify(token, domain.UnsubscribeLinkAction)
+	require.NoError(t, err)
+	assert.Equal(t, link, *verified)
+
+	t.Run("rejects links for another action", func(t *testing.T) {
+		_, err := links.Verify(token, domain.PauseLinkAction)
+		assert.ErrorIs(t, err, domain.ErrInvalidActionLink)
+	})
+
+	t.Run("rejects tampered links", func(t *testing.T) {
+		other := links.Sign(domain.ActionLink{
+			SubscriptionID: uuid.New(),
+			Action:         domain.UnsubscribeLinkAction,
+			IssuedAt:       now,
+			ExpiresAt:      now.Add(time.Hour),
+		})
+
+		parts := strings.Split(token, ".")
+		otherParts := strings.Split(other, ".")
+		_, err := links.Verify(parts[0]+"."+otherParts[1]+"."+parts[2], domain.UnsubscribeLinkAction)
+		assert.ErrorIs(t, err, domain.ErrInvalidActionLink)
+
+		_, err = links.Verify("2025-01."+parts[1]+"."+parts[2], domain.UnsubscribeLinkAction)
+		assert.ErrorIs(t, err, domain.ErrInvalidActionLink)
+
+		_, err = links.Verify(uuid.NewString(), domain.UnsubscribeLinkAction)
+		assert.ErrorIs(t, err, domain.ErrInvalidActionLink)
+	})
+
+	t.Run("verifies links signed with a retired signing key", func(t *testing.T) {
+		previous, err := NewHMACActionLinks("2025-01:old-secret")
+		require.NoError(t, err)
+
+		verified, err := links.Verify(previous.Sign(link), domain.UnsubscribeLinkAction)
+		require.NoError(t, err)
+		assert.Equal(t, link.SubscriptionID, verified.SubscriptionID)
+	})
+
+	t.Run("rejects links signed with an unknown key", func(t *testing.T) {
+		removed, err := NewHMACActionLinks("2024-01:removed-secret")
+		require.NoError(t, err)
+
+		_, err = links.Verify(removed.Sign(link), domain.UnsubscribeLinkAction)
+		assert.ErrorIs(t, err, domain.ErrInvalidActionLink)
+	})
+
+	t.Run("rejects expired links", func(t *testing.T) {
+		pause := link
+		pause.Action = domain.PauseLinkAction
+		pauseToken := links.Sign(pause)
+
+		now = now.Add(time.Hour)
+		_, err := links.Verify(pauseToken, domain.PauseLinkAction)
+		assert.ErrorIs(t, err, domain.ErrActionLinkExpired)
+	})
+
+	t.Run("accepts unsubscribe links past their expiry", func(t *testing.T) {
+		now = link.ExpiresAt.Add(365 * 24 * time.Hour)
+		verified, err := links.Verify(token, domain.UnsubscribeLinkAction)
+		require.NoError(t, err)
+		assert.Equal(t, link.SubscriptionID, verified.SubscriptionID)
+	})
+}
+
+func TestNewHMACActionLinksRejectsInvalidKeys(t *testing.T) {
+	for _, keys := range []string{"", "no-secret", "k1:", "bad.id:secret", "k1:a,k1:b"} {
+		_, err := NewHMACActionLinks(keys)
+		assert.Error(t, err, keys)
+	}
+}
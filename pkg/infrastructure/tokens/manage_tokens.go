@@ -0,0 +1,84 @@
+package tokens
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+// manageTokenPurpose is mixed into the signature so a token signed with the
+// same secret for anything else is not accepted as a manage token.
+const manageTokenPurpose = "manage"
+
+type manageClaims struct {
+	Email     string `json:"e"`
+	ExpiresAt int64  `json:"x"`
+}
+
+// HMACManageTokens issues stateless tokens of the form
+// base64url(claims) "." base64url(HMAC-SHA256(claims)).
+type HMACManageTokens struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewHMACManageTokens(secret string, ttl time.Duration) *HMACManageTokens {
+	return &HMACManageTokens{
+		secret: []byte(secret),
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+func (t *HMACManageTokens) Issue(email string) (string, time.Time, error) {
+	expiresAt := t.now().Add(t.ttl).UTC().Truncate(time.Second)
+
+	claims, err := json.Marshal(manageClaims{Email: email, ExpiresAt: expiresAt.Unix()})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	payload := base64.RawURLEncoding.EncodeToString(claims)
+
+	return payload + "." + base64.RawURLEncoding.EncodeToString(t.sign(payload)), expiresAt, nil
+}
+
+func (t *HMACManageTokens) Verify(token string) (string, error) {
+	payload, signature, found := strings.Cut(token, ".")
+	if !found {
+		return "", domain.ErrInvalidManageToken
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, t.sign(payload)) {
+		return "", domain.ErrInvalidManageToken
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", domain.ErrInvalidManageToken
+	}
+
+	var claims manageClaims
+	if err := json.Unmarshal(data, &claims); err != nil || claims.Email == "" {
+		return "", domain.ErrInvalidManageToken
+	}
+
+	if !t.now().Before(time.Unix(claims.ExpiresAt, 0)) {
+		return "", domain.ErrInvalidManageToken
+	}
+
+	return claims.Email, nil
+}
+
+func (t *HMACManageTokens) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(manageTokenPurpose + ":" + payload))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,50 @@
+package tokens
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+func TestHMACManageTokens(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tokens := NewHMACManageTokens("secret", 30*time.Minute)
+	tokens.now = func() time.Time { return now }
+
+	token, expiresAt, err := tokens.Issue("user@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(30*time.Minute), expiresAt)
+
+	email, err := tokens.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user@example.com", email)
+
+	t.Run("rejects tampered tokens", func(t *testing.T) {
+		other, _, err := tokens.Issue("attacker@example.com")
+		require.NoError(t, err)
+
+		otherPayload, _, _ := strings.Cut(other, ".")
+		_, signature, _ := strings.Cut(token, ".")
+		_, err = tokens.Verify(otherPayload + "." + signature)
+		assert.ErrorIs(t, err, domain.ErrInvalidManageToken)
+
+		_, err = tokens.Verify("garbage")
+		assert.ErrorIs(t, err, domain.ErrInvalidManageToken)
+	})
+
+	t.Run("rejects tokens signed with another secret", func(t *testing.T) {
+		_, err := NewHMACManageTokens("other", 30*time.Minute).Verify(token)
+		assert.ErrorIs(t, err, domain.ErrInvalidManageToken)
+	})
+
+	t.Run("rejects expired tokens", func(t *testing.T) {
+		now = now.Add(30 * time.Minute)
+		_, err := tokens.Verify(token)
+		assert.ErrorIs(t, err, domain.ErrInvalidManageToken)
+	})
+}
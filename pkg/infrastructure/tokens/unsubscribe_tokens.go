@@ -0,0 +1,36 @@
+package tokens
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+
+	"github.com/google/uuid"
+)
+
+// unsubscribeTokenPurpose keeps unsubscribe tokens distinct from anything
+// else derived from the same secret.
+const unsubscribeTokenPurpose = "unsubscribe:"
+
+// HMACUnsubscribeTokens derives unsubscribe tokens as the first 16 bytes of
+// HMAC-SHA256(subscription ID), formatted as a version 8 UUID. Without the
+// secret, the stored digests and IDs are not enough to forge a link.
+type HMACUnsubscribeTokens struct {
+	secret []byte
+}
+
+func NewHMACUnsubscribeTokens(secret string) *HMACUnsubscribeTokens {
+	return &HMACUnsubscribeTokens{secret: []byte(secret)}
+}
+
+func (t *HMACUnsubscribeTokens) Token(id uuid.UUID) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte(unsubscribeTokenPurpose))
+	mac.Write(id[:])
+
+	var token uuid.UUID
+	copy(token[:], mac.Sum(nil))
+	token[6] = (token[6] & 0x0f) | 0x80 // version 8
+	token[8] = (token[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return token.String()
+}
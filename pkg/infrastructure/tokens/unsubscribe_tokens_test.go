@@ -0,0 +1,24 @@
+package tokens
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHMACUnsubscribeTokens(t *testing.T) {
+	tokens := NewHMACUnsubscribeTokens("secret")
+	id := uuid.New()
+
+	token := tokens.Token(id)
+	assert.Equal(t, token, tokens.Token(id), "tokens are deterministic")
+	assert.NotEqual(t, token, tokens.Token(uuid.New()))
+	assert.NotEqual(t, token, NewHMACUnsubscribeTokens("other").Token(id))
+
+	parsed, err := uuid.Parse(token)
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Version(8), parsed.Version())
+	assert.Equal(t, uuid.RFC4122, parsed.Variant())
+}
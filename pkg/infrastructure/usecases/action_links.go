@@ -0,0 +1,34 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
+)
+
+// isLegacyToken reports whether token is one of the UUID tokens sent before
+// links were signed. Those are still looked up by their stored digest.
+func isLegacyToken(token string) bool {
+	_, err := uuid.Parse(token)
+	return err == nil
+}
+
+// findByLinkToken returns the subscription an unsubscribe or pause link
+// points to. Signed links are verified for action before the database is
+// queried.
+func findByLinkToken(ctx context.Context, repo domain_repository.SubscriptionRepository, links domain.ActionLinks, token string, action domain.LinkAction) (*domain_entity.Subscription, error) {
+	if isLegacyToken(token) {
+		return repo.FindByUnsubscribeToken(ctx, token)
+	}
+
+	link, err := links.Verify(token, action)
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.FindByID(ctx, link.SubscriptionID)
+}
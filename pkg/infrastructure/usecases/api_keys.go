@@ -0,0 +1,107 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
+	domain_usecases "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	"github.com/google/uuid"
+)
+
+type AuthenticateAPIKey struct {
+	repo domain_repository.APIKeyRepository
+}
+
+// Authenticate looks the key up by its hash. Unknown and revoked keys are
+// both reported as ErrInvalidAPIKey. LastUsedAt is only written once it is
+// older than APIKeyUsageInterval.
+func (uc *AuthenticateAPIKey) Authenticate(ctx context.Context, key string) (*domain.APIKey, error) {
+	if key == "" {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	apiKey, err := uc.repo.FindByHash(ctx, domain.HashAPIKey(key))
+	if err != nil {
+		if err == domain.ErrAPIKeyNotFound {
+			return nil, domain.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if apiKey.Revoked() {
+		return nil, domain.ErrInvalidAPIKey
+	}
+
+	now := time.Now().UTC()
+	if !apiKey.UsageStale(now) {
+		return apiKey, nil
+	}
+
+	if err := uc.repo.MarkUsed(ctx, apiKey.ID, now); err != nil {
+		log.Printf("Failed to record use of API key %s: %v", apiKey.ID, err)
+	} else {
+		apiKey.LastUsedAt = &now
+	}
+
+	return apiKey, nil
+}
+
+func NewAuthenticateAPIKeyUseCase(repo domain_repository.APIKeyRepository) domain_usecases.AuthenticateAPIKeyUseCase {
+	return &AuthenticateAPIKey{
+		repo: repo,
+	}
+}
+
+type CreateAPIKey struct {
+	repo domain_repository.APIKeyRepository
+}
+
+func (uc *CreateAPIKey) Create(ctx context.Context, name string) (*domain.APIKey, string, error) {
+	apiKey, key, err := domain.NewAPIKey(name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := uc.repo.Save(ctx, apiKey); err != nil {
+		return nil, "", err
+	}
+
+	return apiKey, key, nil
+}
+
+func NewCreateAPIKeyUseCase(repo domain_repository.APIKeyRepository) domain_usecases.CreateAPIKeyUseCase {
+	return &CreateAPIKey{
+		repo: repo,
+	}
+}
+
+type ListAPIKeys struct {
+	repo domain_repository.APIKeyRepository
+}
+
+func (uc *ListAPIKeys) List(ctx context.Context) ([]domain.APIKey, error) {
+	return uc.repo.List(ctx)
+}
+
+func NewListAPIKeysUseCase(repo domain_repository.APIKeyRepository) domain_usecases.ListAPIKeysUseCase {
+	return &ListAPIKeys{
+		repo: repo,
+	}
+}
+
+type RevokeAPIKey struct {
+	repo domain_repository.APIKeyRepository
+}
+
+func (uc *RevokeAPIKey) Revoke(ctx context.Context, id uuid.UUID) error {
+	return uc.repo.Revoke(ctx, id, time.Now().UTC())
+}
+
+func NewRevokeAPIKeyUseCase(repo domain_repository.APIKeyRepository) domain_usecases.RevokeAPIKeyUseCase {
+	return &RevokeAPIKey{
+		repo: repo,
+	}
+}
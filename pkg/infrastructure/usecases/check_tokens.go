@@ -2,23 +2,59 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"time"
 
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
 	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
 	domain_usecases "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
 )
 
 type CheckTokens struct {
-	repository domain_repository.SubscriptionRepository
+	repository      domain_repository.SubscriptionRepository
+	confirmationTTL time.Duration
+	links           domain.ActionLinks
 }
 
+// CheckConfirmationToken accepts a valid signed link without a database
+// lookup; whether it was superseded is only checked on confirmation.
 func (c *CheckTokens) CheckConfirmationToken(ctx context.Context, token string) (bool, error) {
-	return c.repository.IsComfirmationTokenExists(ctx, token)
+	if !isLegacyToken(token) {
+		return c.checkLink(token, domain.ConfirmLinkAction)
+	}
+
+	subscription, err := c.repository.FindByConfirmationToken(ctx, token)
+	if errors.Is(err, domain.ErrSubscriptionNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if !subscription.Confirmed && subscription.ConfirmationExpired(time.Now(), c.confirmationTTL) {
+		return false, domain_entity.ErrConfirmationExpired
+	}
+
+	return true, nil
 }
 
 func (c *CheckTokens) CheckUnsubscribeToken(ctx context.Context, token string) (bool, error) {
+	if !isLegacyToken(token) {
+		return c.checkLink(token, domain.UnsubscribeLinkAction)
+	}
+
 	return c.repository.IsUnsubscribeTokenExists(ctx, token)
 }
 
-func NewCheckTokens(repository domain_repository.SubscriptionRepository) domain_usecases.CheckTokensUseCase {
-	return &CheckTokens{repository: repository}
+func (c *CheckTokens) checkLink(token string, action domain.LinkAction) (bool, error) {
+	if _, err := c.links.Verify(token, action); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func NewCheckTokens(repository domain_repository.SubscriptionRepository, confirmationTTL time.Duration, links domain.ActionLinks) domain_usecases.CheckTokensUseCase {
+	return &CheckTokens{repository: repository, confirmationTTL: confirmationTTL, links: links}
 }
@@ -2,25 +2,36 @@ package usecases
 
 import (
 	"context"
+	"time"
 
 	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
 	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
 	domain_usecases "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
 )
 
 type ConfirmSubscription struct {
-	repo domain_repository.SubscriptionRepository
+	repo     domain_repository.SubscriptionRepository
+	tokenTTL time.Duration
+	links    domain.ActionLinks
 }
 
 func (uc *ConfirmSubscription) Confirm(ctx context.Context, token string) error {
-	subscription, err := uc.repo.IsComfirmationTokenExists(ctx, token)
+	if !isLegacyToken(token) {
+		return uc.confirmLink(ctx, token)
+	}
 
+	subscription, err := uc.repo.FindByConfirmationToken(ctx, token)
 	if err != nil {
 		return err
 	}
 
-	if !subscription {
-		return domain.ErrSubscriptionNotFound
+	if subscription.Confirmed {
+		return nil
+	}
+
+	if subscription.ConfirmationExpired(time.Now(), uc.tokenTTL) {
+		return domain_entity.ErrConfirmationExpired
 	}
 
 	if err := uc.repo.Confirm(ctx, token); err != nil {
@@ -30,8 +41,37 @@ func (uc *ConfirmSubscription) Confirm(ctx context.Context, token string) error
 	return nil
 }
 
-func NewConfirmSubscription(repo domain_repository.SubscriptionRepository) domain_usecases.ConfirmSubscriptionUseCase {
+// confirmLink confirms through a signed link. A link issued before the
+// latest confirmation email was superseded by it and counts as expired.
+func (uc *ConfirmSubscription) confirmLink(ctx context.Context, token string) error {
+	link, err := uc.links.Verify(token, domain.ConfirmLinkAction)
+	if err != nil {
+		return err
+	}
+
+	subscription, err := uc.repo.FindByID(ctx, link.SubscriptionID)
+	if err != nil {
+		return err
+	}
+
+	if subscription.Confirmed {
+		return nil
+	}
+
+	if link.IssuedAt.Before(subscription.ConfirmationSentAt.Truncate(time.Second)) {
+		return domain_entity.ErrConfirmationExpired
+	}
+
+	return uc.repo.ConfirmByID(ctx, subscription.ID)
+}
+
+// NewConfirmSubscription rejects confirmation tokens issued more than
+// tokenTTL ago; a non-positive tokenTTL disables the check. Signed links
+// carry their own expiry.
+func NewConfirmSubscription(repo domain_repository.SubscriptionRepository, tokenTTL time.Duration, links domain.ActionLinks) domain_usecases.ConfirmSubscriptionUseCase {
 	return &ConfirmSubscription{
-		repo: repo,
+		repo:     repo,
+		tokenTTL: tokenTTL,
+		links:    links,
 	}
 }
@@ -0,0 +1,49 @@
+package usecases
+
+import (
+	"context"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
+	domain_usecases "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	"github.com/google/uuid"
+)
+
+type ListDeadLetters struct {
+	repo domain_repository.DeadLetterRepository
+}
+
+func (uc *ListDeadLetters) List(ctx context.Context, limit, offset int, includeReplayed bool) ([]domain.DeadLetter, error) {
+	return uc.repo.List(ctx, limit, offset, includeReplayed)
+}
+
+func NewListDeadLettersUseCase(repo domain_repository.DeadLetterRepository) domain_usecases.ListDeadLettersUseCase {
+	return &ListDeadLetters{
+		repo: repo,
+	}
+}
+
+type ReplayDeadLetter struct {
+	repo domain_repository.DeadLetterRepository
+}
+
+// Replay puts the event back into the outbox, so it goes through the relay
+// and its retry policy again.
+func (uc *ReplayDeadLetter) Replay(ctx context.Context, id uuid.UUID) error {
+	deadLetter, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if deadLetter.ReplayedAt != nil {
+		return domain.ErrDeadLetterAlreadyReplayed
+	}
+
+	return uc.repo.Replay(ctx, id, domain.NewOutboxMessage(deadLetter.EventType, deadLetter.Payload))
+}
+
+func NewReplayDeadLetterUseCase(repo domain_repository.DeadLetterRepository) domain_usecases.ReplayDeadLetterUseCase {
+	return &ReplayDeadLetter{
+		repo: repo,
+	}
+}
@@ -0,0 +1,30 @@
+package usecases
+
+import (
+	"context"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_usecases "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+)
+
+type EmailPoolStats struct {
+	emailService domain.EmailService
+}
+
+// Stats reports the SMTP connection pool. Other transports hold no
+// connections and return ErrEmailPoolStatsUnavailable.
+func (uc *EmailPoolStats) Stats(ctx context.Context) (*domain.EmailPoolStats, error) {
+	pool, ok := uc.emailService.(domain.EmailPool)
+	if !ok {
+		return nil, domain.ErrEmailPoolStatsUnavailable
+	}
+
+	stats := pool.PoolStats()
+	return &stats, nil
+}
+
+func NewEmailPoolStatsUseCase(emailService domain.EmailService) domain_usecases.EmailPoolStatsUseCase {
+	return &EmailPoolStats{
+		emailService: emailService,
+	}
+}
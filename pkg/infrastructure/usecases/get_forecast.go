@@ -0,0 +1,37 @@
+package usecases
+
+import (
+	"context"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_usecases "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	value_object "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+	weather "github.com/danik-tro/weather-subscriber/pkg/external/weather"
+)
+
+type GetForecastUseCase struct {
+	weatherService weather.WeatherService
+}
+
+func (uc *GetForecastUseCase) GetForecast(ctx context.Context, city string, days int) (value_object.Forecast, error) {
+	if days < 1 || days > domain_usecases.MaxForecastDays {
+		return value_object.Forecast{}, domain.ErrBadRequest
+	}
+
+	forecast, err := uc.weatherService.GetForecast(ctx, city, days)
+	if err != nil {
+		return value_object.Forecast{}, err
+	}
+
+	if forecast == nil {
+		return value_object.Forecast{}, domain.ErrCityNotFound
+	}
+
+	return *forecast, nil
+}
+
+func NewGetForecastUseCase(weatherService weather.WeatherService) domain_usecases.GetForecastUseCase {
+	return &GetForecastUseCase{
+		weatherService: weatherService,
+	}
+}
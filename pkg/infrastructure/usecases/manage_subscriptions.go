@@ -0,0 +1,193 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
+	domain_usecases "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	value_objects "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+	weather "github.com/danik-tro/weather-subscriber/pkg/external/weather"
+	events "github.com/danik-tro/weather-subscriber/pkg/infrastructure/events"
+	"github.com/google/uuid"
+)
+
+type RequestManageLink struct {
+	repo   domain_repository.SubscriptionRepository
+	tokens domain.ManageTokens
+}
+
+func (uc *RequestManageLink) RequestLink(ctx context.Context, email string) error {
+	subscriptions, err := uc.repo.FindByEmail(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	token, expiresAt, err := uc.tokens.Issue(subscriptions[0].Email)
+	if err != nil {
+		return err
+	}
+
+	payload, err := events.EncodePayload(domain.Event{
+		Type: domain.ManageLinkRequested,
+		Payload: value_objects.ManageLink{
+			Email:     subscriptions[0].Email,
+			Token:     token,
+			ExpiresAt: expiresAt,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// The email is sent by the outbox relay, so it survives a restart. A
+	// throttled request looks like a sent one, as for unknown addresses.
+	message := domain.NewOutboxMessage(domain.ManageLinkRequested, payload)
+	err = uc.repo.RequestManageLinkWithOutbox(ctx, subscriptions[0].Email, time.Now(), message)
+	if errors.Is(err, domain_entity.ErrManageLinkRequestedTooSoon) {
+		return nil
+	}
+
+	return err
+}
+
+func NewRequestManageLinkUseCase(repo domain_repository.SubscriptionRepository, tokens domain.ManageTokens) domain_usecases.RequestManageLinkUseCase {
+	return &RequestManageLink{
+		repo:   repo,
+		tokens: tokens,
+	}
+}
+
+type ListManagedSubscriptions struct {
+	repo   domain_repository.SubscriptionRepository
+	tokens domain.ManageTokens
+}
+
+func (uc *ListManagedSubscriptions) List(ctx context.Context, token string) (string, []*domain_entity.Subscription, error) {
+	email, err := uc.tokens.Verify(token)
+	if err != nil {
+		return "", nil, err
+	}
+
+	subscriptions, err := uc.repo.FindByEmail(ctx, email)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return email, subscriptions, nil
+}
+
+func NewListManagedSubscriptionsUseCase(repo domain_repository.SubscriptionRepository, tokens domain.ManageTokens) domain_usecases.ListManagedSubscriptionsUseCase {
+	return &ListManagedSubscriptions{
+		repo:   repo,
+		tokens: tokens,
+	}
+}
+
+type UpdateManagedSubscription struct {
+	repo           domain_repository.SubscriptionRepository
+	tokens         domain.ManageTokens
+	weatherService weather.WeatherService
+}
+
+// Update applies changes to a subscription owned by the token's email. When
+// the city changes, the timezone is looked up again so digests keep going out
+// at the local send hour.
+func (uc *UpdateManagedSubscription) Update(ctx context.Context, token string, id uuid.UUID, changes domain_entity.SubscriptionChanges) error {
+	subscription, err := findManagedSubscription(ctx, uc.repo, uc.tokens, token, id)
+	if err != nil {
+		return err
+	}
+
+	cityChanged := changes.CityChanged(subscription)
+
+	if cityChanged {
+		weather, err := uc.weatherService.GetWeather(ctx, changes.City)
+		if err != nil {
+			return err
+		}
+
+		if weather == nil {
+			return domain.ErrCityNotFound
+		}
+
+		owned, err := uc.repo.FindByEmail(ctx, subscription.Email)
+		if err != nil {
+			return err
+		}
+
+		for _, other := range owned {
+			if other.ID != subscription.ID && strings.EqualFold(other.City, changes.City) {
+				return domain.ErrSubscriptionAlreadyExists
+			}
+		}
+	}
+
+	if err := changes.Apply(subscription); err != nil {
+		return err
+	}
+
+	if cityChanged {
+		subscription.Timezone = resolveTimezone(ctx, uc.weatherService, subscription.City)
+	}
+
+	return uc.repo.Save(ctx, subscription)
+}
+
+func NewUpdateManagedSubscriptionUseCase(repo domain_repository.SubscriptionRepository, tokens domain.ManageTokens, weatherService weather.WeatherService) domain_usecases.UpdateManagedSubscriptionUseCase {
+	return &UpdateManagedSubscription{
+		repo:           repo,
+		tokens:         tokens,
+		weatherService: weatherService,
+	}
+}
+
+type DeleteManagedSubscription struct {
+	repo   domain_repository.SubscriptionRepository
+	tokens domain.ManageTokens
+}
+
+func (uc *DeleteManagedSubscription) Delete(ctx context.Context, token string, id uuid.UUID) error {
+	subscription, err := findManagedSubscription(ctx, uc.repo, uc.tokens, token, id)
+	if err != nil {
+		return err
+	}
+
+	return uc.repo.Delete(ctx, subscription.ID)
+}
+
+func NewDeleteManagedSubscriptionUseCase(repo domain_repository.SubscriptionRepository, tokens domain.ManageTokens) domain_usecases.DeleteManagedSubscriptionUseCase {
+	return &DeleteManagedSubscription{
+		repo:   repo,
+		tokens: tokens,
+	}
+}
+
+// findManagedSubscription verifies the token and returns the subscription
+// if it belongs to the token's email. Subscriptions of other addresses are
+// reported as not found.
+func findManagedSubscription(ctx context.Context, repo domain_repository.SubscriptionRepository, tokens domain.ManageTokens, token string, id uuid.UUID) (*domain_entity.Subscription, error) {
+	email, err := tokens.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, err := repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(subscription.Email, email) {
+		return nil, domain.ErrSubscriptionNotFound
+	}
+
+	return subscription, nil
+}
@@ -0,0 +1,146 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
+	domain_usecases "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	events "github.com/danik-tro/weather-subscriber/pkg/infrastructure/events"
+)
+
+const resumeBatchSize = 100
+
+type GetPauseStatus struct {
+	repo  domain_repository.SubscriptionRepository
+	links domain.ActionLinks
+}
+
+func (uc *GetPauseStatus) Status(ctx context.Context, token string) (*domain_entity.Subscription, error) {
+	return findByLinkToken(ctx, uc.repo, uc.links, token, domain.PauseLinkAction)
+}
+
+func NewGetPauseStatusUseCase(repo domain_repository.SubscriptionRepository, links domain.ActionLinks) domain_usecases.GetPauseStatusUseCase {
+	return &GetPauseStatus{
+		repo:  repo,
+		links: links,
+	}
+}
+
+type PauseSubscription struct {
+	repo  domain_repository.SubscriptionRepository
+	links domain.ActionLinks
+}
+
+func (uc *PauseSubscription) Pause(ctx context.Context, token string, until *time.Time) (*domain_entity.Subscription, error) {
+	subscription, err := findByLinkToken(ctx, uc.repo, uc.links, token, domain.PauseLinkAction)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := subscription.Pause(time.Now(), until); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Save(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func NewPauseSubscriptionUseCase(repo domain_repository.SubscriptionRepository, links domain.ActionLinks) domain_usecases.PauseSubscriptionUseCase {
+	return &PauseSubscription{
+		repo:  repo,
+		links: links,
+	}
+}
+
+type ResumeSubscription struct {
+	repo  domain_repository.SubscriptionRepository
+	links domain.ActionLinks
+}
+
+func (uc *ResumeSubscription) Resume(ctx context.Context, token string) (*domain_entity.Subscription, error) {
+	subscription, err := findByLinkToken(ctx, uc.repo, uc.links, token, domain.PauseLinkAction)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := subscription.Resume(); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Save(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func NewResumeSubscriptionUseCase(repo domain_repository.SubscriptionRepository, links domain.ActionLinks) domain_usecases.ResumeSubscriptionUseCase {
+	return &ResumeSubscription{
+		repo:  repo,
+		links: links,
+	}
+}
+
+type ResumeExpiredPauses struct {
+	repo domain_repository.SubscriptionRepository
+}
+
+// ResumeExpired resumes expired pauses in batches. Each subscriber is told
+// through a SubscriptionResumed event stored in the outbox together with
+// the resume, so the notification is not lost if the process dies.
+func (uc *ResumeExpiredPauses) ResumeExpired(ctx context.Context) (int, error) {
+	resumed := 0
+
+	for {
+		now := time.Now()
+
+		subscriptions, err := uc.repo.FindExpiredPauses(ctx, now, resumeBatchSize)
+		if err != nil {
+			return resumed, err
+		}
+
+		batchResumed := 0
+		for _, subscription := range subscriptions {
+			if err := subscription.Resume(); err != nil {
+				return resumed, err
+			}
+
+			payload, err := events.EncodePayload(domain.Event{Type: domain.SubscriptionResumed, Payload: subscription})
+			if err != nil {
+				return resumed, err
+			}
+
+			if err := uc.repo.ResumeWithOutbox(ctx, subscription.ID, now, domain.NewOutboxMessage(domain.SubscriptionResumed, payload)); err != nil {
+				// Resumed or paused again since it was found.
+				if err == domain_entity.ErrSubscriptionNotPaused {
+					continue
+				}
+				return resumed, err
+			}
+
+			batchResumed++
+		}
+
+		resumed += batchResumed
+
+		if len(subscriptions) < resumeBatchSize || batchResumed == 0 {
+			if resumed > 0 {
+				log.Printf("Resumed %d paused subscriptions", resumed)
+			}
+			return resumed, nil
+		}
+	}
+}
+
+func NewResumeExpiredPausesUseCase(repo domain_repository.SubscriptionRepository) domain_usecases.ResumeExpiredPausesUseCase {
+	return &ResumeExpiredPauses{
+		repo: repo,
+	}
+}
@@ -0,0 +1,78 @@
+package usecases
+
+import (
+	"context"
+	"log"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
+	domain_usecases "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	events "github.com/danik-tro/weather-subscriber/pkg/infrastructure/events"
+)
+
+type ResendConfirmation struct {
+	repo         domain_repository.SubscriptionRepository
+	suppressions domain_repository.SuppressionRepository
+}
+
+func (uc *ResendConfirmation) Resend(ctx context.Context, email, city string) error {
+	suppressed, err := uc.suppressions.IsSuppressed(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if suppressed {
+		return domain.ErrEmailSuppressed
+	}
+
+	subscription, err := uc.repo.FindByEmailAndCity(ctx, email, city)
+	if err != nil {
+		return err
+	}
+
+	if err := subscription.RotateConfirmationToken(time.Now()); err != nil {
+		return err
+	}
+
+	payload, err := events.EncodePayload(domain.Event{Type: domain.UserSubscribed, Payload: subscription})
+	if err != nil {
+		return err
+	}
+
+	return uc.repo.SaveWithOutbox(ctx, subscription, domain.NewOutboxMessage(domain.UserSubscribed, payload))
+}
+
+func NewResendConfirmationUseCase(repo domain_repository.SubscriptionRepository, suppressions domain_repository.SuppressionRepository) domain_usecases.ResendConfirmationUseCase {
+	return &ResendConfirmation{
+		repo:         repo,
+		suppressions: suppressions,
+	}
+}
+
+type PurgeUnconfirmedSubscriptions struct {
+	repo      domain_repository.SubscriptionRepository
+	retention time.Duration
+}
+
+func (uc *PurgeUnconfirmedSubscriptions) Purge(ctx context.Context) (int64, error) {
+	purged, err := uc.repo.DeleteUnconfirmedBefore(ctx, time.Now().Add(-uc.retention))
+	if err != nil {
+		return 0, err
+	}
+
+	if purged > 0 {
+		log.Printf("Purged %d unconfirmed subscriptions", purged)
+	}
+
+	return purged, nil
+}
+
+// NewPurgeUnconfirmedSubscriptionsUseCase removes subscriptions that were not
+// confirmed within retention of their last confirmation email.
+func NewPurgeUnconfirmedSubscriptionsUseCase(repo domain_repository.SubscriptionRepository, retention time.Duration) domain_usecases.PurgeUnconfirmedSubscriptionsUseCase {
+	return &PurgeUnconfirmedSubscriptions{
+		repo:      repo,
+		retention: retention,
+	}
+}
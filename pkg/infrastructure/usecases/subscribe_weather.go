@@ -2,21 +2,95 @@ package usecases
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
 	domain_entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
 	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
 	domain_usecases "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
 	weather "github.com/danik-tro/weather-subscriber/pkg/external/weather"
+	events "github.com/danik-tro/weather-subscriber/pkg/infrastructure/events"
 )
 
 type SubscribeWeatherUseCase struct {
-	repo           domain_repository.SubscriptionRepository
-	publisher      domain.EventPublisher
-	weatherService weather.WeatherService
+	repo              domain_repository.SubscriptionRepository
+	weatherService    weather.WeatherService
+	unsubscribeTokens domain.UnsubscribeTokens
+	suppressions      domain_repository.SuppressionRepository
 }
 
-func (uc *SubscribeWeatherUseCase) Subscribe(ctx context.Context, email, city string, freq domain_entity.Frequency) error {
+func (uc *SubscribeWeatherUseCase) Subscribe(ctx context.Context, email, city string, freq domain_entity.Frequency, prefs domain_entity.DeliveryPreferences) error {
+	if err := prefs.Validate(); err != nil {
+		return err
+	}
+
+	return uc.subscribe(ctx, email, city, func() (*domain_entity.Subscription, error) {
+		sub, err := domain_entity.NewSubscription(email, city, freq)
+		if err != nil {
+			return nil, err
+		}
+
+		if prefs.Timezone == "" {
+			sub.Timezone = uc.resolveTimezone(ctx, city)
+		}
+
+		if err := sub.ApplyDeliveryPreferences(prefs); err != nil {
+			return nil, err
+		}
+
+		return sub, nil
+	})
+}
+
+func (uc *SubscribeWeatherUseCase) SubscribeToAlert(ctx context.Context, email, city string, rule domain_entity.AlertRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	return uc.subscribe(ctx, email, city, func() (*domain_entity.Subscription, error) {
+		sub, err := domain_entity.NewAlertSubscription(email, city, rule)
+		if err != nil {
+			return nil, err
+		}
+
+		sub.Timezone = uc.resolveTimezone(ctx, city)
+
+		return sub, nil
+	})
+}
+
+func (uc *SubscribeWeatherUseCase) resolveTimezone(ctx context.Context, city string) string {
+	return resolveTimezone(ctx, uc.weatherService, city)
+}
+
+// resolveTimezone looks up the city's IANA timezone through the weather
+// provider, falling back to UTC when it is unavailable.
+func resolveTimezone(ctx context.Context, weatherService weather.WeatherService, city string) string {
+	location, err := weatherService.GetLocation(ctx, city)
+	if err != nil || location == nil || location.Timezone == "" {
+		return domain_entity.DefaultTimezone
+	}
+
+	if _, err := time.LoadLocation(location.Timezone); err != nil {
+		return domain_entity.DefaultTimezone
+	}
+
+	return location.Timezone
+}
+
+func (uc *SubscribeWeatherUseCase) subscribe(ctx context.Context, email, city string, newSubscription func() (*domain_entity.Subscription, error)) error {
+	// The confirmation email would not be sent, so the subscription could
+	// never be confirmed.
+	suppressed, err := uc.suppressions.IsSuppressed(ctx, email)
+	if err != nil {
+		return err
+	}
+
+	if suppressed {
+		return domain.ErrEmailSuppressed
+	}
+
 	weather, err := uc.weatherService.GetWeather(ctx, city)
 	if err != nil {
 		return err
@@ -26,35 +100,38 @@ func (uc *SubscribeWeatherUseCase) Subscribe(ctx context.Context, email, city st
 		return domain.ErrCityNotFound
 	}
 
-	isSubscribed, err := uc.repo.IsSubscribed(ctx, email, city)
-	if err != nil {
+	existing, err := uc.repo.FindByEmailAndCity(ctx, email, city)
+	if err != nil && !errors.Is(err, domain.ErrSubscriptionNotFound) {
 		return err
 	}
 
-	if isSubscribed {
-		return domain.ErrSubscriptionAlreadyExists
+	if existing != nil {
+		if existing.Confirmed {
+			return domain.ErrSubscriptionAlreadyExists
+		}
+		return domain.ErrSubscriptionPendingConfirmation
 	}
 
-	sub, err := domain_entity.NewSubscription(email, city, freq)
+	sub, err := newSubscription()
 	if err != nil {
 		return err
 	}
+	sub.AssignUnsubscribeToken(uc.unsubscribeTokens.Token(sub.ID))
 
-	if err := uc.repo.Save(ctx, sub); err != nil {
+	payload, err := events.EncodePayload(domain.Event{Type: domain.UserSubscribed, Payload: sub})
+	if err != nil {
 		return err
 	}
 
-	uc.publisher.TriggerAsync(
-		domain.Event{
-			Type:    domain.UserSubscribed,
-			Payload: sub,
-			Context: ctx,
-		},
-	)
+	// The confirmation email is sent by the outbox relay once the
+	// subscription is committed.
+	if err := uc.repo.SaveWithOutbox(ctx, sub, domain.NewOutboxMessage(domain.UserSubscribed, payload)); err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func NewSubscribeWeatherUseCase(repo domain_repository.SubscriptionRepository, publisher domain.EventPublisher, weatherService weather.WeatherService) domain_usecases.SubscribeWeatherUseCase {
-	return &SubscribeWeatherUseCase{repo: repo, publisher: publisher, weatherService: weatherService}
+func NewSubscribeWeatherUseCase(repo domain_repository.SubscriptionRepository, weatherService weather.WeatherService, unsubscribeTokens domain.UnsubscribeTokens, suppressions domain_repository.SuppressionRepository) domain_usecases.SubscribeWeatherUseCase {
+	return &SubscribeWeatherUseCase{repo: repo, weatherService: weatherService, unsubscribeTokens: unsubscribeTokens, suppressions: suppressions}
 }
@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"context"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
+	domain_usecases "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	"github.com/google/uuid"
+)
+
+type ListSubscriptions struct {
+	repo domain_repository.SubscriptionRepository
+}
+
+func (uc *ListSubscriptions) List(ctx context.Context, filter domain.SubscriptionFilter) ([]*domain.Subscription, int64, error) {
+	subscriptions, err := uc.repo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, err := uc.repo.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return subscriptions, total, nil
+}
+
+func NewListSubscriptionsUseCase(repo domain_repository.SubscriptionRepository) domain_usecases.ListSubscriptionsUseCase {
+	return &ListSubscriptions{
+		repo: repo,
+	}
+}
+
+type GetSubscription struct {
+	repo domain_repository.SubscriptionRepository
+}
+
+func (uc *GetSubscription) Get(ctx context.Context, id uuid.UUID) (*domain.Subscription, error) {
+	return uc.repo.FindByID(ctx, id)
+}
+
+func NewGetSubscriptionUseCase(repo domain_repository.SubscriptionRepository) domain_usecases.GetSubscriptionUseCase {
+	return &GetSubscription{
+		repo: repo,
+	}
+}
+
+type DeleteSubscription struct {
+	repo domain_repository.SubscriptionRepository
+}
+
+func (uc *DeleteSubscription) Delete(ctx context.Context, id uuid.UUID) error {
+	return uc.repo.Delete(ctx, id)
+}
+
+func NewDeleteSubscriptionUseCase(repo domain_repository.SubscriptionRepository) domain_usecases.DeleteSubscriptionUseCase {
+	return &DeleteSubscription{
+		repo: repo,
+	}
+}
+
+type ForceConfirmSubscription struct {
+	repo domain_repository.SubscriptionRepository
+}
+
+// ForceConfirm confirms the subscription on behalf of the subscriber.
+// Confirming an already confirmed subscription keeps its confirmation time.
+func (uc *ForceConfirmSubscription) ForceConfirm(ctx context.Context, id uuid.UUID) (*domain.Subscription, error) {
+	subscription, err := uc.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.Confirmed {
+		return subscription, nil
+	}
+
+	if err := uc.repo.ConfirmByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return uc.repo.FindByID(ctx, id)
+}
+
+func NewForceConfirmSubscriptionUseCase(repo domain_repository.SubscriptionRepository) domain_usecases.ForceConfirmSubscriptionUseCase {
+	return &ForceConfirmSubscription{
+		repo: repo,
+	}
+}
+
+type SubscriptionStats struct {
+	repo domain_repository.SubscriptionRepository
+}
+
+func (uc *SubscriptionStats) Stats(ctx context.Context) (*domain.SubscriptionStats, error) {
+	return uc.repo.Stats(ctx)
+}
+
+func NewSubscriptionStatsUseCase(repo domain_repository.SubscriptionRepository) domain_usecases.SubscriptionStatsUseCase {
+	return &SubscriptionStats{
+		repo: repo,
+	}
+}
+
+type SubscriptionHistory struct {
+	repo domain_repository.SubscriptionRepository
+}
+
+func (uc *SubscriptionHistory) History(ctx context.Context, email, city string) (*domain.SubscriptionHistory, error) {
+	subscriptions, err := uc.repo.History(ctx, email, city)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.SubscriptionHistory{Email: email, City: city, Subscriptions: subscriptions}, nil
+}
+
+func NewSubscriptionHistoryUseCase(repo domain_repository.SubscriptionRepository) domain_usecases.SubscriptionHistoryUseCase {
+	return &SubscriptionHistory{
+		repo: repo,
+	}
+}
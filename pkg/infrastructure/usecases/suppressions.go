@@ -0,0 +1,133 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
+	domain_usecases "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/email_service/feedback"
+)
+
+const (
+	mailboxSource       = "mailbox"
+	mailboxProcessedDir = "processed"
+	mailboxFailedDir    = "failed"
+)
+
+// suppress adds the addresses that hard-bounced or complained to the
+// suppression list and returns how many there were.
+func suppress(ctx context.Context, repo domain_repository.SuppressionRepository, source string, items []domain.EmailFeedback) (int, error) {
+	suppressed := 0
+	for _, item := range items {
+		if !item.Suppresses() {
+			continue
+		}
+
+		if err := repo.Save(ctx, domain.NewSuppression(item, source)); err != nil {
+			return suppressed, err
+		}
+		suppressed++
+	}
+
+	if suppressed > 0 {
+		log.Printf("Suppressed %d email addresses from %s feedback", suppressed, source)
+	}
+
+	return suppressed, nil
+}
+
+type ProcessEmailFeedback struct {
+	repo domain_repository.SuppressionRepository
+}
+
+func (uc *ProcessEmailFeedback) ProcessWebhook(ctx context.Context, provider string, payload []byte) (int, error) {
+	items, err := feedback.ParseWebhook(provider, payload)
+	if err != nil {
+		return 0, err
+	}
+
+	return suppress(ctx, uc.repo, provider, items)
+}
+
+func NewProcessEmailFeedbackUseCase(repo domain_repository.SuppressionRepository) domain_usecases.ProcessEmailFeedbackUseCase {
+	return &ProcessEmailFeedback{
+		repo: repo,
+	}
+}
+
+type ProcessBounceMailbox struct {
+	repo domain_repository.SuppressionRepository
+	dir  string
+}
+
+// ProcessMailbox parses each file in the mailbox directory as a delivery
+// status notification. Parsed files are moved to processed/ and files that
+// are not bounces to failed/, so every message is read once. A file whose
+// addresses could not be saved stays for the next run.
+func (uc *ProcessBounceMailbox) ProcessMailbox(ctx context.Context) (int, error) {
+	entries, err := os.ReadDir(uc.dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read bounce mailbox: %w", err)
+	}
+
+	suppressed := 0
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(uc.dir, entry.Name())
+
+		items, err := parseBounceFile(path)
+		if err != nil {
+			log.Printf("Failed to parse bounce message %s: %v", entry.Name(), err)
+			if err := moveMailboxFile(uc.dir, entry.Name(), mailboxFailedDir); err != nil {
+				return suppressed, err
+			}
+			continue
+		}
+
+		count, err := suppress(ctx, uc.repo, mailboxSource, items)
+		suppressed += count
+		if err != nil {
+			return suppressed, err
+		}
+
+		if err := moveMailboxFile(uc.dir, entry.Name(), mailboxProcessedDir); err != nil {
+			return suppressed, err
+		}
+	}
+
+	return suppressed, nil
+}
+
+func parseBounceFile(path string) ([]domain.EmailFeedback, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return feedback.ParseDSN(file)
+}
+
+func moveMailboxFile(dir, name, subdir string) error {
+	if err := os.MkdirAll(filepath.Join(dir, subdir), 0o750); err != nil {
+		return err
+	}
+
+	return os.Rename(filepath.Join(dir, name), filepath.Join(dir, subdir, name))
+}
+
+func NewProcessBounceMailboxUseCase(repo domain_repository.SuppressionRepository, dir string) domain_usecases.ProcessBounceMailboxUseCase {
+	return &ProcessBounceMailbox{
+		repo: repo,
+		dir:  dir,
+	}
+}
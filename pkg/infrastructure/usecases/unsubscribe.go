@@ -4,16 +4,18 @@ import (
 	"context"
 
 	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
 	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
 	domain_usecases "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
 )
 
 type Unsubscribe struct {
-	repo domain_repository.SubscriptionRepository
+	repo  domain_repository.SubscriptionRepository
+	links domain.ActionLinks
 }
 
 func (uc *Unsubscribe) Unsubscribe(ctx context.Context, token string) error {
-	subscription, err := uc.repo.FindByUnsubscribeToken(ctx, token)
+	subscription, err := findByLinkToken(ctx, uc.repo, uc.links, token, domain.UnsubscribeLinkAction)
 
 	if err != nil {
 		return err
@@ -30,8 +32,45 @@ func (uc *Unsubscribe) Unsubscribe(ctx context.Context, token string) error {
 	return nil
 }
 
-func NewUnsubscribeUseCase(repo domain_repository.SubscriptionRepository) domain_usecases.UnsubscribeUseCase {
+func NewUnsubscribeUseCase(repo domain_repository.SubscriptionRepository, links domain.ActionLinks) domain_usecases.UnsubscribeUseCase {
 	return &Unsubscribe{
-		repo: repo,
+		repo:  repo,
+		links: links,
+	}
+}
+
+const backfillBatchSize = 500
+
+type BackfillUnsubscribeTokens struct {
+	repo   domain_repository.SubscriptionRepository
+	tokens domain.UnsubscribeTokens
+}
+
+func (uc *BackfillUnsubscribeTokens) Backfill(ctx context.Context) (int, error) {
+	updated := 0
+
+	for {
+		ids, err := uc.repo.FindWithoutUnsubscribeTokenHash(ctx, backfillBatchSize)
+		if err != nil {
+			return updated, err
+		}
+
+		for _, id := range ids {
+			if err := uc.repo.SetUnsubscribeTokenHash(ctx, id, domain_entity.HashToken(uc.tokens.Token(id))); err != nil {
+				return updated, err
+			}
+			updated++
+		}
+
+		if len(ids) < backfillBatchSize {
+			return updated, nil
+		}
+	}
+}
+
+func NewBackfillUnsubscribeTokensUseCase(repo domain_repository.SubscriptionRepository, tokens domain.UnsubscribeTokens) domain_usecases.BackfillUnsubscribeTokensUseCase {
+	return &BackfillUnsubscribeTokens{
+		repo:   repo,
+		tokens: tokens,
 	}
 }
@@ -0,0 +1,39 @@
+package usecases
+
+import (
+	"context"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
+	domain_usecases "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+)
+
+type ListWebhookEndpoints struct {
+	repository domain_repository.WebhookEndpointRepository
+}
+
+func (uc *ListWebhookEndpoints) List(ctx context.Context) ([]domain.WebhookEndpoint, error) {
+	return uc.repository.List(ctx)
+}
+
+func NewListWebhookEndpointsUseCase(repository domain_repository.WebhookEndpointRepository) domain_usecases.ListWebhookEndpointsUseCase {
+	return &ListWebhookEndpoints{
+		repository: repository,
+	}
+}
+
+type EnableWebhookEndpoint struct {
+	repository domain_repository.WebhookEndpointRepository
+}
+
+// Enable lets deliveries reach an endpoint that was disabled after repeated
+// failures. Its failure count starts over.
+func (uc *EnableWebhookEndpoint) Enable(ctx context.Context, url string) error {
+	return uc.repository.Enable(ctx, url)
+}
+
+func NewEnableWebhookEndpointUseCase(repository domain_repository.WebhookEndpointRepository) domain_usecases.EnableWebhookEndpointUseCase {
+	return &EnableWebhookEndpoint{
+		repository: repository,
+	}
+}
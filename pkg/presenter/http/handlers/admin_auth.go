@@ -0,0 +1,38 @@
+package http
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	usecase "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAPIKeyContextKey holds the authenticated *domain.APIKey in the gin
+// context.
+const AdminAPIKeyContextKey = "admin_api_key"
+
+// AdminAuthMiddleware requires an admin API key as a bearer token.
+func AdminAuthMiddleware(uc usecase.AuthenticateAPIKeyUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, _ := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+
+		apiKey, err := uc.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			switch err {
+			case domain.ErrInvalidAPIKey:
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			default:
+				// The error may describe the key store; keep it in the log.
+				log.Printf("Failed to authenticate API key: %v", err)
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			}
+			return
+		}
+
+		c.Set(AdminAPIKeyContextKey, apiKey)
+		c.Next()
+	}
+}
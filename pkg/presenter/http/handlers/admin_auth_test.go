@@ -0,0 +1,59 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+// stubAPIKeyAuth accepts a single key.
+type stubAPIKeyAuth struct {
+	key string
+	err error
+}
+
+func (s stubAPIKeyAuth) Authenticate(ctx context.Context, key string) (*domain.APIKey, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if key == "" || key != s.key {
+		return nil, domain.ErrInvalidAPIKey
+	}
+	return &domain.APIKey{Name: "test"}, nil
+}
+
+func TestAdminAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func(auth stubAPIKeyAuth) *gin.Engine {
+		r := gin.New()
+		r.GET("/admin/ping", AdminAuthMiddleware(auth), func(c *gin.Context) {
+			apiKey := c.MustGet(AdminAPIKeyContextKey).(*domain.APIKey)
+			c.String(http.StatusOK, apiKey.Name)
+		})
+		return r
+	}
+
+	router := newRouter(stubAPIKeyAuth{key: "secret"})
+
+	w := adminRequest(router, http.MethodGet, "/admin/ping", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = adminRequest(router, http.MethodGet, "/admin/ping", "wrong")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = adminRequest(router, http.MethodGet, "/admin/ping", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "test", w.Body.String())
+
+	router = newRouter(stubAPIKeyAuth{err: errors.New("db down")})
+	w = adminRequest(router, http.MethodGet, "/admin/ping", "secret")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.NotContains(t, w.Body.String(), "db down")
+}
@@ -0,0 +1,254 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	usecase "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const defaultSubscriptionsPageSize = 50
+
+type ListSubscriptionsQuery struct {
+	City      string `form:"city"`
+	Frequency string `form:"frequency" binding:"omitempty,oneof=hourly daily alert"`
+	Confirmed *bool  `form:"confirmed"`
+	Page      int    `form:"page" binding:"omitempty,min=1"`
+	PageSize  int    `form:"page_size" binding:"omitempty,min=1,max=200"`
+}
+
+type SubscriptionResponse struct {
+	ID             uuid.UUID          `json:"id"`
+	Email          string             `json:"email"`
+	City           string             `json:"city"`
+	Frequency      string             `json:"frequency"`
+	Confirmed      bool               `json:"confirmed"`
+	CreatedAt      time.Time          `json:"created_at"`
+	ConfirmedAt    *time.Time         `json:"confirmed_at,omitempty"`
+	LastSentAt     *time.Time         `json:"last_sent_at,omitempty"`
+	Timezone       string             `json:"timezone"`
+	SendHour       int                `json:"send_hour"`
+	Alert          *AlertRuleResponse `json:"alert,omitempty"`
+	AlertTriggered bool               `json:"alert_triggered,omitempty"`
+	UnsubscribedAt *time.Time         `json:"unsubscribed_at,omitempty"`
+}
+
+type AlertRuleResponse struct {
+	Metric          string  `json:"metric"`
+	Comparator      string  `json:"comparator"`
+	Threshold       float64 `json:"threshold"`
+	CooldownMinutes int     `json:"cooldown_minutes"`
+}
+
+type SubscriptionHistoryQuery struct {
+	Email string `form:"email" binding:"required,email"`
+	City  string `form:"city" binding:"required"`
+}
+
+type SubscriptionHistoryResponse struct {
+	Email         string                 `json:"email"`
+	City          string                 `json:"city"`
+	Subscriptions []SubscriptionResponse `json:"subscriptions"`
+	Unsubscribes  int                    `json:"unsubscribes"`
+}
+
+type SubscriptionStatsResponse struct {
+	Total            int64               `json:"total"`
+	Confirmed        int64               `json:"confirmed"`
+	ConfirmationRate float64             `json:"confirmation_rate"`
+	ByFrequency      map[string]int64    `json:"by_frequency"`
+	ByCity           []CityStatsResponse `json:"by_city"`
+}
+
+type CityStatsResponse struct {
+	City      string `json:"city"`
+	Total     int64  `json:"total"`
+	Confirmed int64  `json:"confirmed"`
+}
+
+func toSubscriptionResponse(s *entity.Subscription) SubscriptionResponse {
+	response := SubscriptionResponse{
+		ID:             s.ID,
+		Email:          s.Email,
+		City:           s.City,
+		Frequency:      strings.ToLower(string(s.Frequency)),
+		Confirmed:      s.Confirmed,
+		CreatedAt:      s.CreatedAt,
+		ConfirmedAt:    s.ConfirmedAt,
+		LastSentAt:     s.LastSentAt,
+		Timezone:       s.Timezone,
+		SendHour:       s.SendHour,
+		AlertTriggered: s.AlertTriggered,
+		UnsubscribedAt: s.UnsubscribedAt,
+	}
+
+	if s.AlertRule != nil {
+		response.Alert = &AlertRuleResponse{
+			Metric:          strings.ToLower(string(s.AlertRule.Metric)),
+			Comparator:      strings.ToLower(string(s.AlertRule.Comparator)),
+			Threshold:       s.AlertRule.Threshold,
+			CooldownMinutes: int(s.AlertRule.Cooldown / time.Minute),
+		}
+	}
+
+	return response
+}
+
+func ListSubscriptionsHandler(uc usecase.ListSubscriptionsUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query ListSubscriptionsQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if query.Page == 0 {
+			query.Page = 1
+		}
+		if query.PageSize == 0 {
+			query.PageSize = defaultSubscriptionsPageSize
+		}
+
+		subscriptions, total, err := uc.List(c.Request.Context(), entity.SubscriptionFilter{
+			City:      query.City,
+			Frequency: entity.Frequency(strings.ToUpper(query.Frequency)),
+			Confirmed: query.Confirmed,
+			Limit:     query.PageSize,
+			Offset:    (query.Page - 1) * query.PageSize,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		items := make([]SubscriptionResponse, len(subscriptions))
+		for i, s := range subscriptions {
+			items[i] = toSubscriptionResponse(s)
+		}
+
+		c.JSON(http.StatusOK, gin.H{"items": items, "total": total, "page": query.Page, "page_size": query.PageSize})
+	}
+}
+
+func GetSubscriptionHandler(uc usecase.GetSubscriptionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription id"})
+			return
+		}
+
+		subscription, err := uc.Get(c.Request.Context(), id)
+		if err != nil {
+			writeSubscriptionError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, toSubscriptionResponse(subscription))
+	}
+}
+
+// SubscriptionHistoryHandler lists every subscription an address has had to a
+// city, oldest first, including the ones it unsubscribed from.
+func SubscriptionHistoryHandler(uc usecase.SubscriptionHistoryUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query SubscriptionHistoryQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		history, err := uc.History(c.Request.Context(), query.Email, query.City)
+		if err != nil {
+			writeSubscriptionError(c, err)
+			return
+		}
+
+		response := SubscriptionHistoryResponse{
+			Email:         history.Email,
+			City:          history.City,
+			Subscriptions: make([]SubscriptionResponse, len(history.Subscriptions)),
+			Unsubscribes:  history.Unsubscribes(),
+		}
+		for i, s := range history.Subscriptions {
+			response.Subscriptions[i] = toSubscriptionResponse(s)
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func ForceConfirmSubscriptionHandler(uc usecase.ForceConfirmSubscriptionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription id"})
+			return
+		}
+
+		subscription, err := uc.ForceConfirm(c.Request.Context(), id)
+		if err != nil {
+			writeSubscriptionError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, toSubscriptionResponse(subscription))
+	}
+}
+
+func ForceUnsubscribeHandler(uc usecase.DeleteSubscriptionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid subscription id"})
+			return
+		}
+
+		if err := uc.Delete(c.Request.Context(), id); err != nil {
+			writeSubscriptionError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Unsubscribed successfully"})
+	}
+}
+
+func SubscriptionStatsHandler(uc usecase.SubscriptionStatsUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stats, err := uc.Stats(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		response := SubscriptionStatsResponse{
+			Total:            stats.Total,
+			Confirmed:        stats.Confirmed,
+			ConfirmationRate: stats.ConfirmationRate(),
+			ByFrequency:      make(map[string]int64, len(stats.ByFrequency)),
+			ByCity:           make([]CityStatsResponse, len(stats.ByCity)),
+		}
+		for frequency, count := range stats.ByFrequency {
+			response.ByFrequency[strings.ToLower(string(frequency))] = count
+		}
+		for i, city := range stats.ByCity {
+			response.ByCity[i] = CityStatsResponse{City: city.City, Total: city.Total, Confirmed: city.Confirmed}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+func writeSubscriptionError(c *gin.Context, err error) {
+	switch err {
+	case domain.ErrSubscriptionNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
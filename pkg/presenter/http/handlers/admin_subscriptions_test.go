@@ -0,0 +1,194 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+)
+
+type MockAdminSubscriptionsUseCase struct {
+	mock.Mock
+}
+
+func (m *MockAdminSubscriptionsUseCase) List(ctx context.Context, filter entity.SubscriptionFilter) ([]*entity.Subscription, int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*entity.Subscription), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAdminSubscriptionsUseCase) Get(ctx context.Context, id uuid.UUID) (*entity.Subscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Subscription), args.Error(1)
+}
+
+func (m *MockAdminSubscriptionsUseCase) ForceConfirm(ctx context.Context, id uuid.UUID) (*entity.Subscription, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Subscription), args.Error(1)
+}
+
+func (m *MockAdminSubscriptionsUseCase) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAdminSubscriptionsUseCase) Stats(ctx context.Context) (*entity.SubscriptionStats, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*entity.SubscriptionStats), args.Error(1)
+}
+
+func (m *MockAdminSubscriptionsUseCase) History(ctx context.Context, email, city string) (*entity.SubscriptionHistory, error) {
+	args := m.Called(ctx, email, city)
+	return args.Get(0).(*entity.SubscriptionHistory), args.Error(1)
+}
+
+func setupAdminSubscriptionsRouter(uc *MockAdminSubscriptionsUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	admin := r.Group("/admin", AdminAuthMiddleware(stubAPIKeyAuth{key: "secret"}))
+	admin.GET("/subscriptions", ListSubscriptionsHandler(uc))
+	admin.GET("/subscriptions/history", SubscriptionHistoryHandler(uc))
+	admin.GET("/subscriptions/:id", GetSubscriptionHandler(uc))
+	admin.POST("/subscriptions/:id/confirm", ForceConfirmSubscriptionHandler(uc))
+	admin.POST("/subscriptions/:id/unsubscribe", ForceUnsubscribeHandler(uc))
+	admin.GET("/stats", SubscriptionStatsHandler(uc))
+	return r
+}
+
+func TestListSubscriptionsHandler(t *testing.T) {
+	mockUC := new(MockAdminSubscriptionsUseCase)
+	subscription, _ := entity.NewSubscription("a@example.com", "Kyiv", entity.FrequencyDaily)
+	confirmed := true
+	mockUC.On("List", mock.Anything, entity.SubscriptionFilter{
+		City:      "Kyiv",
+		Frequency: entity.FrequencyDaily,
+		Confirmed: &confirmed,
+		Limit:     10,
+		Offset:    20,
+	}).Return([]*entity.Subscription{subscription}, int64(21), nil).Once()
+
+	router := setupAdminSubscriptionsRouter(mockUC)
+
+	w := adminRequest(router, http.MethodGet, "/admin/subscriptions", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = adminRequest(router, http.MethodGet, "/admin/subscriptions?city=Kyiv&frequency=daily&confirmed=true&page=3&page_size=10", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"total":21`)
+	assert.Contains(t, w.Body.String(), `"email":"a@example.com"`)
+	assert.Contains(t, w.Body.String(), `"frequency":"daily"`)
+	mockUC.AssertExpectations(t)
+
+	w = adminRequest(router, http.MethodGet, "/admin/subscriptions?frequency=weekly", "secret")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = adminRequest(router, http.MethodGet, "/admin/subscriptions?page_size=1000", "secret")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetSubscriptionHandler(t *testing.T) {
+	mockUC := new(MockAdminSubscriptionsUseCase)
+	subscription, _ := entity.NewSubscription("a@example.com", "Kyiv", entity.FrequencyHourly)
+	missing := uuid.New()
+	mockUC.On("Get", mock.Anything, subscription.ID).Return(subscription, nil).Once()
+	mockUC.On("Get", mock.Anything, missing).Return(nil, domain.ErrSubscriptionNotFound).Once()
+
+	router := setupAdminSubscriptionsRouter(mockUC)
+
+	w := adminRequest(router, http.MethodGet, "/admin/subscriptions/"+subscription.ID.String(), "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), subscription.ID.String())
+
+	w = adminRequest(router, http.MethodGet, "/admin/subscriptions/"+missing.String(), "secret")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = adminRequest(router, http.MethodGet, "/admin/subscriptions/not-a-uuid", "secret")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestForceConfirmAndUnsubscribeHandlers(t *testing.T) {
+	mockUC := new(MockAdminSubscriptionsUseCase)
+	subscription, _ := entity.NewSubscription("a@example.com", "Kyiv", entity.FrequencyDaily)
+	now := time.Now()
+	subscription.Confirmed = true
+	subscription.ConfirmedAt = &now
+	missing := uuid.New()
+	mockUC.On("ForceConfirm", mock.Anything, subscription.ID).Return(subscription, nil).Once()
+	mockUC.On("Delete", mock.Anything, subscription.ID).Return(nil).Once()
+	mockUC.On("Delete", mock.Anything, missing).Return(domain.ErrSubscriptionNotFound).Once()
+
+	router := setupAdminSubscriptionsRouter(mockUC)
+
+	w := adminRequest(router, http.MethodPost, "/admin/subscriptions/"+subscription.ID.String()+"/confirm", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"confirmed":true`)
+
+	w = adminRequest(router, http.MethodPost, "/admin/subscriptions/"+subscription.ID.String()+"/unsubscribe", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = adminRequest(router, http.MethodPost, "/admin/subscriptions/"+missing.String()+"/unsubscribe", "secret")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestSubscriptionStatsHandler(t *testing.T) {
+	mockUC := new(MockAdminSubscriptionsUseCase)
+	mockUC.On("Stats", mock.Anything).Return(&entity.SubscriptionStats{
+		Total:       4,
+		Confirmed:   3,
+		ByFrequency: map[entity.Frequency]int64{entity.FrequencyDaily: 3, entity.FrequencyHourly: 1},
+		ByCity:      []entity.CityStats{{City: "Kyiv", Total: 4, Confirmed: 3}},
+	}, nil).Once()
+
+	router := setupAdminSubscriptionsRouter(mockUC)
+
+	w := adminRequest(router, http.MethodGet, "/admin/stats", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{
+		"total": 4,
+		"confirmed": 3,
+		"confirmation_rate": 0.75,
+		"by_frequency": {"daily": 3, "hourly": 1},
+		"by_city": [{"city": "Kyiv", "total": 4, "confirmed": 3}]
+	}`, w.Body.String())
+	mockUC.AssertExpectations(t)
+}
+
+func TestSubscriptionHistoryHandler(t *testing.T) {
+	mockUC := new(MockAdminSubscriptionsUseCase)
+	past, _ := entity.NewSubscription("a@example.com", "Kyiv", entity.FrequencyDaily)
+	unsubscribedAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	past.UnsubscribedAt = &unsubscribedAt
+	current, _ := entity.NewSubscription("a@example.com", "Kyiv", entity.FrequencyHourly)
+	mockUC.On("History", mock.Anything, "a@example.com", "Kyiv").Return(&entity.SubscriptionHistory{
+		Email:         "a@example.com",
+		City:          "Kyiv",
+		Subscriptions: []*entity.Subscription{past, current},
+	}, nil).Once()
+
+	router := setupAdminSubscriptionsRouter(mockUC)
+
+	w := adminRequest(router, http.MethodGet, "/admin/subscriptions/history?email=a@example.com&city=Kyiv", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"unsubscribes":1`)
+	assert.Contains(t, w.Body.String(), `"unsubscribed_at":"2025-03-01T00:00:00Z"`)
+	assert.Contains(t, w.Body.String(), current.ID.String())
+
+	w = adminRequest(router, http.MethodGet, "/admin/subscriptions/history?city=Kyiv", "secret")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertExpectations(t)
+}
@@ -4,9 +4,9 @@ import (
 	"net/http"
 
 	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
 	usecase "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 func CheckConfirmationTokenHandler(uc usecase.CheckTokensUseCase) gin.HandlerFunc {
@@ -18,16 +18,13 @@ func CheckConfirmationTokenHandler(uc usecase.CheckTokensUseCase) gin.HandlerFun
 			return
 		}
 
-		if _, err := uuid.Parse(token); err != nil {
-			c.HTML(http.StatusBadRequest, "404.html", gin.H{"Message": "Invalid token format"})
-			return
-		}
-
 		res, err := uc.CheckConfirmationToken(c.Request.Context(), token)
 		if err != nil {
 			switch err {
 			case domain.ErrSubscriptionNotFound:
 				c.HTML(http.StatusNotFound, "404.html", gin.H{"Message": "Token not found"})
+			case entity.ErrConfirmationExpired, domain.ErrActionLinkExpired:
+				c.HTML(http.StatusGone, "404.html", gin.H{"Message": "This confirmation link has expired. Please request a new confirmation email."})
 			default:
 				c.HTML(http.StatusBadRequest, "404.html", gin.H{"Message": err.Error()})
 			}
@@ -52,16 +49,13 @@ func CheckUnsubscribeTokenHandler(uc usecase.CheckTokensUseCase) gin.HandlerFunc
 			return
 		}
 
-		if _, err := uuid.Parse(token); err != nil {
-			c.HTML(http.StatusBadRequest, "404.html", gin.H{"Message": "Invalid token format"})
-			return
-		}
-
 		res, err := uc.CheckUnsubscribeToken(c.Request.Context(), token)
 		if err != nil {
 			switch err {
 			case domain.ErrSubscriptionNotFound:
 				c.HTML(http.StatusNotFound, "404.html", gin.H{"Message": "Token not found"})
+			case domain.ErrActionLinkExpired:
+				c.HTML(http.StatusGone, "404.html", gin.H{"Message": "This unsubscribe link has expired. Please use the link from a more recent email."})
 			default:
 				c.HTML(http.StatusBadRequest, "404.html", gin.H{"Message": err.Error()})
 			}
@@ -4,9 +4,9 @@ import (
 	"net/http"
 
 	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
 	usecase "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 // @Summary Confirm subscription
@@ -14,10 +14,11 @@ import (
 // @Tags subscription
 // @Accept json
 // @Produce json
-// @Param token path string true "Confirmation token"
+// @Param token path string true "Confirmation token or signed link token"
 // @Success 200 {object} map[string]string "Subscription confirmed"
-// @Failure 400 {object} map[string]string "Invalid token format or missing token"
+// @Failure 400 {object} map[string]string "Missing token or invalid link"
 // @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 410 {object} map[string]string "Confirmation link expired"
 // @Router /confirm/{token} [post]
 func ConfirmHandler(uc usecase.ConfirmSubscriptionUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -27,16 +28,13 @@ func ConfirmHandler(uc usecase.ConfirmSubscriptionUseCase) gin.HandlerFunc {
 			return
 		}
 
-		if _, err := uuid.Parse(token); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid token format"})
-			return
-		}
-
 		err := uc.Confirm(c.Request.Context(), token)
 		if err != nil {
 			switch err {
 			case domain.ErrSubscriptionNotFound:
 				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case entity.ErrConfirmationExpired, domain.ErrActionLinkExpired:
+				c.JSON(http.StatusGone, gin.H{"error": err.Error()})
 			default:
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			}
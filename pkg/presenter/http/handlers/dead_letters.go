@@ -0,0 +1,90 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	usecase "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const defaultDeadLettersLimit = 50
+
+type ListDeadLettersQuery struct {
+	Limit           int  `form:"limit" binding:"omitempty,min=1,max=200"`
+	Offset          int  `form:"offset" binding:"omitempty,min=0"`
+	IncludeReplayed bool `form:"include_replayed"`
+}
+
+type DeadLetterResponse struct {
+	ID         uuid.UUID       `json:"id"`
+	EventType  string          `json:"event_type"`
+	Payload    json.RawMessage `json:"payload"`
+	Error      string          `json:"error"`
+	Attempts   int             `json:"attempts"`
+	CreatedAt  time.Time       `json:"created_at"`
+	ReplayedAt *time.Time      `json:"replayed_at,omitempty"`
+}
+
+func ListDeadLettersHandler(uc usecase.ListDeadLettersUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query ListDeadLettersQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if query.Limit == 0 {
+			query.Limit = defaultDeadLettersLimit
+		}
+
+		deadLetters, err := uc.List(c.Request.Context(), query.Limit, query.Offset, query.IncludeReplayed)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		items := make([]DeadLetterResponse, len(deadLetters))
+		for i, d := range deadLetters {
+			items[i] = DeadLetterResponse{
+				ID:         d.ID,
+				EventType:  string(d.EventType),
+				Payload:    d.Payload,
+				Error:      d.Error,
+				Attempts:   d.Attempts,
+				CreatedAt:  d.CreatedAt,
+				ReplayedAt: d.ReplayedAt,
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"items": items, "limit": query.Limit, "offset": query.Offset})
+	}
+}
+
+func ReplayDeadLetterHandler(uc usecase.ReplayDeadLetterUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dead letter id"})
+			return
+		}
+
+		err = uc.Replay(c.Request.Context(), id)
+		if err != nil {
+			switch err {
+			case domain.ErrDeadLetterNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case domain.ErrDeadLetterAlreadyReplayed:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.JSON(http.StatusAccepted, gin.H{"message": "Dead letter replayed"})
+	}
+}
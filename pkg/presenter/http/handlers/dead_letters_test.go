@@ -0,0 +1,87 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+type MockDeadLettersUseCase struct {
+	mock.Mock
+}
+
+func (m *MockDeadLettersUseCase) List(ctx context.Context, limit, offset int, includeReplayed bool) ([]domain.DeadLetter, error) {
+	args := m.Called(ctx, limit, offset, includeReplayed)
+	return args.Get(0).([]domain.DeadLetter), args.Error(1)
+}
+
+func (m *MockDeadLettersUseCase) Replay(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func setupDeadLettersRouter(uc *MockDeadLettersUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	admin := r.Group("/admin", AdminAuthMiddleware(stubAPIKeyAuth{key: "secret"}))
+	admin.GET("/dead-letters", ListDeadLettersHandler(uc))
+	admin.POST("/dead-letters/:id/replay", ReplayDeadLetterHandler(uc))
+	return r
+}
+
+func adminRequest(router *gin.Engine, method, path, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestListDeadLettersHandler(t *testing.T) {
+	mockUC := new(MockDeadLettersUseCase)
+	deadLetter := domain.NewDeadLetter(domain.UserSubscribed, []byte(`{"Email":"a@example.com"}`), "smtp unavailable", 8)
+	mockUC.On("List", mock.Anything, 10, 20, false).Return([]domain.DeadLetter{*deadLetter}, nil).Once()
+
+	router := setupDeadLettersRouter(mockUC)
+
+	w := adminRequest(router, http.MethodGet, "/admin/dead-letters?limit=10&offset=20", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = adminRequest(router, http.MethodGet, "/admin/dead-letters?limit=10&offset=20", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"payload":{"Email":"a@example.com"}`)
+	mockUC.AssertExpectations(t)
+
+	w = adminRequest(router, http.MethodGet, "/admin/dead-letters?limit=1000", "secret")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestReplayDeadLetterHandler(t *testing.T) {
+	mockUC := new(MockDeadLettersUseCase)
+	id := uuid.New()
+	replayed := uuid.New()
+	mockUC.On("Replay", mock.Anything, id).Return(nil).Once()
+	mockUC.On("Replay", mock.Anything, replayed).Return(domain.ErrDeadLetterAlreadyReplayed).Once()
+
+	router := setupDeadLettersRouter(mockUC)
+
+	w := adminRequest(router, http.MethodPost, "/admin/dead-letters/"+id.String()+"/replay", "secret")
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	w = adminRequest(router, http.MethodPost, "/admin/dead-letters/"+replayed.String()+"/replay", "secret")
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = adminRequest(router, http.MethodPost, "/admin/dead-letters/not-a-uuid/replay", "secret")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertExpectations(t)
+}
@@ -0,0 +1,54 @@
+package http
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	usecase "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+const maxFeedbackPayloadSize = 1 << 20
+
+// EmailFeedbackWebhookHandler receives bounce and complaint notifications
+// for the provider in the path. Providers cannot sign requests the same
+// way, so the shared secret is accepted as a bearer token or as the token
+// query parameter of the configured webhook URL.
+func EmailFeedbackWebhookHandler(uc usecase.ProcessEmailFeedbackUseCase, secret string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Query("token")
+		if bearer, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+			token = bearer
+		}
+
+		if secret == "" || subtle.ConstantTimeCompare([]byte(token), []byte(secret)) != 1 {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid webhook token"})
+			return
+		}
+
+		payload, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxFeedbackPayloadSize))
+		if err != nil {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		}
+
+		suppressed, err := uc.ProcessWebhook(c.Request.Context(), c.Param("provider"), payload)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrUnknownFeedbackProvider):
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case errors.Is(err, domain.ErrInvalidFeedbackPayload):
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"suppressed": suppressed})
+	}
+}
@@ -0,0 +1,69 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+type MockProcessEmailFeedbackUseCase struct {
+	mock.Mock
+}
+
+func (m *MockProcessEmailFeedbackUseCase) ProcessWebhook(ctx context.Context, provider string, payload []byte) (int, error) {
+	args := m.Called(ctx, provider, string(payload))
+	return args.Int(0), args.Error(1)
+}
+
+func feedbackRequest(router *gin.Engine, path, authorization, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestEmailFeedbackWebhookHandler(t *testing.T) {
+	mockUC := new(MockProcessEmailFeedbackUseCase)
+	payload := `[{"email":"gone@example.com","event":"bounce"}]`
+	mockUC.On("ProcessWebhook", mock.Anything, "sendgrid", payload).Return(1, nil).Twice()
+	mockUC.On("ProcessWebhook", mock.Anything, "mailgun", "{}").Return(0, fmt.Errorf("%w: %q", domain.ErrUnknownFeedbackProvider, "mailgun")).Once()
+	mockUC.On("ProcessWebhook", mock.Anything, "generic", "nope").Return(0, fmt.Errorf("%w: bad json", domain.ErrInvalidFeedbackPayload)).Once()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/webhooks/email/:provider", EmailFeedbackWebhookHandler(mockUC, "hook-secret"))
+
+	w := feedbackRequest(router, "/webhooks/email/sendgrid", "", payload)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = feedbackRequest(router, "/webhooks/email/sendgrid?token=wrong", "", payload)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = feedbackRequest(router, "/webhooks/email/sendgrid?token=hook-secret", "", payload)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"suppressed":1}`, w.Body.String())
+
+	w = feedbackRequest(router, "/webhooks/email/sendgrid", "Bearer hook-secret", payload)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = feedbackRequest(router, "/webhooks/email/mailgun", "Bearer hook-secret", "{}")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = feedbackRequest(router, "/webhooks/email/generic", "Bearer hook-secret", "nope")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockUC.AssertExpectations(t)
+}
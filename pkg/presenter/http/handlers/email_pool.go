@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/http"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	usecase "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type EmailPoolStatsResponse struct {
+	MaxConnections int   `json:"max_connections"`
+	Open           int   `json:"open"`
+	Idle           int   `json:"idle"`
+	Dials          int64 `json:"dials"`
+	DialFailures   int64 `json:"dial_failures"`
+	Sent           int64 `json:"sent"`
+	Failed         int64 `json:"failed"`
+	Throttled      int64 `json:"throttled"`
+}
+
+func EmailPoolStatsHandler(uc usecase.EmailPoolStatsUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		stats, err := uc.Stats(c.Request.Context())
+		if err != nil {
+			switch err {
+			case domain.ErrEmailPoolStatsUnavailable:
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, EmailPoolStatsResponse{
+			MaxConnections: stats.MaxConnections,
+			Open:           stats.Open,
+			Idle:           stats.Idle,
+			Dials:          stats.Dials,
+			DialFailures:   stats.DialFailures,
+			Sent:           stats.Sent,
+			Failed:         stats.Failed,
+			Throttled:      stats.Throttled,
+		})
+	}
+}
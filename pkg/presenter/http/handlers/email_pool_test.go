@@ -0,0 +1,52 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+type MockEmailPoolStatsUseCase struct {
+	mock.Mock
+}
+
+func (m *MockEmailPoolStatsUseCase) Stats(ctx context.Context) (*domain.EmailPoolStats, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.EmailPoolStats), args.Error(1)
+}
+
+func setupEmailPoolRouter(uc *MockEmailPoolStatsUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	admin := r.Group("/admin", AdminAuthMiddleware(stubAPIKeyAuth{key: "secret"}))
+	admin.GET("/email/pool", EmailPoolStatsHandler(uc))
+	return r
+}
+
+func TestEmailPoolStatsHandler(t *testing.T) {
+	mockUC := new(MockEmailPoolStatsUseCase)
+	mockUC.On("Stats", mock.Anything).Return(&domain.EmailPoolStats{MaxConnections: 4, Open: 2, Idle: 1, Dials: 3, Sent: 10, Throttled: 5}, nil).Once()
+	mockUC.On("Stats", mock.Anything).Return(nil, domain.ErrEmailPoolStatsUnavailable).Once()
+
+	router := setupEmailPoolRouter(mockUC)
+
+	w := adminRequest(router, http.MethodGet, "/admin/email/pool", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = adminRequest(router, http.MethodGet, "/admin/email/pool", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"max_connections":4,"open":2,"idle":1,"dials":3,"dial_failures":0,"sent":10,"failed":0,"throttled":5}`, w.Body.String())
+
+	w = adminRequest(router, http.MethodGet, "/admin/email/pool", "secret")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockUC.AssertExpectations(t)
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	usecase "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get weather forecast by city
+// @Description Get a multi-day weather forecast for a specific city
+// @Tags weather
+// @Accept json
+// @Produce json
+// @Param city query string true "City name"
+// @Param days query int false "Number of days (1-7, default 3)"
+// @Success 200 {object} domain.Forecast "Forecast information"
+// @Failure 400 {object} map[string]string "Invalid request or missing city parameter"
+// @Failure 404 {object} map[string]string "City not found"
+// @Router /forecast [get]
+func GetForecastHandler(uc usecase.GetForecastUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		city := c.Query("city")
+		if city == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "query parameter 'city' is required"})
+			return
+		}
+
+		days := usecase.DefaultForecastDays
+		if raw := c.Query("days"); raw != "" {
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 1 || parsed > usecase.MaxForecastDays {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("query parameter 'days' must be between 1 and %d", usecase.MaxForecastDays)})
+				return
+			}
+			days = parsed
+		}
+
+		res, err := uc.GetForecast(c.Request.Context(), city, days)
+		if err != nil {
+			switch err {
+			case domain.ErrCityNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, res)
+	}
+}
@@ -0,0 +1,94 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domain_errors "github.com/danik-tro/weather-subscriber/pkg/domain"
+	usecase "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain/value_object"
+)
+
+type MockGetForecastUseCase struct {
+	mock.Mock
+}
+
+func (m *MockGetForecastUseCase) GetForecast(ctx context.Context, city string, days int) (domain.Forecast, error) {
+	args := m.Called(ctx, city, days)
+	return args.Get(0).(domain.Forecast), args.Error(1)
+}
+
+func setupForecastRouter(uc usecase.GetForecastUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/api/forecast", GetForecastHandler(uc))
+	return r
+}
+
+func TestGetForecastHandler_Success(t *testing.T) {
+	mockUC := new(MockGetForecastUseCase)
+
+	want := domain.Forecast{Days: []domain.ForecastDay{
+		{Date: "2025-01-01", MinTemperature: -2, MaxTemperature: 3, PrecipitationChance: 60, Description: "Snow"},
+	}}
+	mockUC.On("GetForecast", mock.Anything, "Kyiv", 1).Return(want, nil).Once()
+
+	router := setupForecastRouter(mockUC)
+	req := httptest.NewRequest(http.MethodGet, "/api/forecast?city=Kyiv&days=1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t,
+		`{"days":[{"date":"2025-01-01","min_temperature":-2,"max_temperature":3,"precipitation_chance":60,"description":"Snow"}]}`,
+		w.Body.String(),
+	)
+	mockUC.AssertExpectations(t)
+}
+
+func TestGetForecastHandler_DefaultDays(t *testing.T) {
+	mockUC := new(MockGetForecastUseCase)
+	mockUC.On("GetForecast", mock.Anything, "Kyiv", usecase.DefaultForecastDays).
+		Return(domain.Forecast{}, nil).Once()
+
+	router := setupForecastRouter(mockUC)
+	req := httptest.NewRequest(http.MethodGet, "/api/forecast?city=Kyiv", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestGetForecastHandler_InvalidDays(t *testing.T) {
+	mockUC := new(MockGetForecastUseCase)
+	router := setupForecastRouter(mockUC)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/forecast?city=Kyiv&days=30", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.JSONEq(t, `{"error":"query parameter 'days' must be between 1 and 7"}`, w.Body.String())
+}
+
+func TestGetForecastHandler_CityNotFound(t *testing.T) {
+	mockUC := new(MockGetForecastUseCase)
+	mockUC.On("GetForecast", mock.Anything, "Nowhere", usecase.DefaultForecastDays).
+		Return(domain.Forecast{}, domain_errors.ErrCityNotFound).Once()
+
+	router := setupForecastRouter(mockUC)
+	req := httptest.NewRequest(http.MethodGet, "/api/forecast?city=Nowhere", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"city not found"}`, w.Body.String())
+	mockUC.AssertExpectations(t)
+}
@@ -0,0 +1,177 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	usecase "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ManageLinkRequest struct {
+	Email string `form:"email" binding:"required,email"`
+}
+
+type ManageSubscriptionRequest struct {
+	City      string `form:"city" binding:"required"`
+	Frequency string `form:"frequency" binding:"omitempty,oneof=hourly daily"`
+}
+
+// ManagedSubscriptionView is a subscription as shown on the manage page.
+type ManagedSubscriptionView struct {
+	ID        uuid.UUID
+	City      string
+	Frequency string
+	Confirmed bool
+	Alert     string
+}
+
+var manageNotices = map[string]string{
+	"updated": "Your subscription has been updated.",
+	"deleted": "Your subscription has been deleted.",
+}
+
+func toManagedSubscriptionView(s *entity.Subscription) ManagedSubscriptionView {
+	view := ManagedSubscriptionView{
+		ID:        s.ID,
+		City:      s.City,
+		Frequency: strings.ToLower(string(s.Frequency)),
+		Confirmed: s.Confirmed,
+	}
+
+	if s.AlertRule != nil {
+		metric := strings.ReplaceAll(strings.ToLower(string(s.AlertRule.Metric)), "_", " ")
+		view.Alert = fmt.Sprintf("%s %s %g", metric, s.AlertRule.Comparator.Symbol(), s.AlertRule.Threshold)
+	}
+
+	return view
+}
+
+func ManageRequestPageHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.HTML(http.StatusOK, "manage_request.html", gin.H{})
+	}
+}
+
+func RequestManageLinkHandler(uc usecase.RequestManageLinkUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ManageLinkRequest
+		if err := c.ShouldBind(&req); err != nil {
+			c.HTML(http.StatusBadRequest, "manage_request.html", gin.H{"Email": req.Email, "Error": "Please enter a valid email address."})
+			return
+		}
+
+		if err := uc.RequestLink(c.Request.Context(), req.Email); err != nil {
+			c.HTML(http.StatusInternalServerError, "manage_request.html", gin.H{"Email": req.Email, "Error": "Something went wrong, please try again later."})
+			return
+		}
+
+		c.HTML(http.StatusOK, "manage_request.html", gin.H{"Email": req.Email, "Sent": true})
+	}
+}
+
+func ManagePageHandler(uc usecase.ListManagedSubscriptionsUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		renderManagePage(c, uc, http.StatusOK, manageNotices[c.Query("status")], "")
+	}
+}
+
+func UpdateManagedSubscriptionHandler(listUC usecase.ListManagedSubscriptionsUseCase, uc usecase.UpdateManagedSubscriptionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Param("token")
+
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.HTML(http.StatusNotFound, "404.html", gin.H{"Message": "Subscription not found"})
+			return
+		}
+
+		var req ManageSubscriptionRequest
+		if err := c.ShouldBind(&req); err != nil {
+			renderManagePage(c, listUC, http.StatusBadRequest, "", "Please enter a city and choose hourly or daily updates.")
+			return
+		}
+
+		err = uc.Update(c.Request.Context(), token, id, entity.SubscriptionChanges{
+			City:      strings.TrimSpace(req.City),
+			Frequency: entity.Frequency(strings.ToUpper(req.Frequency)),
+		})
+		if err != nil {
+			switch err {
+			case domain.ErrInvalidManageToken:
+				c.HTML(http.StatusUnauthorized, "404.html", gin.H{"Message": err.Error()})
+			case domain.ErrSubscriptionNotFound:
+				c.HTML(http.StatusNotFound, "404.html", gin.H{"Message": "Subscription not found"})
+			case domain.ErrCityNotFound:
+				renderManagePage(c, listUC, http.StatusBadRequest, "", "We couldn't find weather for that city.")
+			case domain.ErrSubscriptionAlreadyExists:
+				renderManagePage(c, listUC, http.StatusConflict, "", "You are already subscribed to that city.")
+			case entity.ErrInvalidFrequencyChange:
+				renderManagePage(c, listUC, http.StatusBadRequest, "", "Alert subscriptions can't be switched to regular updates.")
+			default:
+				renderManagePage(c, listUC, http.StatusInternalServerError, "", "Something went wrong, please try again later.")
+			}
+			return
+		}
+
+		c.Redirect(http.StatusSeeOther, "/manage/"+token+"?status=updated")
+	}
+}
+
+func DeleteManagedSubscriptionHandler(uc usecase.DeleteManagedSubscriptionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Param("token")
+
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			c.HTML(http.StatusNotFound, "404.html", gin.H{"Message": "Subscription not found"})
+			return
+		}
+
+		if err := uc.Delete(c.Request.Context(), token, id); err != nil {
+			switch err {
+			case domain.ErrInvalidManageToken:
+				c.HTML(http.StatusUnauthorized, "404.html", gin.H{"Message": err.Error()})
+			case domain.ErrSubscriptionNotFound:
+				c.HTML(http.StatusNotFound, "404.html", gin.H{"Message": "Subscription not found"})
+			default:
+				c.HTML(http.StatusInternalServerError, "404.html", gin.H{"Message": "Something went wrong, please try again later."})
+			}
+			return
+		}
+
+		c.Redirect(http.StatusSeeOther, "/manage/"+token+"?status=deleted")
+	}
+}
+
+func renderManagePage(c *gin.Context, uc usecase.ListManagedSubscriptionsUseCase, status int, notice, errMessage string) {
+	token := c.Param("token")
+
+	email, subscriptions, err := uc.List(c.Request.Context(), token)
+	if err != nil {
+		switch err {
+		case domain.ErrInvalidManageToken:
+			c.HTML(http.StatusUnauthorized, "404.html", gin.H{"Message": err.Error()})
+		default:
+			c.HTML(http.StatusInternalServerError, "404.html", gin.H{"Message": "Something went wrong, please try again later."})
+		}
+		return
+	}
+
+	views := make([]ManagedSubscriptionView, len(subscriptions))
+	for i, s := range subscriptions {
+		views[i] = toManagedSubscriptionView(s)
+	}
+
+	c.HTML(status, "manage.html", gin.H{
+		"Email":         email,
+		"Token":         token,
+		"Subscriptions": views,
+		"Notice":        notice,
+		"Error":         errMessage,
+	})
+}
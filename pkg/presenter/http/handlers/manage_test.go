@@ -0,0 +1,141 @@
+package http
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	"github.com/danik-tro/weather-subscriber/templates"
+)
+
+type MockManageUseCase struct {
+	mock.Mock
+}
+
+func (m *MockManageUseCase) RequestLink(ctx context.Context, email string) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func (m *MockManageUseCase) List(ctx context.Context, token string) (string, []*entity.Subscription, error) {
+	args := m.Called(ctx, token)
+	if args.Get(1) == nil {
+		return args.String(0), nil, args.Error(2)
+	}
+	return args.String(0), args.Get(1).([]*entity.Subscription), args.Error(2)
+}
+
+func (m *MockManageUseCase) Update(ctx context.Context, token string, id uuid.UUID, changes entity.SubscriptionChanges) error {
+	args := m.Called(ctx, token, id, changes)
+	return args.Error(0)
+}
+
+func (m *MockManageUseCase) Delete(ctx context.Context, token string, id uuid.UUID) error {
+	args := m.Called(ctx, token, id)
+	return args.Error(0)
+}
+
+func setupManageRouter(uc *MockManageUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.SetHTMLTemplate(template.Must(templates.Load("")))
+	r.GET("/manage", ManageRequestPageHandler())
+	r.POST("/manage", RequestManageLinkHandler(uc))
+	r.GET("/manage/:token", ManagePageHandler(uc))
+	r.POST("/manage/:token/subscriptions/:id", UpdateManagedSubscriptionHandler(uc, uc))
+	r.POST("/manage/:token/subscriptions/:id/delete", DeleteManagedSubscriptionHandler(uc))
+	return r
+}
+
+func formRequest(router *gin.Engine, method, path string, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRequestManageLinkHandler(t *testing.T) {
+	mockUC := new(MockManageUseCase)
+	mockUC.On("RequestLink", mock.Anything, "user@example.com").Return(nil).Once()
+
+	router := setupManageRouter(mockUC)
+
+	w := formRequest(router, http.MethodPost, "/manage", url.Values{"email": {"user@example.com"}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Check your inbox")
+
+	w = formRequest(router, http.MethodPost, "/manage", url.Values{"email": {"not-an-email"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestManagePageHandler(t *testing.T) {
+	mockUC := new(MockManageUseCase)
+	subscription, _ := entity.NewSubscription("user@example.com", "Kyiv", entity.FrequencyHourly)
+	mockUC.On("List", mock.Anything, "valid").Return("user@example.com", []*entity.Subscription{subscription}, nil)
+	mockUC.On("List", mock.Anything, "expired").Return("", nil, domain.ErrInvalidManageToken)
+
+	router := setupManageRouter(mockUC)
+
+	w := formRequest(router, http.MethodGet, "/manage/valid?status=updated", nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Kyiv")
+	assert.Contains(t, w.Body.String(), "/manage/valid/subscriptions/"+subscription.ID.String())
+	assert.Contains(t, w.Body.String(), "has been updated")
+
+	w = formRequest(router, http.MethodGet, "/manage/expired", nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestUpdateManagedSubscriptionHandler(t *testing.T) {
+	mockUC := new(MockManageUseCase)
+	id := uuid.New()
+	mockUC.On("List", mock.Anything, "valid").Return("user@example.com", []*entity.Subscription{}, nil)
+	mockUC.On("Update", mock.Anything, "valid", id, entity.SubscriptionChanges{City: "Lviv", Frequency: entity.FrequencyDaily}).Return(nil).Once()
+	mockUC.On("Update", mock.Anything, "valid", id, entity.SubscriptionChanges{City: "Atlantis", Frequency: entity.FrequencyDaily}).Return(domain.ErrCityNotFound).Once()
+
+	router := setupManageRouter(mockUC)
+	path := "/manage/valid/subscriptions/" + id.String()
+
+	w := formRequest(router, http.MethodPost, path, url.Values{"city": {" Lviv "}, "frequency": {"daily"}})
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/manage/valid?status=updated", w.Header().Get("Location"))
+
+	w = formRequest(router, http.MethodPost, path, url.Values{"city": {"Atlantis"}, "frequency": {"daily"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "find weather for that city")
+
+	w = formRequest(router, http.MethodPost, path, url.Values{"city": {"Lviv"}, "frequency": {"alert"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestDeleteManagedSubscriptionHandler(t *testing.T) {
+	mockUC := new(MockManageUseCase)
+	id := uuid.New()
+	other := uuid.New()
+	mockUC.On("Delete", mock.Anything, "valid", id).Return(nil).Once()
+	mockUC.On("Delete", mock.Anything, "valid", other).Return(domain.ErrSubscriptionNotFound).Once()
+
+	router := setupManageRouter(mockUC)
+
+	w := formRequest(router, http.MethodPost, "/manage/valid/subscriptions/"+id.String()+"/delete", nil)
+	assert.Equal(t, http.StatusSeeOther, w.Code)
+	assert.Equal(t, "/manage/valid?status=deleted", w.Header().Get("Location"))
+
+	w = formRequest(router, http.MethodPost, "/manage/valid/subscriptions/"+other.String()+"/delete", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockUC.AssertExpectations(t)
+}
@@ -0,0 +1,221 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	usecase "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+const pauseDateLayout = "2006-01-02"
+
+var errInvalidPauseUntil = errors.New("until must be a date (YYYY-MM-DD) or an RFC 3339 timestamp")
+
+type PauseRequest struct {
+	Until string `form:"until" json:"until" example:"2025-07-01"`
+}
+
+type PauseResponse struct {
+	Message     string     `json:"message"`
+	PausedUntil *time.Time `json:"paused_until"`
+}
+
+// parseUntil accepts an RFC 3339 timestamp or a date, which means midnight
+// UTC at the start of that day. An empty value pauses indefinitely.
+func parseUntil(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	if until, err := time.Parse(time.RFC3339, value); err == nil {
+		return &until, nil
+	}
+
+	until, err := time.Parse(pauseDateLayout, value)
+	if err != nil {
+		return nil, errInvalidPauseUntil
+	}
+
+	return &until, nil
+}
+
+// @Summary Pause weather updates
+// @Description Pause a subscription until the given date, or indefinitely when until is omitted
+// @Tags subscription
+// @Accept json
+// @Produce json
+// @Param token path string true "Pause link token or unsubscribe token"
+// @Param request body PauseRequest false "Pause end"
+// @Success 200 {object} PauseResponse "Subscription paused"
+// @Failure 400 {object} map[string]string "Invalid link or pause end"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 410 {object} map[string]string "Pause link expired"
+// @Router /pause/{token} [post]
+func PauseHandler(uc usecase.PauseSubscriptionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Param("token")
+		var req PauseRequest
+		if err := c.ShouldBind(&req); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		until, err := parseUntil(req.Until)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		subscription, err := uc.Pause(c.Request.Context(), token, until)
+		if err != nil {
+			switch {
+			case errors.Is(err, domain.ErrSubscriptionNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case errors.Is(err, entity.ErrInvalidPause), errors.Is(err, domain.ErrInvalidActionLink):
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case errors.Is(err, domain.ErrActionLinkExpired):
+				c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, PauseResponse{Message: "Subscription paused", PausedUntil: subscription.PausedUntil})
+	}
+}
+
+// @Summary Resume weather updates
+// @Description Resume a paused subscription
+// @Tags subscription
+// @Produce json
+// @Param token path string true "Pause link token or unsubscribe token"
+// @Success 200 {object} map[string]string "Subscription resumed"
+// @Failure 400 {object} map[string]string "Invalid link"
+// @Failure 404 {object} map[string]string "Subscription not found"
+// @Failure 409 {object} map[string]string "Subscription is not paused"
+// @Failure 410 {object} map[string]string "Pause link expired"
+// @Router /resume/{token} [post]
+func ResumeHandler(uc usecase.ResumeSubscriptionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Param("token")
+		if _, err := uc.Resume(c.Request.Context(), token); err != nil {
+			switch err {
+			case domain.ErrSubscriptionNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case entity.ErrSubscriptionNotPaused:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			case domain.ErrInvalidActionLink:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			case domain.ErrActionLinkExpired:
+				c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Subscription resumed"})
+	}
+}
+
+func PausePageHandler(uc usecase.GetPauseStatusUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Param("token")
+		subscription, err := uc.Status(c.Request.Context(), token)
+		if err != nil {
+			renderPauseError(c, err)
+			return
+		}
+
+		renderPausePage(c, http.StatusOK, token, subscription, "", "")
+	}
+}
+
+func PauseFormHandler(statusUC usecase.GetPauseStatusUseCase, uc usecase.PauseSubscriptionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Param("token")
+		until, err := parseUntil(c.PostForm("until"))
+		if err != nil {
+			renderInvalidPause(c, statusUC, token)
+			return
+		}
+
+		subscription, err := uc.Pause(c.Request.Context(), token, until)
+		if err != nil {
+			if errors.Is(err, entity.ErrInvalidPause) {
+				renderInvalidPause(c, statusUC, token)
+			} else {
+				renderPauseError(c, err)
+			}
+			return
+		}
+
+		renderPausePage(c, http.StatusOK, token, subscription, "Your updates are paused.", "")
+	}
+}
+
+func ResumeFormHandler(statusUC usecase.GetPauseStatusUseCase, uc usecase.ResumeSubscriptionUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Param("token")
+		subscription, err := uc.Resume(c.Request.Context(), token)
+		if err == entity.ErrSubscriptionNotPaused {
+			subscription, err = statusUC.Status(c.Request.Context(), token)
+		}
+		if err != nil {
+			renderPauseError(c, err)
+			return
+		}
+
+		renderPausePage(c, http.StatusOK, token, subscription, "Your updates have resumed.", "")
+	}
+}
+
+func renderPausePage(c *gin.Context, status int, token string, s *entity.Subscription, notice, errMessage string) {
+	pausedUntil := ""
+	if s.PausedUntil != nil {
+		pausedUntil = s.PausedUntil.Format("January 2, 2006")
+	}
+
+	c.HTML(status, "pause.html", gin.H{
+		"Token":       token,
+		"City":        s.City,
+		"Frequency":   strings.ToLower(string(s.Frequency)),
+		"Paused":      s.IsPaused(),
+		"PausedUntil": pausedUntil,
+		"MinDate":     time.Now().UTC().AddDate(0, 0, 1).Format(pauseDateLayout),
+		"MaxDate":     time.Now().UTC().Add(entity.MaxPauseDuration).Format(pauseDateLayout),
+		"Notice":      notice,
+		"Error":       errMessage,
+	})
+}
+
+func renderInvalidPause(c *gin.Context, uc usecase.GetPauseStatusUseCase, token string) {
+	subscription, err := uc.Status(c.Request.Context(), token)
+	if err != nil {
+		renderPauseError(c, err)
+		return
+	}
+
+	renderPausePage(c, http.StatusBadRequest, token, subscription, "", "Please pick a date within the next year.")
+}
+
+func renderPauseError(c *gin.Context, err error) {
+	switch err {
+	case domain.ErrSubscriptionNotFound:
+		c.HTML(http.StatusNotFound, "404.html", gin.H{"Message": "Subscription not found"})
+	case domain.ErrInvalidActionLink:
+		c.HTML(http.StatusBadRequest, "404.html", gin.H{"Message": "Invalid link"})
+	case domain.ErrActionLinkExpired:
+		c.HTML(http.StatusGone, "404.html", gin.H{"Message": "This link has expired. Please use the link from a more recent email."})
+	default:
+		c.HTML(http.StatusInternalServerError, "404.html", gin.H{"Message": "Something went wrong, please try again later."})
+	}
+}
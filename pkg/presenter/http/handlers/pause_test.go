@@ -0,0 +1,167 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	"github.com/danik-tro/weather-subscriber/templates"
+)
+
+type MockPauseUseCase struct {
+	mock.Mock
+}
+
+func (m *MockPauseUseCase) Status(ctx context.Context, token string) (*entity.Subscription, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Subscription), args.Error(1)
+}
+
+func (m *MockPauseUseCase) Pause(ctx context.Context, token string, until *time.Time) (*entity.Subscription, error) {
+	args := m.Called(ctx, token, until)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Subscription), args.Error(1)
+}
+
+func (m *MockPauseUseCase) Resume(ctx context.Context, token string) (*entity.Subscription, error) {
+	args := m.Called(ctx, token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Subscription), args.Error(1)
+}
+
+func setupPauseRouter(uc *MockPauseUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.SetHTMLTemplate(template.Must(templates.Load("")))
+	r.POST("/api/pause/:token", PauseHandler(uc))
+	r.POST("/api/resume/:token", ResumeHandler(uc))
+	r.GET("/pause/:token", PausePageHandler(uc))
+	r.POST("/pause/:token", PauseFormHandler(uc, uc))
+	r.POST("/resume/:token", ResumeFormHandler(uc, uc))
+	return r
+}
+
+func jsonRequest(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestPauseHandler(t *testing.T) {
+	mockUC := new(MockPauseUseCase)
+	token := uuid.New().String()
+	until := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 30)
+	day := until.Format("2006-01-02")
+	paused, _ := entity.NewSubscription("user@example.com", "Kyiv", entity.FrequencyDaily)
+	_ = paused.Pause(time.Now(), &until)
+
+	mockUC.On("Pause", mock.Anything, token, &until).Return(paused, nil).Once()
+	mockUC.On("Pause", mock.Anything, token, (*time.Time)(nil)).Return(paused, nil).Once()
+	mockUC.On("Pause", mock.Anything, "not-a-token", (*time.Time)(nil)).Return(nil, domain.ErrInvalidActionLink).Once()
+	mockUC.On("Pause", mock.Anything, "expired-link", (*time.Time)(nil)).Return(nil, domain.ErrActionLinkExpired).Once()
+
+	router := setupPauseRouter(mockUC)
+
+	w := jsonRequest(router, http.MethodPost, "/api/pause/"+token, `{"until": "`+day+`"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"paused_until":"`+until.Format(time.RFC3339)+`"`)
+
+	w = jsonRequest(router, http.MethodPost, "/api/pause/"+token, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = jsonRequest(router, http.MethodPost, "/api/pause/"+token, `{"until": "next week"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = jsonRequest(router, http.MethodPost, "/api/pause/not-a-token", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = jsonRequest(router, http.MethodPost, "/api/pause/expired-link", "")
+	assert.Equal(t, http.StatusGone, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestResumeHandler(t *testing.T) {
+	mockUC := new(MockPauseUseCase)
+	token := uuid.New().String()
+	active := uuid.New().String()
+	missing := uuid.New().String()
+	subscription, _ := entity.NewSubscription("user@example.com", "Kyiv", entity.FrequencyDaily)
+
+	mockUC.On("Resume", mock.Anything, token).Return(subscription, nil).Once()
+	mockUC.On("Resume", mock.Anything, active).Return(nil, entity.ErrSubscriptionNotPaused).Once()
+	mockUC.On("Resume", mock.Anything, missing).Return(nil, domain.ErrSubscriptionNotFound).Once()
+
+	router := setupPauseRouter(mockUC)
+
+	w := jsonRequest(router, http.MethodPost, "/api/resume/"+token, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = jsonRequest(router, http.MethodPost, "/api/resume/"+active, "")
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = jsonRequest(router, http.MethodPost, "/api/resume/"+missing, "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestPausePages(t *testing.T) {
+	mockUC := new(MockPauseUseCase)
+	token := uuid.New().String()
+	active, _ := entity.NewSubscription("user@example.com", "Kyiv", entity.FrequencyDaily)
+	paused, _ := entity.NewSubscription("user@example.com", "Kyiv", entity.FrequencyDaily)
+	until := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 30)
+	day := until.Format("2006-01-02")
+	_ = paused.Pause(time.Now(), &until)
+
+	mockUC.On("Status", mock.Anything, token).Return(active, nil)
+	mockUC.On("Pause", mock.Anything, token, &until).Return(paused, nil).Once()
+	mockUC.On("Resume", mock.Anything, token).Return(active, nil).Once()
+	mockUC.On("Status", mock.Anything, "expired-link").Return(nil, domain.ErrActionLinkExpired).Once()
+
+	router := setupPauseRouter(mockUC)
+
+	w := formRequest(router, http.MethodGet, "/pause/"+token, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `action="/pause/`+token+`"`)
+
+	w = formRequest(router, http.MethodPost, "/pause/"+token, url.Values{"until": {day}})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), until.Format("January 2, 2006"))
+	assert.Contains(t, w.Body.String(), `action="/resume/`+token+`"`)
+
+	w = formRequest(router, http.MethodPost, "/pause/"+token, url.Values{"until": {"soon"}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "pick a date")
+
+	w = formRequest(router, http.MethodPost, "/resume/"+token, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "have resumed")
+
+	w = formRequest(router, http.MethodGet, "/pause/expired-link", nil)
+	assert.Equal(t, http.StatusGone, w.Code)
+	assert.Contains(t, w.Body.String(), "link has expired")
+	mockUC.AssertExpectations(t)
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"strings"
+	"time"
 
 	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
 	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
@@ -15,13 +16,53 @@ import (
 )
 
 type SubscribeRequest struct {
-	Email     string `form:"email" json:"email" binding:"required,email"`
-	City      string `form:"city"  json:"city"  binding:"required"`
-	Frequency string `form:"frequency" json:"frequency" binding:"required,oneof=hourly daily"`
+	Email     string            `form:"email" json:"email" binding:"required,email"`
+	City      string            `form:"city"  json:"city"  binding:"required"`
+	Frequency string            `form:"frequency" json:"frequency" binding:"required,oneof=hourly daily alert"`
+	Timezone  string            `form:"timezone" json:"timezone" binding:"omitempty,timezone"`
+	SendHour  *int              `form:"send_hour" json:"send_hour" binding:"omitempty,min=0,max=23"`
+	Alert     *AlertRuleRequest `json:"alert" binding:"required_if=Frequency alert,excluded_unless=Frequency alert"`
+	// WebhookURL is an https URL that receives hourly and daily digests as
+	// signed JSON instead of email.
+	WebhookURL string `form:"webhook_url" json:"webhook_url" binding:"omitempty,url,excluded_if=Frequency alert"`
+}
+
+func (r *SubscribeRequest) deliveryPreferences() entity.DeliveryPreferences {
+	prefs := entity.DefaultDeliveryPreferences()
+	prefs.Timezone = r.Timezone
+	if r.SendHour != nil {
+		prefs.SendHour = *r.SendHour
+	}
+	prefs.WebhookURL = r.WebhookURL
+	return prefs
+}
+
+type AlertRuleRequest struct {
+	Metric          string   `json:"metric" binding:"required,oneof=temperature humidity precipitation_chance"`
+	Comparator      string   `json:"comparator" binding:"required,oneof=lt lte gt gte"`
+	Threshold       *float64 `json:"threshold" binding:"required"`
+	CooldownMinutes *int     `json:"cooldown_minutes" binding:"omitempty,min=0"`
+}
+
+func (r *AlertRuleRequest) toEntity() entity.AlertRule {
+	cooldown := entity.DefaultAlertCooldown
+	if r.CooldownMinutes != nil {
+		cooldown = time.Duration(*r.CooldownMinutes) * time.Minute
+	}
+
+	return entity.AlertRule{
+		Metric:     entity.AlertMetric(strings.ToUpper(r.Metric)),
+		Comparator: entity.AlertComparator(strings.ToUpper(r.Comparator)),
+		Threshold:  *r.Threshold,
+		Cooldown:   cooldown,
+	}
 }
 
 // @Summary Subscribe to weather updates
-// @Description Subscribe to weather updates for a specific city and frequency
+// @Description Subscribe to weather updates for a specific city and frequency.
+// @Description Use frequency "alert" together with an "alert" rule to be notified only when the rule starts to hold.
+// @Description Daily digests are sent at "send_hour" (default 12) in "timezone", which defaults to the city's timezone.
+// @Description Set "webhook_url" to receive digests as JSON POSTs signed with HMAC-SHA256 in the X-Webhook-Signature header instead of email.
 // @Tags subscription
 // @Accept json
 // @Produce json
@@ -29,7 +70,8 @@ type SubscribeRequest struct {
 // @Success 200 {object} map[string]string "Subscription created and confirmation email sent"
 // @Failure 400 {object} map[string]string "Invalid request or validation errors"
 // @Failure 404 {object} map[string]string "City not found"
-// @Failure 409 {object} map[string]string "Subscription already exists"
+// @Failure 409 {object} map[string]string "Subscription already exists or is awaiting confirmation"
+// @Failure 422 {object} map[string]string "Email address bounced or reported spam and is suppressed"
 // @Router /subscribe [post]
 func SubscribeHandler(uc usecase.SubscribeWeatherUseCase) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -50,14 +92,29 @@ func SubscribeHandler(uc usecase.SubscribeWeatherUseCase) gin.HandlerFunc {
 
 		freq := entity.Frequency(strings.ToUpper(req.Frequency))
 
-		err := uc.Subscribe(c.Request.Context(), req.Email, req.City, freq)
+		var err error
+		if freq == entity.FrequencyAlert {
+			rule := req.Alert.toEntity()
+			if err := rule.Validate(); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+
+			err = uc.SubscribeToAlert(c.Request.Context(), req.Email, req.City, rule)
+		} else {
+			err = uc.Subscribe(c.Request.Context(), req.Email, req.City, freq, req.deliveryPreferences())
+		}
 
 		if err != nil {
 			switch err {
 			case domain.ErrSubscriptionAlreadyExists:
 				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			case domain.ErrSubscriptionPendingConfirmation:
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error() + ", use /api/subscribe/resend to get a new confirmation email"})
 			case domain.ErrCityNotFound:
 				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			case domain.ErrEmailSuppressed:
+				c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
 			default:
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			}
@@ -67,3 +124,46 @@ func SubscribeHandler(uc usecase.SubscribeWeatherUseCase) gin.HandlerFunc {
 		c.JSON(http.StatusOK, gin.H{"message": "subscription created, confirmation email sent"})
 	}
 }
+
+type ResendConfirmationRequest struct {
+	Email string `form:"email" json:"email" binding:"required,email"`
+	City  string `form:"city"  json:"city"  binding:"required"`
+}
+
+// @Summary Resend confirmation email
+// @Description Issue a new confirmation token for an unconfirmed subscription and email it again.
+// @Description The previous confirmation link stops working. Unknown, confirmed, suppressed and
+// @Description recently resent subscriptions get the same response as a sent email, so the
+// @Description endpoint cannot reveal who is subscribed.
+// @Tags subscription
+// @Accept json
+// @Produce json
+// @Param request body ResendConfirmationRequest true "Subscription to confirm"
+// @Success 200 {object} map[string]string "Confirmation email sent if a pending subscription exists"
+// @Failure 400 {object} map[string]string "Invalid request"
+// @Router /subscribe/resend [post]
+func ResendConfirmationHandler(uc usecase.ResendConfirmationUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req ResendConfirmationRequest
+		if err := c.ShouldBind(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		if err := uc.Resend(c.Request.Context(), req.Email, req.City); err != nil {
+			switch err {
+			// None of these may differ from a sent email, or the response
+			// would tell whether and how the address is subscribed.
+			case domain.ErrSubscriptionNotFound, entity.ErrAlreadyConfirmed, entity.ErrConfirmationResendTooSoon, domain.ErrEmailSuppressed:
+				c.JSON(http.StatusOK, gin.H{"message": resendConfirmationMessage})
+			default:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": resendConfirmationMessage})
+	}
+}
+
+const resendConfirmationMessage = "if a pending subscription exists, a confirmation email has been sent"
@@ -0,0 +1,196 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	usecase "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+)
+
+type MockSubscribeUseCase struct {
+	mock.Mock
+}
+
+func (m *MockSubscribeUseCase) Subscribe(ctx context.Context, email, city string, freq entity.Frequency, prefs entity.DeliveryPreferences) error {
+	args := m.Called(ctx, email, city, freq, prefs)
+	return args.Error(0)
+}
+
+func (m *MockSubscribeUseCase) SubscribeToAlert(ctx context.Context, email, city string, rule entity.AlertRule) error {
+	args := m.Called(ctx, email, city, rule)
+	return args.Error(0)
+}
+
+type MockResendConfirmationUseCase struct {
+	mock.Mock
+}
+
+func (m *MockResendConfirmationUseCase) Resend(ctx context.Context, email, city string) error {
+	args := m.Called(ctx, email, city)
+	return args.Error(0)
+}
+
+func setupSubscribeRouter(uc usecase.SubscribeWeatherUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/subscribe", SubscribeHandler(uc))
+	return r
+}
+
+func postSubscribe(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/subscribe", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSubscribeHandler_Daily(t *testing.T) {
+	mockUC := new(MockSubscribeUseCase)
+	mockUC.On("Subscribe", mock.Anything, "a@example.com", "Kyiv", entity.FrequencyDaily, entity.DefaultDeliveryPreferences()).Return(nil).Once()
+
+	w := postSubscribe(setupSubscribeRouter(mockUC), `{"email":"a@example.com","city":"Kyiv","frequency":"daily"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestSubscribeHandler_DeliveryPreferences(t *testing.T) {
+	mockUC := new(MockSubscribeUseCase)
+	prefs := entity.DeliveryPreferences{Timezone: "Asia/Tokyo", SendHour: 0}
+	mockUC.On("Subscribe", mock.Anything, "a@example.com", "Kyiv", entity.FrequencyDaily, prefs).Return(nil).Once()
+
+	router := setupSubscribeRouter(mockUC)
+	w := postSubscribe(router, `{"email":"a@example.com","city":"Kyiv","frequency":"daily","timezone":"Asia/Tokyo","send_hour":0}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+
+	w = postSubscribe(router, `{"email":"a@example.com","city":"Kyiv","frequency":"daily","timezone":"Mars/Olympus"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postSubscribe(router, `{"email":"a@example.com","city":"Kyiv","frequency":"daily","send_hour":24}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postSubscribe(router, `{"email":"a@example.com","city":"Kyiv","frequency":"daily","webhook_url":"not a url"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestSubscribeHandler_WebhookURL(t *testing.T) {
+	mockUC := new(MockSubscribeUseCase)
+	prefs := entity.DefaultDeliveryPreferences()
+	prefs.WebhookURL = "https://hooks.example.com/weather"
+	mockUC.On("Subscribe", mock.Anything, "a@example.com", "Kyiv", entity.FrequencyHourly, prefs).Return(nil).Once()
+
+	w := postSubscribe(setupSubscribeRouter(mockUC), `{"email":"a@example.com","city":"Kyiv","frequency":"hourly","webhook_url":"https://hooks.example.com/weather"}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestSubscribeHandler_Alert(t *testing.T) {
+	mockUC := new(MockSubscribeUseCase)
+	rule := entity.AlertRule{
+		Metric:     entity.AlertMetricTemperature,
+		Comparator: entity.ComparatorLessThan,
+		Threshold:  0,
+		Cooldown:   90 * time.Minute,
+	}
+	mockUC.On("SubscribeToAlert", mock.Anything, "a@example.com", "Kyiv", rule).Return(nil).Once()
+
+	w := postSubscribe(setupSubscribeRouter(mockUC), `{
+		"email": "a@example.com", "city": "Kyiv", "frequency": "alert",
+		"alert": {"metric": "temperature", "comparator": "lt", "threshold": 0, "cooldown_minutes": 90}
+	}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestSubscribeHandler_AlertValidation(t *testing.T) {
+	cases := map[string]string{
+		"missing rule":       `{"email":"a@example.com","city":"Kyiv","frequency":"alert"}`,
+		"rule on daily":      `{"email":"a@example.com","city":"Kyiv","frequency":"daily","alert":{"metric":"humidity","comparator":"gt","threshold":70}}`,
+		"unknown metric":     `{"email":"a@example.com","city":"Kyiv","frequency":"alert","alert":{"metric":"wind","comparator":"gt","threshold":70}}`,
+		"missing threshold":  `{"email":"a@example.com","city":"Kyiv","frequency":"alert","alert":{"metric":"humidity","comparator":"gt"}}`,
+		"threshold too high": `{"email":"a@example.com","city":"Kyiv","frequency":"alert","alert":{"metric":"precipitation_chance","comparator":"gt","threshold":170}}`,
+		"webhook on alert":   `{"email":"a@example.com","city":"Kyiv","frequency":"alert","webhook_url":"https://hooks.example.com/weather","alert":{"metric":"humidity","comparator":"gt","threshold":70}}`,
+	}
+
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			mockUC := new(MockSubscribeUseCase)
+
+			w := postSubscribe(setupSubscribeRouter(mockUC), body)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockUC.AssertNotCalled(t, "SubscribeToAlert", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockUC.AssertNotCalled(t, "Subscribe", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSubscribeHandler_PendingConfirmation(t *testing.T) {
+	mockUC := new(MockSubscribeUseCase)
+	mockUC.On("Subscribe", mock.Anything, "a@example.com", "Kyiv", entity.FrequencyDaily, entity.DefaultDeliveryPreferences()).
+		Return(domain.ErrSubscriptionPendingConfirmation).Once()
+
+	w := postSubscribe(setupSubscribeRouter(mockUC), `{"email":"a@example.com","city":"Kyiv","frequency":"daily"}`)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "/api/subscribe/resend")
+	mockUC.AssertExpectations(t)
+}
+
+func TestSubscribeHandler_SuppressedEmail(t *testing.T) {
+	mockUC := new(MockSubscribeUseCase)
+	mockUC.On("Subscribe", mock.Anything, "gone@example.com", "Kyiv", entity.FrequencyDaily, entity.DefaultDeliveryPreferences()).
+		Return(domain.ErrEmailSuppressed).Once()
+
+	w := postSubscribe(setupSubscribeRouter(mockUC), `{"email":"gone@example.com","city":"Kyiv","frequency":"daily"}`)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	mockUC.AssertExpectations(t)
+}
+
+func TestResendConfirmationHandler(t *testing.T) {
+	mockUC := new(MockResendConfirmationUseCase)
+	mockUC.On("Resend", mock.Anything, "a@example.com", "Kyiv").Return(nil).Once()
+	mockUC.On("Resend", mock.Anything, "b@example.com", "Kyiv").Return(domain.ErrSubscriptionNotFound).Once()
+	mockUC.On("Resend", mock.Anything, "c@example.com", "Kyiv").Return(entity.ErrAlreadyConfirmed).Once()
+	mockUC.On("Resend", mock.Anything, "d@example.com", "Kyiv").Return(entity.ErrConfirmationResendTooSoon).Once()
+	mockUC.On("Resend", mock.Anything, "e@example.com", "Kyiv").Return(domain.ErrEmailSuppressed).Once()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/subscribe/resend", ResendConfirmationHandler(mockUC))
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/subscribe/resend", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	sent := post(`{"email":"a@example.com","city":"Kyiv"}`)
+	assert.Equal(t, http.StatusOK, sent.Code)
+	// Unknown, confirmed, recently resent and suppressed addresses.
+	for _, email := range []string{"b@example.com", "c@example.com", "d@example.com", "e@example.com"} {
+		w := post(`{"email":"` + email + `","city":"Kyiv"}`)
+		assert.Equal(t, http.StatusOK, w.Code, email)
+		assert.Equal(t, sent.Body.String(), w.Body.String(), email)
+	}
+	assert.Equal(t, http.StatusBadRequest, post(`{"email":"not-an-email","city":"Kyiv"}`).Code)
+	mockUC.AssertExpectations(t)
+}
@@ -6,7 +6,6 @@ import (
 	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
 	usecase "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 )
 
 // @Summary Unsubscribe from weather updates
@@ -14,9 +13,9 @@ import (
 // @Tags subscription
 // @Accept json
 // @Produce json
-// @Param token path string true "Unsubscribe token"
+// @Param token path string true "Unsubscribe token or signed link token"
 // @Success 200 {object} map[string]string "Subscription unsubscribed"
-// @Failure 400 {object} map[string]string "Invalid token format or missing token"
+// @Failure 400 {object} map[string]string "Missing token or invalid link"
 // @Failure 404 {object} map[string]string "Subscription not found"
 // @Router /unsubscribe/{token} [post]
 func UnsubscribeHandler(uc usecase.UnsubscribeUseCase) gin.HandlerFunc {
@@ -27,16 +26,37 @@ func UnsubscribeHandler(uc usecase.UnsubscribeUseCase) gin.HandlerFunc {
 			return
 		}
 
-		if _, err := uuid.Parse(token); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid token format"})
+		err := uc.Unsubscribe(c.Request.Context(), token)
+		if err != nil {
+			switch err {
+			case domain.ErrSubscriptionNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			}
 			return
 		}
 
-		err := uc.Unsubscribe(c.Request.Context(), token)
+		c.JSON(http.StatusOK, gin.H{"message": "Subscription unsubscribed"})
+	}
+}
+
+// OneClickUnsubscribeHandler handles RFC 8058 one-click unsubscribes: mail
+// providers POST "List-Unsubscribe=One-Click" to the List-Unsubscribe URL
+// without a browser, so the token alone authorizes the request. Providers
+// may repeat the request, so an already removed subscription is a success.
+func OneClickUnsubscribeHandler(uc usecase.UnsubscribeUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.PostForm("List-Unsubscribe") != "One-Click" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expected a List-Unsubscribe=One-Click form body"})
+			return
+		}
+
+		err := uc.Unsubscribe(c.Request.Context(), c.Param("token"))
 		if err != nil {
 			switch err {
 			case domain.ErrSubscriptionNotFound:
-				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				c.JSON(http.StatusOK, gin.H{"message": "Subscription unsubscribed"})
 			default:
 				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			}
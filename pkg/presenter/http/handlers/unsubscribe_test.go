@@ -0,0 +1,63 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+type MockUnsubscribeUseCase struct {
+	mock.Mock
+}
+
+func (m *MockUnsubscribeUseCase) Unsubscribe(ctx context.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func setupUnsubscribeRouter(uc *MockUnsubscribeUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/unsubscribe/:token", OneClickUnsubscribeHandler(uc))
+	return r
+}
+
+func TestOneClickUnsubscribeHandler(t *testing.T) {
+	mockUC := new(MockUnsubscribeUseCase)
+	mockUC.On("Unsubscribe", mock.Anything, "valid-link").Return(nil).Twice()
+	mockUC.On("Unsubscribe", mock.Anything, "unsubscribed-link").Return(domain.ErrSubscriptionNotFound).Once()
+
+	router := setupUnsubscribeRouter(mockUC)
+	oneClick := url.Values{"List-Unsubscribe": {"One-Click"}}
+
+	w := formRequest(router, http.MethodPost, "/unsubscribe/valid-link", oneClick)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	require.NoError(t, writer.WriteField("List-Unsubscribe", "One-Click"))
+	require.NoError(t, writer.Close())
+	req := httptest.NewRequest(http.MethodPost, "/unsubscribe/valid-link", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = formRequest(router, http.MethodPost, "/unsubscribe/unsubscribed-link", oneClick)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = formRequest(router, http.MethodPost, "/unsubscribe/valid-link", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertExpectations(t)
+}
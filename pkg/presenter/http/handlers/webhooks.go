@@ -0,0 +1,72 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	usecase "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookEndpointResponse struct {
+	URL                 string     `json:"url"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	TotalFailures       int64      `json:"total_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	LastFailureAt       *time.Time `json:"last_failure_at,omitempty"`
+	LastSuccessAt       *time.Time `json:"last_success_at,omitempty"`
+	DisabledAt          *time.Time `json:"disabled_at,omitempty"`
+}
+
+type EnableWebhookEndpointQuery struct {
+	URL string `form:"url" binding:"required"`
+}
+
+func ListWebhookEndpointsHandler(uc usecase.ListWebhookEndpointsUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		endpoints, err := uc.List(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		items := make([]WebhookEndpointResponse, len(endpoints))
+		for i, e := range endpoints {
+			items[i] = WebhookEndpointResponse{
+				URL:                 e.URL,
+				ConsecutiveFailures: e.ConsecutiveFailures,
+				TotalFailures:       e.TotalFailures,
+				LastError:           e.LastError,
+				LastFailureAt:       e.LastFailureAt,
+				LastSuccessAt:       e.LastSuccessAt,
+				DisabledAt:          e.DisabledAt,
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"items": items})
+	}
+}
+
+func EnableWebhookEndpointHandler(uc usecase.EnableWebhookEndpointUseCase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query EnableWebhookEndpointQuery
+		if err := c.ShouldBindQuery(&query); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		err := uc.Enable(c.Request.Context(), query.URL)
+		if err != nil {
+			switch err {
+			case domain.ErrWebhookEndpointNotFound:
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Webhook endpoint enabled"})
+	}
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+)
+
+type MockWebhookEndpointsUseCase struct {
+	mock.Mock
+}
+
+func (m *MockWebhookEndpointsUseCase) List(ctx context.Context) ([]domain.WebhookEndpoint, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]domain.WebhookEndpoint), args.Error(1)
+}
+
+func (m *MockWebhookEndpointsUseCase) Enable(ctx context.Context, url string) error {
+	args := m.Called(ctx, url)
+	return args.Error(0)
+}
+
+func setupWebhooksRouter(uc *MockWebhookEndpointsUseCase) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	admin := r.Group("/admin", AdminAuthMiddleware(stubAPIKeyAuth{key: "secret"}))
+	admin.GET("/webhooks", ListWebhookEndpointsHandler(uc))
+	admin.POST("/webhooks/enable", EnableWebhookEndpointHandler(uc))
+	return r
+}
+
+func TestListWebhookEndpointsHandler(t *testing.T) {
+	mockUC := new(MockWebhookEndpointsUseCase)
+	disabledAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	mockUC.On("List", mock.Anything).Return([]domain.WebhookEndpoint{{
+		URL:                 "https://hooks.example.com/weather",
+		ConsecutiveFailures: 5,
+		TotalFailures:       7,
+		LastError:           "webhook returned HTTP 500: ",
+		LastFailureAt:       &disabledAt,
+		DisabledAt:          &disabledAt,
+	}}, nil).Once()
+
+	router := setupWebhooksRouter(mockUC)
+
+	w := adminRequest(router, http.MethodGet, "/admin/webhooks", "")
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = adminRequest(router, http.MethodGet, "/admin/webhooks", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"items":[{
+		"url":"https://hooks.example.com/weather",
+		"consecutive_failures":5,
+		"total_failures":7,
+		"last_error":"webhook returned HTTP 500: ",
+		"last_failure_at":"2026-10-18T09:00:00Z",
+		"disabled_at":"2026-10-18T09:00:00Z"
+	}]}`, w.Body.String())
+	mockUC.AssertExpectations(t)
+}
+
+func TestEnableWebhookEndpointHandler(t *testing.T) {
+	mockUC := new(MockWebhookEndpointsUseCase)
+	mockUC.On("Enable", mock.Anything, "https://hooks.example.com/weather?team=ops").Return(nil).Once()
+	mockUC.On("Enable", mock.Anything, "https://unknown.example.com").Return(domain.ErrWebhookEndpointNotFound).Once()
+
+	router := setupWebhooksRouter(mockUC)
+
+	w := adminRequest(router, http.MethodPost, "/admin/webhooks/enable?url="+url.QueryEscape("https://hooks.example.com/weather?team=ops"), "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = adminRequest(router, http.MethodPost, "/admin/webhooks/enable?url="+url.QueryEscape("https://unknown.example.com"), "secret")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = adminRequest(router, http.MethodPost, "/admin/webhooks/enable", "secret")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockUC.AssertExpectations(t)
+}
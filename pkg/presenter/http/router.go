@@ -1,6 +1,8 @@
 package http
 
 import (
+	"html/template"
+
 	config "github.com/danik-tro/weather-subscriber/pkg"
 	usecase "github.com/danik-tro/weather-subscriber/pkg/domain/usecases"
 	handlers "github.com/danik-tro/weather-subscriber/pkg/presenter/http/handlers"
@@ -10,9 +12,42 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func NewRouter(config config.Config, subscribeUC usecase.SubscribeWeatherUseCase, getWeatherUC usecase.GetWeatherUseCase, confirmUC usecase.ConfirmSubscriptionUseCase, unsubscribeUC usecase.UnsubscribeUseCase, checkTokensUC usecase.CheckTokensUseCase) *gin.Engine {
+// PauseUseCases backs the pause and resume endpoints, which are addressed by
+// the subscription's unsubscribe token.
+type PauseUseCases struct {
+	Status usecase.GetPauseStatusUseCase
+	Pause  usecase.PauseSubscriptionUseCase
+	Resume usecase.ResumeSubscriptionUseCase
+}
+
+// ManageUseCases backs the self-service /manage pages, which are reached
+// through emailed magic links.
+type ManageUseCases struct {
+	RequestLink usecase.RequestManageLinkUseCase
+	List        usecase.ListManagedSubscriptionsUseCase
+	Update      usecase.UpdateManagedSubscriptionUseCase
+	Delete      usecase.DeleteManagedSubscriptionUseCase
+}
+
+// AdminUseCases backs the /admin routes, which require an API key.
+type AdminUseCases struct {
+	AuthenticateAPIKey       usecase.AuthenticateAPIKeyUseCase
+	ListSubscriptions        usecase.ListSubscriptionsUseCase
+	GetSubscription          usecase.GetSubscriptionUseCase
+	SubscriptionHistory      usecase.SubscriptionHistoryUseCase
+	ForceConfirmSubscription usecase.ForceConfirmSubscriptionUseCase
+	DeleteSubscription       usecase.DeleteSubscriptionUseCase
+	SubscriptionStats        usecase.SubscriptionStatsUseCase
+	ListDeadLetters          usecase.ListDeadLettersUseCase
+	ReplayDeadLetter         usecase.ReplayDeadLetterUseCase
+	EmailPoolStats           usecase.EmailPoolStatsUseCase
+	ListWebhookEndpoints     usecase.ListWebhookEndpointsUseCase
+	EnableWebhookEndpoint    usecase.EnableWebhookEndpointUseCase
+}
+
+func NewRouter(config config.Config, pages *template.Template, subscribeUC usecase.SubscribeWeatherUseCase, resendConfirmationUC usecase.ResendConfirmationUseCase, getWeatherUC usecase.GetWeatherUseCase, getForecastUC usecase.GetForecastUseCase, confirmUC usecase.ConfirmSubscriptionUseCase, unsubscribeUC usecase.UnsubscribeUseCase, checkTokensUC usecase.CheckTokensUseCase, emailFeedbackUC usecase.ProcessEmailFeedbackUseCase, pause PauseUseCases, manage ManageUseCases, admin AdminUseCases) *gin.Engine {
 	router := gin.Default()
-	router.LoadHTMLGlob("templates/*")
+	router.SetHTMLTemplate(pages)
 
 	url := ginSwagger.URL(config.SwaggerURL)
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
@@ -20,13 +55,47 @@ func NewRouter(config config.Config, subscribeUC usecase.SubscribeWeatherUseCase
 	api := router.Group("/api")
 	{
 		api.POST("/subscribe", handlers.SubscribeHandler(subscribeUC))
+		api.POST("/subscribe/resend", handlers.ResendConfirmationHandler(resendConfirmationUC))
 		api.GET("/weather", handlers.GetWeatherHandler(getWeatherUC))
+		api.GET("/forecast", handlers.GetForecastHandler(getForecastUC))
 		api.POST("/confirm/:token", handlers.ConfirmHandler(confirmUC))
 		api.POST("/unsubscribe/:token", handlers.UnsubscribeHandler(unsubscribeUC))
+		api.POST("/pause/:token", handlers.PauseHandler(pause.Pause))
+		api.POST("/resume/:token", handlers.ResumeHandler(pause.Resume))
 	}
 
 	router.GET("/confirm/:token", handlers.CheckConfirmationTokenHandler(checkTokensUC))
 	router.GET("/unsubscribe/:token", handlers.CheckUnsubscribeTokenHandler(checkTokensUC))
+	router.POST("/unsubscribe/:token", handlers.OneClickUnsubscribeHandler(unsubscribeUC))
+
+	if config.EmailWebhookSecret != "" {
+		router.POST("/webhooks/email/:provider", handlers.EmailFeedbackWebhookHandler(emailFeedbackUC, config.EmailWebhookSecret))
+	}
+
+	router.GET("/pause/:token", handlers.PausePageHandler(pause.Status))
+	router.POST("/pause/:token", handlers.PauseFormHandler(pause.Status, pause.Pause))
+	router.POST("/resume/:token", handlers.ResumeFormHandler(pause.Status, pause.Resume))
+
+	router.GET("/manage", handlers.ManageRequestPageHandler())
+	router.POST("/manage", handlers.RequestManageLinkHandler(manage.RequestLink))
+	router.GET("/manage/:token", handlers.ManagePageHandler(manage.List))
+	router.POST("/manage/:token/subscriptions/:id", handlers.UpdateManagedSubscriptionHandler(manage.List, manage.Update))
+	router.POST("/manage/:token/subscriptions/:id/delete", handlers.DeleteManagedSubscriptionHandler(manage.Delete))
+
+	adminGroup := router.Group("/admin", handlers.AdminAuthMiddleware(admin.AuthenticateAPIKey))
+	{
+		adminGroup.GET("/subscriptions", handlers.ListSubscriptionsHandler(admin.ListSubscriptions))
+		adminGroup.GET("/subscriptions/history", handlers.SubscriptionHistoryHandler(admin.SubscriptionHistory))
+		adminGroup.GET("/subscriptions/:id", handlers.GetSubscriptionHandler(admin.GetSubscription))
+		adminGroup.POST("/subscriptions/:id/confirm", handlers.ForceConfirmSubscriptionHandler(admin.ForceConfirmSubscription))
+		adminGroup.POST("/subscriptions/:id/unsubscribe", handlers.ForceUnsubscribeHandler(admin.DeleteSubscription))
+		adminGroup.GET("/stats", handlers.SubscriptionStatsHandler(admin.SubscriptionStats))
+		adminGroup.GET("/dead-letters", handlers.ListDeadLettersHandler(admin.ListDeadLetters))
+		adminGroup.POST("/dead-letters/:id/replay", handlers.ReplayDeadLetterHandler(admin.ReplayDeadLetter))
+		adminGroup.GET("/email/pool", handlers.EmailPoolStatsHandler(admin.EmailPoolStats))
+		adminGroup.GET("/webhooks", handlers.ListWebhookEndpointsHandler(admin.ListWebhookEndpoints))
+		adminGroup.POST("/webhooks/enable", handlers.EnableWebhookEndpointHandler(admin.EnableWebhookEndpoint))
+	}
 
 	return router
 }
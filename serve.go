@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	config "github.com/danik-tro/weather-subscriber/pkg"
+	domain "github.com/danik-tro/weather-subscriber/pkg/domain"
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	domain_repository "github.com/danik-tro/weather-subscriber/pkg/domain/repository"
+	"github.com/danik-tro/weather-subscriber/pkg/external/weather"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/background_job"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/db"
+	smtp "github.com/danik-tro/weather-subscriber/pkg/infrastructure/email_service"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/email_service/httpmail"
+	events "github.com/danik-tro/weather-subscriber/pkg/infrastructure/events"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/notification"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/tokens"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/usecases"
+	"github.com/danik-tro/weather-subscriber/pkg/presenter/http"
+	"github.com/danik-tro/weather-subscriber/templates"
+)
+
+func serveCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "run the HTTP server, event handlers and scheduled jobs",
+		Action: withConfig(func(c *cli.Context, config *config.Config) error {
+			return runServe(config)
+		}),
+	}
+}
+
+func runServe(config *config.Config) error {
+	retryPolicies := events.RetryPolicies{
+		// Confirmation emails are worth retrying through longer SMTP outages.
+		domain.UserSubscribed: {
+			MaxAttempts:    8,
+			InitialBackoff: 30 * time.Second,
+			MaxBackoff:     30 * time.Minute,
+			Multiplier:     2,
+			Jitter:         0.2,
+		},
+	}
+
+	weatherService, err := newWeatherService(config)
+	if err != nil {
+		return err
+	}
+
+	gormDb, err := db.NewGormConnection(config)
+	if err != nil {
+		return err
+	}
+
+	repository := db.NewGormRepository(gormDb)
+	outboxRepository := db.NewGormOutboxRepository(gormDb)
+	deliveryRepository := db.NewGormDeliveryRepository(gormDb)
+	deadLetterRepository := db.NewGormDeadLetterRepository(gormDb)
+	apiKeyRepository := db.NewGormAPIKeyRepository(gormDb)
+	suppressionRepository := db.NewGormSuppressionRepository(gormDb)
+	webhookEndpointRepository := db.NewGormWebhookEndpointRepository(gormDb)
+
+	var publisher events.Bus
+	switch config.EventBus {
+	case "redis":
+		redisPublisher, err := events.NewRedisStreamsPublisher(config.RedisAddress, config.RedisPassword, config.RedisDB, events.RedisStreamsConfig{
+			Prefix:   redisPrefix,
+			Group:    config.EventStreamGroup,
+			Consumer: config.EventStreamConsumer,
+		})
+		if err != nil {
+			return err
+		}
+		redisPublisher.UseRetries(retryPolicies, deadLetterRepository)
+		publisher = redisPublisher
+	default:
+		// The outbox relay runs the handlers and retries failed messages.
+		publisher = events.NewPublisher(workers, bufferSize)
+	}
+
+	if config.DBAutoMigrate {
+		sqlDb, err := gormDb.DB()
+		if err != nil {
+			return err
+		}
+
+		migrator, err := db.NewMigrator(sqlDb)
+		if err != nil {
+			return err
+		}
+
+		applied, err := migrator.Up(context.Background())
+		if err != nil {
+			return err
+		}
+		log.Printf("Applied %d migrations", applied)
+	}
+
+	unsubscribeTokens := tokens.NewHMACUnsubscribeTokens(config.UnsubscribeTokenSecret)
+	actionLinks, err := tokens.NewHMACActionLinks(config.ActionLinkKeys)
+	if err != nil {
+		return fmt.Errorf("invalid ACTION_LINK_KEYS: %w", err)
+	}
+
+	// Subscriptions from before unsubscribe tokens were derived need the
+	// digest of their new token before any email links to it.
+	backfilled, err := usecases.NewBackfillUnsubscribeTokensUseCase(repository, unsubscribeTokens).Backfill(context.Background())
+	if err != nil {
+		return err
+	}
+	if backfilled > 0 {
+		log.Printf("Stored unsubscribe token digests for %d subscriptions", backfilled)
+	}
+
+	getWeatherUC := usecases.NewGetWeatherUseCase(*weatherService)
+	getForecastUC := usecases.NewGetForecastUseCase(*weatherService)
+	subscribeUC := usecases.NewSubscribeWeatherUseCase(repository, *weatherService, unsubscribeTokens, suppressionRepository)
+	resendConfirmationUC := usecases.NewResendConfirmationUseCase(repository, suppressionRepository)
+	confirmUC := usecases.NewConfirmSubscription(repository, config.ConfirmationTokenTTL, actionLinks)
+	unsubscribeUC := usecases.NewUnsubscribeUseCase(repository, actionLinks)
+	checkTokensUC := usecases.NewCheckTokens(repository, config.ConfirmationTokenTTL, actionLinks)
+
+	manageTokens := tokens.NewHMACManageTokens(config.ManageTokenSecret, config.ManageLinkTTL)
+
+	emailService, emailTransport, closeEmailService, err := newFilteredEmailService(config, suppressionRepository)
+	if err != nil {
+		return err
+	}
+	defer closeEmailService()
+
+	templateSet, err := templates.Load(config.TemplatesDir)
+	if err != nil {
+		return err
+	}
+
+	router := http.NewRouter(*config, templateSet, subscribeUC, resendConfirmationUC, getWeatherUC, getForecastUC, confirmUC, unsubscribeUC, checkTokensUC, usecases.NewProcessEmailFeedbackUseCase(suppressionRepository), http.PauseUseCases{
+		Status: usecases.NewGetPauseStatusUseCase(repository, actionLinks),
+		Pause:  usecases.NewPauseSubscriptionUseCase(repository, actionLinks),
+		Resume: usecases.NewResumeSubscriptionUseCase(repository, actionLinks),
+	}, http.ManageUseCases{
+		RequestLink: usecases.NewRequestManageLinkUseCase(repository, manageTokens),
+		List:        usecases.NewListManagedSubscriptionsUseCase(repository, manageTokens),
+		Update:      usecases.NewUpdateManagedSubscriptionUseCase(repository, manageTokens, *weatherService),
+		Delete:      usecases.NewDeleteManagedSubscriptionUseCase(repository, manageTokens),
+	}, http.AdminUseCases{
+		AuthenticateAPIKey:       usecases.NewAuthenticateAPIKeyUseCase(apiKeyRepository),
+		ListSubscriptions:        usecases.NewListSubscriptionsUseCase(repository),
+		GetSubscription:          usecases.NewGetSubscriptionUseCase(repository),
+		SubscriptionHistory:      usecases.NewSubscriptionHistoryUseCase(repository),
+		ForceConfirmSubscription: usecases.NewForceConfirmSubscriptionUseCase(repository),
+		DeleteSubscription:       usecases.NewDeleteSubscriptionUseCase(repository),
+		SubscriptionStats:        usecases.NewSubscriptionStatsUseCase(repository),
+		ListDeadLetters:          usecases.NewListDeadLettersUseCase(deadLetterRepository),
+		ReplayDeadLetter:         usecases.NewReplayDeadLetterUseCase(deadLetterRepository),
+		EmailPoolStats:           usecases.NewEmailPoolStatsUseCase(emailTransport),
+		ListWebhookEndpoints:     usecases.NewListWebhookEndpointsUseCase(webhookEndpointRepository),
+		EnableWebhookEndpoint:    usecases.NewEnableWebhookEndpointUseCase(webhookEndpointRepository),
+	})
+
+	handler := events.Handler{
+		EmailService:   emailService,
+		WeatherService: weatherService,
+		Repository:     repository,
+		Deliveries:     deliveryRepository,
+		Config:         *config,
+		Templates:      templateSet,
+		ActionLinks:    actionLinks,
+		DigestWorkers:  config.DigestWorkers,
+		Webhooks:       newWebhookChannel(config, webhookEndpointRepository),
+	}
+
+	publisher.Register(domain.UserSubscribed, handler.UserSubscribed())
+	publisher.Register(domain.ManageLinkRequested, handler.ManageLinkRequested())
+	publisher.Register(domain.SubscriptionResumed, handler.SubscriptionResumed())
+
+	backgroundJobService := background_job.NewCronBackgroundJobService()
+
+	switch config.JobLock {
+	case "postgres":
+		sqlDb, err := gormDb.DB()
+		if err != nil {
+			return err
+		}
+		backgroundJobService.UseLock(background_job.NewPostgresLocker(sqlDb), config.InstanceID)
+	case "redis":
+		locker, err := background_job.NewRedisLocker(config.RedisAddress, config.RedisPassword, config.RedisDB, redisPrefix, config.InstanceID)
+		if err != nil {
+			return err
+		}
+		defer locker.Close()
+		backgroundJobService.UseLock(locker, config.InstanceID)
+	}
+
+	if err := backgroundJobService.Start(); err != nil {
+		return err
+	}
+	defer backgroundJobService.Stop()
+
+	// Daily digests go out at each subscriber's local send hour, so the job
+	// runs frequently and only dispatches subscriptions that are due.
+	if err := backgroundJobService.AddExclusiveJob("daily_digest", "0 */5 * * * *", func(ctx context.Context) {
+		event := domain.Event{
+			Type: domain.WeatherEvent,
+		}
+		if err := handler.FetchAndUpdateWeatherSubscribers(entity.FrequencyDaily)(ctx, event); err != nil {
+			log.Printf("Failed to process daily weather updates: %v", err)
+		}
+	}); err != nil {
+		return err
+	}
+
+	if err := backgroundJobService.AddExclusiveJob("hourly_digest", "0 0 * * * *", func(ctx context.Context) {
+		event := domain.Event{
+			Type: domain.WeatherEvent,
+		}
+		if err := handler.FetchAndUpdateWeatherSubscribers(entity.FrequencyHourly)(ctx, event); err != nil {
+			log.Printf("Failed to process hourly weather updates: %v", err)
+		}
+	}); err != nil {
+		return err
+	}
+
+	if err := backgroundJobService.AddExclusiveJob("weather_alerts", "0 */15 * * * *", func(ctx context.Context) {
+		event := domain.Event{
+			Type: domain.WeatherEvent,
+		}
+		if err := handler.EvaluateAlertSubscriptions()(ctx, event); err != nil {
+			log.Printf("Failed to evaluate weather alerts: %v", err)
+		}
+	}); err != nil {
+		return err
+	}
+
+	resumeExpiredPausesUC := usecases.NewResumeExpiredPausesUseCase(repository)
+	if err := backgroundJobService.AddExclusiveJob("resume_paused", "0 */5 * * * *", func(ctx context.Context) {
+		if _, err := resumeExpiredPausesUC.ResumeExpired(ctx); err != nil {
+			log.Printf("Failed to resume paused subscriptions: %v", err)
+		}
+	}); err != nil {
+		return err
+	}
+
+	purgeUnconfirmedUC := usecases.NewPurgeUnconfirmedSubscriptionsUseCase(repository, config.UnconfirmedRetention())
+	if err := backgroundJobService.AddExclusiveJob("purge_unconfirmed", "0 30 3 * * *", func(ctx context.Context) {
+		if _, err := purgeUnconfirmedUC.Purge(ctx); err != nil {
+			log.Printf("Failed to purge unconfirmed subscriptions: %v", err)
+		}
+	}); err != nil {
+		return err
+	}
+
+	if config.BounceMailboxDir != "" {
+		processBounceMailboxUC := usecases.NewProcessBounceMailboxUseCase(suppressionRepository, config.BounceMailboxDir)
+		if err := backgroundJobService.AddExclusiveJob("bounce_mailbox", "0 * * * * *", func(ctx context.Context) {
+			if _, err := processBounceMailboxUC.ProcessMailbox(ctx); err != nil {
+				log.Printf("Failed to process bounce mailbox: %v", err)
+			}
+		}); err != nil {
+			return err
+		}
+	}
+
+	publisher.Start()
+	defer publisher.Close()
+
+	outboxRelay := events.NewOutboxRelay(outboxRepository, deadLetterRepository, publisher, retryPolicies, outboxPollInterval, outboxBatchSize)
+	outboxRelay.Start()
+	defer outboxRelay.Close()
+
+	return router.Run(fmt.Sprintf("%s:%d", config.AppHost, config.AppPort))
+}
+
+func newWeatherService(config *config.Config) (*weather.WeatherService, error) {
+	weatherClient, err := weather.NewProviderRegistry().BuildChain(
+		config.WeatherProviders(),
+		map[string]weather.ProviderConfig{
+			weather.ProviderWeatherAPI:     {APIKey: config.WeatherAPIKey},
+			weather.ProviderOpenWeatherMap: {APIKey: config.OpenWeatherMapAPIKey},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	weatherCache, err := weather.NewWeatherCache(config.RedisAddress, config.RedisPassword, config.RedisDB, redisPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return weather.NewWeatherService(weatherClient, weatherCache), nil
+}
+
+// newWebhookChannel returns nil while WEBHOOK_SIGNING_SECRET is unset, so
+// digests for webhook subscribers fail instead of going out unsigned.
+func newWebhookChannel(config *config.Config, endpoints domain_repository.WebhookEndpointRepository) domain.NotificationChannel {
+	if config.WebhookSigningSecret == "" {
+		return nil
+	}
+
+	return notification.NewWebhookChannel(notification.WebhookConfig{
+		Secret:       config.WebhookSigningSecret,
+		Timeout:      config.WebhookTimeout,
+		MaxAttempts:  config.WebhookMaxAttempts,
+		DisableAfter: config.WebhookDisableAfter,
+	}, endpoints)
+}
+
+// newFilteredEmailService returns the configured transport behind the
+// suppression filter, the bare transport for its stats, and a function that
+// closes the transport's pooled connections.
+func newFilteredEmailService(config *config.Config, suppressions domain_repository.SuppressionRepository) (domain.EmailService, domain.EmailService, func(), error) {
+	transport, err := newEmailService(config)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	closeTransport := func() {}
+	if closer, ok := transport.(io.Closer); ok {
+		closeTransport = func() { closer.Close() }
+	}
+
+	return smtp.NewSuppressionFilter(transport, suppressions), transport, closeTransport, nil
+}
+
+func newEmailService(config *config.Config) (domain.EmailService, error) {
+	switch config.EmailTransport {
+	case "http":
+		return httpmail.NewAPIClient(httpmail.APIConfig{
+			URL:    config.EmailAPIURL,
+			APIKey: config.EmailAPIKey,
+			From:   config.EmailFrom,
+		}), nil
+	case "ses":
+		return httpmail.NewSESClient(httpmail.SESConfig{
+			Region:          config.SESRegion,
+			AccessKeyID:     config.SESAccessKeyID,
+			SecretAccessKey: config.SESSecretAccessKey,
+			Endpoint:        config.SESEndpoint,
+			From:            config.EmailFrom,
+		}), nil
+	case "file":
+		return smtp.NewFileSink(config.EmailFileDir, config.EmailFrom)
+	default:
+		return smtp.NewPool(smtp.PoolConfig{
+			SMTPConfig: smtp.SMTPConfig{
+				Host:     config.SMTPHost,
+				Port:     config.SMTPPort,
+				Username: config.SMTPUsername,
+				Password: config.SMTPPassword,
+				From:     config.EmailFrom,
+			},
+			MaxConnections:  config.SMTPPoolSize,
+			IdleTimeout:     config.SMTPIdleTimeout,
+			RateLimit:       config.SMTPRateLimit,
+			DomainRateLimit: config.SMTPDomainRateLimit,
+		}), nil
+	}
+}
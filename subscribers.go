@@ -0,0 +1,198 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
+
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/db"
+	"github.com/danik-tro/weather-subscriber/pkg/infrastructure/usecases"
+)
+
+func subscribersCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "subscribers",
+		Usage: "inspect and remove subscriptions",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "list subscriptions, newest first",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "city", Usage: "only subscriptions for `CITY`"},
+					&cli.StringFlag{Name: "frequency", Usage: "only subscriptions with `FREQUENCY` (hourly, daily or alert)"},
+					&cli.BoolFlag{Name: "confirmed", Usage: "only confirmed subscriptions; --confirmed=false lists unconfirmed ones"},
+					&cli.IntFlag{Name: "limit", Value: 50, Usage: "maximum number of subscriptions to list"},
+					&cli.IntFlag{Name: "offset", Usage: "number of subscriptions to skip"},
+				},
+				Action: withRepository(runSubscribersList),
+			},
+			{
+				Name:      "show",
+				Usage:     "show a single subscription",
+				ArgsUsage: "<id>",
+				Action:    withRepository(runSubscribersShow),
+			},
+			{
+				Name:  "history",
+				Usage: "list every subscription an address has had to a city, including unsubscribed ones",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "email", Required: true, Usage: "subscriber `EMAIL`"},
+					&cli.StringFlag{Name: "city", Required: true, Usage: "subscribed `CITY`"},
+				},
+				Action: withRepository(runSubscribersHistory),
+			},
+			{
+				Name:      "delete",
+				Usage:     "delete a subscription",
+				ArgsUsage: "<id>",
+				Action:    withRepository(runSubscribersDelete),
+			},
+		},
+	}
+}
+
+func runSubscribersList(c *cli.Context, repository *db.GormRepository) error {
+	filter, err := subscriptionFilter(c)
+	if err != nil {
+		return err
+	}
+
+	subscriptions, total, err := usecases.NewListSubscriptionsUseCase(repository).List(c.Context, filter)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tEMAIL\tCITY\tFREQUENCY\tCONFIRMED\tCREATED AT")
+	for _, s := range subscriptions {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\t%s\n", s.ID, s.Email, s.City, s.Frequency, s.Confirmed, s.CreatedAt.Format(timeFormat))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nShowing %d of %d subscriptions\n", len(subscriptions), total)
+	return nil
+}
+
+func runSubscribersShow(c *cli.Context, repository *db.GormRepository) error {
+	id, err := subscriptionID(c)
+	if err != nil {
+		return err
+	}
+
+	s, err := usecases.NewGetSubscriptionUseCase(repository).Get(c.Context, id)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%s\n", s.ID)
+	fmt.Fprintf(w, "Email:\t%s\n", s.Email)
+	fmt.Fprintf(w, "City:\t%s\n", s.City)
+	fmt.Fprintf(w, "Frequency:\t%s\n", s.Frequency)
+	fmt.Fprintf(w, "Confirmed:\t%t\n", s.Confirmed)
+	fmt.Fprintf(w, "Created at:\t%s\n", s.CreatedAt.Format(timeFormat))
+	fmt.Fprintf(w, "Confirmed at:\t%s\n", formatOptionalTime(s.ConfirmedAt))
+	fmt.Fprintf(w, "Last sent at:\t%s\n", formatOptionalTime(s.LastSentAt))
+	fmt.Fprintf(w, "Timezone:\t%s\n", s.Timezone)
+	fmt.Fprintf(w, "Send hour:\t%d\n", s.SendHour)
+	if s.AlertRule != nil {
+		fmt.Fprintf(w, "Alert rule:\t%s %s %g\n", s.AlertRule.Metric, s.AlertRule.Comparator, s.AlertRule.Threshold)
+		fmt.Fprintf(w, "Alert triggered:\t%t\n", s.AlertTriggered)
+	}
+	return w.Flush()
+}
+
+func runSubscribersHistory(c *cli.Context, repository *db.GormRepository) error {
+	history, err := usecases.NewSubscriptionHistoryUseCase(repository).History(c.Context, c.String("email"), c.String("city"))
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tFREQUENCY\tCONFIRMED\tCREATED AT\tUNSUBSCRIBED AT")
+	for _, s := range history.Subscriptions {
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\n", s.ID, s.Frequency, s.Confirmed, s.CreatedAt.Format(timeFormat), formatOptionalTime(s.UnsubscribedAt))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%d subscriptions, %d unsubscribed\n", len(history.Subscriptions), history.Unsubscribes())
+	return nil
+}
+
+func runSubscribersDelete(c *cli.Context, repository *db.GormRepository) error {
+	id, err := subscriptionID(c)
+	if err != nil {
+		return err
+	}
+
+	if err := usecases.NewDeleteSubscriptionUseCase(repository).Delete(c.Context, id); err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted subscription %s\n", id)
+	return nil
+}
+
+func subscriptionFilter(c *cli.Context) (entity.SubscriptionFilter, error) {
+	filter := entity.SubscriptionFilter{
+		City:   c.String("city"),
+		Limit:  c.Int("limit"),
+		Offset: c.Int("offset"),
+	}
+
+	if c.IsSet("frequency") {
+		frequency := entity.Frequency(strings.ToUpper(c.String("frequency")))
+		switch frequency {
+		case entity.FrequencyHourly, entity.FrequencyDaily, entity.FrequencyAlert:
+			filter.Frequency = frequency
+		default:
+			return filter, fmt.Errorf("unknown frequency %q", c.String("frequency"))
+		}
+	}
+
+	if c.IsSet("confirmed") {
+		confirmed := c.Bool("confirmed")
+		filter.Confirmed = &confirmed
+	}
+
+	return filter, nil
+}
+
+func subscriptionID(c *cli.Context) (uuid.UUID, error) {
+	if !c.Args().Present() {
+		return uuid.Nil, errors.New("subscription id is required")
+	}
+
+	id, err := uuid.Parse(c.Args().First())
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid subscription id: %w", err)
+	}
+
+	return id, nil
+}
+
+func formatOptionalTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(timeFormat)
+}
+
+// withRepository opens a database connection for the duration of action.
+func withRepository(action func(c *cli.Context, repository *db.GormRepository) error) cli.ActionFunc {
+	return withDatabase(func(c *cli.Context, gormDb *gorm.DB) error {
+		return action(c, db.NewGormRepository(gormDb))
+	})
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+
+	entity "github.com/danik-tro/weather-subscriber/pkg/domain/entity"
+)
+
+func parseFilter(t *testing.T, args ...string) (entity.SubscriptionFilter, error) {
+	t.Helper()
+
+	var filter entity.SubscriptionFilter
+	var filterErr error
+
+	list := subscribersCommand().Subcommands[0]
+	list.Action = func(c *cli.Context) error {
+		filter, filterErr = subscriptionFilter(c)
+		return nil
+	}
+
+	app := &cli.App{Commands: []*cli.Command{list}}
+	require.NoError(t, app.Run(append([]string{"app", "list"}, args...)))
+
+	return filter, filterErr
+}
+
+func TestSubscriptionFilter(t *testing.T) {
+	t.Run("defaults match everything", func(t *testing.T) {
+		filter, err := parseFilter(t)
+		require.NoError(t, err)
+
+		assert.Equal(t, entity.SubscriptionFilter{Limit: 50}, filter)
+	})
+
+	t.Run("applies flags", func(t *testing.T) {
+		filter, err := parseFilter(t, "--city", "Kyiv", "--frequency", "hourly", "--confirmed=false", "--limit", "10", "--offset", "20")
+		require.NoError(t, err)
+
+		assert.Equal(t, "Kyiv", filter.City)
+		assert.Equal(t, entity.FrequencyHourly, filter.Frequency)
+		require.NotNil(t, filter.Confirmed)
+		assert.False(t, *filter.Confirmed)
+		assert.Equal(t, 10, filter.Limit)
+		assert.Equal(t, 20, filter.Offset)
+	})
+
+	t.Run("rejects unknown frequency", func(t *testing.T) {
+		_, err := parseFilter(t, "--frequency", "weekly")
+		assert.Error(t, err)
+	})
+}
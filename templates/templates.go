@@ -0,0 +1,51 @@
+// Package templates holds the HTML pages and email templates. They are
+// compiled into the binary, so it runs from any working directory.
+package templates
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+)
+
+const pattern = "*.html"
+
+//go:embed *.html
+var files embed.FS
+
+// Load parses the embedded templates, each named after its file. If
+// overrideDir is set, its .html files are parsed on top and replace the
+// embedded templates with the same name, so operators can customise some
+// templates and keep the rest.
+func Load(overrideDir string) (*template.Template, error) {
+	tmpl, err := template.New("").ParseFS(files, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse embedded templates: %w", err)
+	}
+
+	if overrideDir == "" {
+		return tmpl, nil
+	}
+
+	overrides := os.DirFS(overrideDir)
+	if _, err := fs.Stat(overrides, "."); err != nil {
+		return nil, fmt.Errorf("failed to open templates directory: %w", err)
+	}
+
+	matches, err := fs.Glob(overrides, pattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates in %s: %w", overrideDir, err)
+	}
+
+	if len(matches) == 0 {
+		return tmpl, nil
+	}
+
+	if _, err := tmpl.ParseFS(overrides, matches...); err != nil {
+		return nil, fmt.Errorf("failed to parse templates in %s: %w", overrideDir, err)
+	}
+
+	return tmpl, nil
+}
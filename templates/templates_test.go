@@ -0,0 +1,44 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad(t *testing.T) {
+	tmpl, err := Load("")
+	require.NoError(t, err)
+
+	for _, name := range []string{"404.html", "confirmation.html", "weather_update.html", "weather_alert.html"} {
+		assert.NotNil(t, tmpl.Lookup(name), name)
+	}
+}
+
+func TestLoadOverrides(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "confirmation.html"), []byte(`<p>Custom {{.ConfirmURL}}</p>`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(`ignored`), 0o644))
+
+	tmpl, err := Load(dir)
+	require.NoError(t, err)
+
+	var body strings.Builder
+	require.NoError(t, tmpl.ExecuteTemplate(&body, "confirmation.html", map[string]string{"ConfirmURL": "https://example.com/confirm"}))
+	assert.Equal(t, `<p>Custom https://example.com/confirm</p>`, body.String())
+	assert.NotNil(t, tmpl.Lookup("weather_update.html"), "templates that are not overridden stay embedded")
+}
+
+func TestLoadRejectsInvalidOverrides(t *testing.T) {
+	_, err := Load(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "404.html"), []byte(`{{.Message`), 0o644))
+	_, err = Load(dir)
+	assert.Error(t, err)
+}
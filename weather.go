@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/urfave/cli/v2"
+
+	config "github.com/danik-tro/weather-subscriber/pkg"
+)
+
+func weatherCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "weather",
+		Usage: "query the configured weather providers",
+		Subcommands: []*cli.Command{
+			{
+				Name:  "get",
+				Usage: "print the current weather and, with --days, the forecast for a city",
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "city", Required: true, Usage: "`CITY` to look up"},
+					&cli.IntFlag{Name: "days", Usage: "number of forecast days to include"},
+				},
+				Action: withConfig(runWeatherGet),
+			},
+		},
+	}
+}
+
+func runWeatherGet(c *cli.Context, config *config.Config) error {
+	weatherService, err := newWeatherService(config)
+	if err != nil {
+		return err
+	}
+
+	city := c.String("city")
+
+	current, err := weatherService.GetWeather(c.Context, city)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "City:\t%s\n", city)
+	fmt.Fprintf(w, "Temperature:\t%.1f°C\n", current.Temperature)
+	fmt.Fprintf(w, "Humidity:\t%.0f%%\n", current.Humidity)
+	fmt.Fprintf(w, "Description:\t%s\n", current.Description)
+
+	if days := c.Int("days"); days > 0 {
+		forecast, err := weatherService.GetForecast(c.Context, city, days)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "DATE\tMIN\tMAX\tPRECIPITATION\tDESCRIPTION")
+		for _, day := range forecast.Days {
+			fmt.Fprintf(w, "%s\t%.1f°C\t%.1f°C\t%.0f%%\t%s\n", day.Date, day.MinTemperature, day.MaxTemperature, day.PrecipitationChance, day.Description)
+		}
+	}
+
+	return w.Flush()
+}